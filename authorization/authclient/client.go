@@ -0,0 +1,52 @@
+package authclient
+
+import (
+	"context"
+	"fmt"
+
+	auth "github.com/go-park-mail-ru/2023_2_Vkladyshi/authorization/proto"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Client is the connection of the films and comments services to the
+// authorization service.
+type Client struct {
+	auth.AuthorizationClient
+	conn *grpc.ClientConn
+}
+
+func GetClient(port string, mt *metrics.Metrics) (*Client, error) {
+	conn, err := grpc.Dial(port,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(mt.UnaryClientInterceptor(), logging.UnaryClientInterceptor()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("grpc connect err: %w", err)
+	}
+
+	return &Client{AuthorizationClient: auth.NewAuthorizationClient(conn), conn: conn}, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Ping asks the authorization service whether it is serving.
+func (c *Client) Ping(ctx context.Context) error {
+	resp, err := healthpb.NewHealthClient(c.conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: auth.Authorization_ServiceDesc.ServiceName,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("authorization is %s", resp.Status)
+	}
+	return nil
+}
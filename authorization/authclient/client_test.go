@@ -0,0 +1,61 @@
+package authclient
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	auth "github.com/go-park-mail-ru/2023_2_Vkladyshi/authorization/proto"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestPing(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	server := grpc.NewServer()
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(server, hs)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	mt, err := metrics.NewMetrics("test", prometheus.NewRegistry(), configs.MetricsCfg{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	client, err := GetClient(lis.Addr().String(), mt)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer client.Close()
+	service := auth.Authorization_ServiceDesc.ServiceName
+
+	testCases := []struct {
+		name   string
+		status healthpb.HealthCheckResponse_ServingStatus
+		err    bool
+	}{
+		{"Serving", healthpb.HealthCheckResponse_SERVING, false},
+		{"Not serving", healthpb.HealthCheckResponse_NOT_SERVING, true},
+	}
+	for _, tc := range testCases {
+		hs.SetServingStatus(service, tc.status)
+		if err := client.Ping(context.Background()); (err != nil) != tc.err {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+		}
+	}
+
+	server.Stop()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := client.Ping(ctx); err == nil {
+		t.Errorf("stopped server: expected an error")
+	}
+}
@@ -2,21 +2,37 @@ package delivery_auth_grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
+	"time"
 
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/authorization/repository/profile"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/authorization/repository/session"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	pb "github.com/go-park-mail-ru/2023_2_Vkladyshi/authorization/proto"
 )
 
+// healthInterval is how often the health service status follows the
+// repositories.
+const healthInterval = 5 * time.Second
+
 type authGrpc struct {
-	grpcServ *grpc.Server
-	lg       *slog.Logger
+	grpcServ   *grpc.Server
+	lg         *slog.Logger
+	cfg        configs.GrpcConfig
+	sessions   *session.SessionRepo
+	users      *profile.RepoPostgre
+	health     *health.Server
+	stopHealth context.CancelFunc
 }
 
 type server struct {
@@ -26,50 +42,83 @@ type server struct {
 	lg          *slog.Logger
 }
 
-func NewServer(l *slog.Logger) (*authGrpc, error) {
-	config, err := configs.ReadConfig()
-	if err != nil {
-		l.Error("read config error", "err", err.Error())
-		return nil, fmt.Errorf("listen and serve grpc error: %w", err)
-	}
-
-	configSession, err := configs.ReadSessionRedisConfig()
-	if err != nil {
-		l.Error("read config error", "err", err.Error())
-		return nil, fmt.Errorf("listen and serve grpc error: %w", err)
-	}
-
-	session, err := session.GetSessionRepo(*configSession, l)
+func NewServer(config *configs.AuthCfg, l *slog.Logger, mt *metrics.Metrics) (*authGrpc, error) {
+	session, err := session.GetSessionRepo(config.Session, l, mt)
 
 	if err != nil {
 		l.Error("Session repository is not responding")
 		return nil, fmt.Errorf("listen and serve grpc error: %w", err)
 	}
 
-	users, err := profile.GetUserRepo(config, l)
+	users, err := profile.GetUserRepo(&config.DbDsnCfg, l, mt)
 	if err != nil {
 		l.Error("cant create repo")
-		return nil, fmt.Errorf("listen and serve grpc error: %w", err)
+		return nil, fmt.Errorf("listen and serve grpc error: %w", errors.Join(err, session.Close()))
 	}
 
-	s := grpc.NewServer()
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(logging.UnaryServerInterceptor(), mt.UnaryServerInterceptor()),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+	)
 	pb.RegisterAuthorizationServer(s, &server{
 		lg:          l,
 		sessionRepo: session,
 		userRepo:    users,
 	})
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(s, hs)
+
+	ctx, stop := context.WithCancel(context.Background())
+	a := &authGrpc{grpcServ: s, lg: l, cfg: config.Grpc, sessions: session, users: users, health: hs, stopHealth: stop}
+	go a.watchHealth(ctx, mt.Health())
+	return a, nil
+}
 
-	return &authGrpc{grpcServ: s, lg: l}, nil
+// watchHealth serves the authorization service only while its session store
+// and user database are reachable, the CSRF store is not used over gRPC. The
+// server itself stays serving until shutdown.
+func (a *authGrpc) watchHealth(ctx context.Context, repos *metrics.Health) {
+	service := pb.Authorization_ServiceDesc.ServiceName
+	serving := true
+	for {
+		err := repos.Ready(ctx, "session", "profile")
+		if ctx.Err() != nil {
+			return
+		}
+		switch {
+		case err != nil && serving:
+			a.lg.Warn("grpc health not serving", "service", service, "err", err.Error())
+			a.health.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+		case err == nil:
+			if !serving {
+				a.lg.Info("grpc health serving", "service", service)
+			}
+			a.health.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+		}
+		serving = err == nil
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(healthInterval):
+		}
+	}
+}
+
+// Reconfigure applies the reloadable settings, the listener needs a restart.
+func (a *authGrpc) Reconfigure(config *configs.AuthCfg) {
+	a.sessions.SetTimer(config.Session.Timer)
 }
 
 func (s *server) GetId(ctx context.Context, req *pb.FindIdRequest) (*pb.FindIdResponse, error) {
-	login, err := s.sessionRepo.GetUserLogin(ctx, req.Sid, s.lg)
+	login, err := s.sessionRepo.GetUserLogin(ctx, req.Sid, logging.FromContext(ctx, s.lg))
 	if err != nil {
 		return nil, err
 	}
 
-	id, err := s.userRepo.GetUserProfileId(login)
+	id, err := s.userRepo.GetUserProfileId(ctx, login)
 	if err != nil {
+		logging.FromContext(ctx, s.lg).Error("get id error", "err", err.Error())
 		return nil, err
 	}
 	return &pb.FindIdResponse{
@@ -78,8 +127,9 @@ func (s *server) GetId(ctx context.Context, req *pb.FindIdRequest) (*pb.FindIdRe
 }
 
 func (s *server) GetIdsAndPaths(ctx context.Context, req *pb.NamesAndPathsListRequest) (*pb.NamesAndPathsResponse, error) {
-	names, paths, err := s.userRepo.GetNamesAndPaths(req.Ids)
+	names, paths, err := s.userRepo.GetNamesAndPaths(ctx, req.Ids)
 	if err != nil {
+		logging.FromContext(ctx, s.lg).Error("get ids and paths error", "err", err.Error())
 		return nil, err
 	}
 	return &pb.NamesAndPathsResponse{
@@ -89,7 +139,7 @@ func (s *server) GetIdsAndPaths(ctx context.Context, req *pb.NamesAndPathsListRe
 }
 
 func (s *server) GetAuthorizationStatus(ctx context.Context, req *pb.AuthorizationCheckRequest) (*pb.AuthorizationCheckResponse, error) {
-	status, err := s.sessionRepo.CheckActiveSession(ctx, req.Sid, s.lg)
+	status, err := s.sessionRepo.CheckActiveSession(ctx, req.Sid, logging.FromContext(ctx, s.lg))
 	if err != nil {
 		return nil, err
 	}
@@ -98,23 +148,45 @@ func (s *server) GetAuthorizationStatus(ctx context.Context, req *pb.Authorizati
 	}, nil
 }
 
-func (s *authGrpc) ListenAndServeGrpc() error {
-	grpcConfig, err := configs.ReadGrpcConfig()
-	if err != nil {
-		s.lg.Error("failed to parse grpc config file: %v", err)
-		return fmt.Errorf("listen and serve grpc error: %w", err)
-	}
-
-	lis, err := net.Listen(grpcConfig.ConnectionType, ":"+grpcConfig.Port)
+func (s *authGrpc) ListenAndServe() error {
+	lis, err := net.Listen(s.cfg.ConnectionType, ":"+s.cfg.Port)
 	if err != nil {
-		s.lg.Error("failed to listen: %v", err)
+		s.lg.Error("failed to listen", "err", err.Error())
 		return fmt.Errorf("listen and serve grpc error: %w", err)
 	}
 
 	if err := s.grpcServ.Serve(lis); err != nil {
-		s.lg.Error("failed to serve: %v", err)
+		s.lg.Error("failed to serve", "err", err.Error())
 		return fmt.Errorf("listen and serve grpc error: %w", err)
 	}
 
 	return nil
 }
+
+// Shutdown reports every service as not serving, waits for the running calls
+// to finish and stops them when ctx is done first.
+func (s *authGrpc) Shutdown(ctx context.Context) error {
+	s.stopHealth()
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServ.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServ.Stop()
+		return ctx.Err()
+	}
+}
+
+// Close stops the health checks, should Shutdown not have run, and closes the
+// repositories of the server.
+func (s *authGrpc) Close() error {
+	s.stopHealth()
+	return errors.Join(s.sessions.Close(), s.users.Close())
+}
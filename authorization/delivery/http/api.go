@@ -3,7 +3,6 @@ package delivery
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
@@ -11,10 +10,12 @@ import (
 	"time"
 
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/authorization/usecase"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/httpserver"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/middleware"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/requests"
-
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type IApi interface {
@@ -26,32 +27,27 @@ type IApi interface {
 	AuthAccept(w http.ResponseWriter, r *http.Request)
 }
 
+// Metrics are the metric components the service wires up, its dashboard and
+// alert rules are generated from them.
+var Metrics = metrics.Service{
+	Name:       "auth",
+	Components: []string{metrics.ComponentHTTP, metrics.ComponentDB, metrics.ComponentRedis, metrics.ComponentGrpcServer, metrics.ComponentAuthKPI},
+}
+
 type API struct {
 	core usecase.ICore
 	lg   *slog.Logger
-	mt   *metrics.Metrics
 	mx   *http.ServeMux
+	*httpserver.Server
 }
 
-func (a *API) ListenAndServe() error {
-	err := http.ListenAndServe(":8081", a.mx)
-	if err != nil {
-		a.lg.Error("ListenAndServe error", "err", err.Error())
-		return fmt.Errorf("listen and serve error: %w", err)
-	}
-
-	return nil
-}
-
-func GetApi(c *usecase.Core, l *slog.Logger) *API {
+func GetApi(c *usecase.Core, l *slog.Logger, cfg *configs.DbDsnCfg, mt *metrics.Metrics, al *middleware.AccessLog, levels *logging.Levels) *API {
 	api := &API{
 		core: c,
 		lg:   l.With("module", "api"),
-		mt:   metrics.GetMetrics(),
 		mx:   http.NewServeMux(),
 	}
 
-	api.mx.Handle("/metrics", promhttp.Handler())
 	api.mx.HandleFunc("/signin", api.Signin)
 	api.mx.HandleFunc("/signup", api.Signup)
 	api.mx.HandleFunc("/logout", api.LogoutSession)
@@ -59,41 +55,40 @@ func GetApi(c *usecase.Core, l *slog.Logger) *API {
 	api.mx.HandleFunc("/api/v1/csrf", api.GetCsrfToken)
 	api.mx.HandleFunc("/api/v1/settings", api.Profile)
 
+	api.Server = httpserver.GetServer(api.mx, cfg.ServerAdress, cfg.AdminAdress, cfg.Http, l, mt, al, levels, "/api/v1/settings")
+
 	return api
 }
 
 func (a *API) LogoutSession(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
 
-	start := time.Now()
-
 	session, err := r.Cookie("session_id")
 	if err == http.ErrNoCookie {
 		response.Status = http.StatusUnauthorized
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	found, _ := a.core.FindActiveSession(r.Context(), session.Value)
 	if !found {
 		response.Status = http.StatusUnauthorized
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	} else {
 		err := a.core.KillSession(r.Context(), session.Value)
 		if err != nil {
-			a.lg.Error("failed to kill session", "err", err.Error())
+			logging.FromContext(r.Context(), a.lg).Error("failed to kill session", "err", err.Error())
 		}
 		session.Expires = time.Now().AddDate(0, 0, -1)
 		http.SetCookie(w, session)
 	}
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) AuthAccept(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
 
-	start := time.Now()
 	var authorized bool
 
 	session, err := r.Cookie("session_id")
@@ -103,47 +98,45 @@ func (a *API) AuthAccept(w http.ResponseWriter, r *http.Request) {
 
 	if !authorized {
 		response.Status = http.StatusUnauthorized
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 	login, err := a.core.GetUserName(r.Context(), session.Value)
 	if err != nil {
-		a.lg.Error("auth accept error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("auth accept error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	role, err := a.core.GetUserRole(login)
+	role, err := a.core.GetUserRole(r.Context(), login)
 	if err != nil {
-		a.lg.Error("auth accept error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("auth accept error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	authCheckResponse := requests.AuthCheckResponse{Login: login, Role: role}
 	response.Body = authCheckResponse
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) Signin(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
 
-	start := time.Now()
 	if r.Method != http.MethodPost {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	csrfToken := r.Header.Get("x-csrf-token")
 
-	_, err := a.core.CheckCsrfToken(r.Context(), csrfToken)
-	if err != nil {
+	if err := a.core.SigninCsrf(r.Context(), csrfToken); err != nil {
 		w.Header().Set("X-CSRF-Token", "null")
 		response.Status = http.StatusPreconditionFailed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -151,49 +144,47 @@ func (a *API) Signin(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		response.Status = requests.BodyStatus(err)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	if err = json.Unmarshal(body, &request); err != nil {
 		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	user, found, err := a.core.FindUserAccount(request.Login, request.Password)
+	sid, session, found, err := a.core.Signin(r.Context(), request.Login, request.Password)
 	if err != nil {
-		a.lg.Error("Signin error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("Signin error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 	if !found {
 		response.Status = http.StatusUnauthorized
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
-	} else {
-		sid, session, _ := a.core.CreateSession(r.Context(), user.Login)
-		cookie := &http.Cookie{
-			Name:     "session_id",
-			Value:    sid,
-			Path:     "/",
-			Expires:  session.ExpiresAt,
-			HttpOnly: true,
-		}
-		http.SetCookie(w, cookie)
 	}
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+
+	cookie := &http.Cookie{
+		Name:     "session_id",
+		Value:    sid,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		HttpOnly: true,
+	}
+	http.SetCookie(w, cookie)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) Signup(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
 
-	start := time.Now()
 	if r.Method != http.MethodPost {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -203,7 +194,7 @@ func (a *API) Signup(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		w.Header().Set("X-CSRF-Token", "null")
 		response.Status = http.StatusPreconditionFailed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -211,63 +202,61 @@ func (a *API) Signup(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.lg.Error("Signup error", "err", err.Error())
-		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		logging.FromContext(r.Context(), a.lg).Error("Signup error", "err", err.Error())
+		response.Status = requests.BodyStatus(err)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	err = json.Unmarshal(body, &request)
 	if err != nil {
-		a.lg.Error("Signup error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("Signup error", "err", err.Error())
 		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	found, err := a.core.FindUserByLogin(request.Login)
+	found, err := a.core.FindUserByLogin(r.Context(), request.Login)
 	if err != nil {
-		a.lg.Error("Signup error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("Signup error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	if found {
 		response.Status = http.StatusConflict
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	err = a.core.CreateUserAccount(request.Login, request.Password, request.Name, request.BirthDate, request.Email)
+	err = a.core.CreateUserAccount(r.Context(), request.Login, request.Password, request.Name, request.BirthDate, request.Email)
 	if err == usecase.InvalideEmail {
-		a.lg.Error("create user error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("create user error", "err", err.Error())
 		response.Status = http.StatusBadRequest
 	}
 	if err != nil {
-		a.lg.Error("failed to create user account", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("failed to create user account", "err", err.Error())
 		response.Status = http.StatusBadRequest
 	}
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) GetCsrfToken(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
 
-	start := time.Now()
-
 	csrfToken := r.Header.Get("x-csrf-token")
 
 	found, err := a.core.CheckCsrfToken(r.Context(), csrfToken)
 	if err != nil {
 		w.Header().Set("X-CSRF-Token", "null")
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 	if csrfToken != "" && found {
 		w.Header().Set("X-CSRF-Token", csrfToken)
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -275,35 +264,34 @@ func (a *API) GetCsrfToken(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		w.Header().Set("X-CSRF-Token", "null")
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	w.Header().Set("X-CSRF-Token", token)
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) Profile(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
 
-	start := time.Now()
 	if r.Method == http.MethodGet {
 		session, err := r.Cookie("session_id")
 		if err == http.ErrNoCookie {
 			response.Status = http.StatusUnauthorized
-			requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+			requests.SendResponse(w, response, a.lg)
 			return
 		}
 
 		login, err := a.core.GetUserName(r.Context(), session.Value)
 		if err != nil {
-			a.lg.Error("Get Profile error", "err", err.Error())
+			logging.FromContext(r.Context(), a.lg).Error("Get Profile error", "err", err.Error())
 		}
 
-		profile, err := a.core.GetUserProfile(login)
+		profile, err := a.core.GetUserProfile(r.Context(), login)
 		if err != nil {
 			response.Status = http.StatusInternalServerError
-			requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+			requests.SendResponse(w, response, a.lg)
 			return
 		}
 
@@ -316,32 +304,32 @@ func (a *API) Profile(w http.ResponseWriter, r *http.Request) {
 		}
 
 		response.Body = profileResponse
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	if r.Method != http.MethodPost {
 		response.Status = http.StatusUnauthorized
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 	session, err := r.Cookie("session_id")
 	if err == http.ErrNoCookie {
 		response.Status = http.StatusUnauthorized
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	prevLogin, err := a.core.GetUserName(r.Context(), session.Value)
 	if err != nil {
-		a.lg.Error("Get Profile error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("Get Profile error", "err", err.Error())
 	}
 
-	err1 := r.ParseMultipartForm(10 << 20)
+	err1 := r.ParseMultipartForm(a.Limit("/api/v1/settings"))
 	if err1 != nil {
-		a.lg.Error("Post profile error", "err", err.Error())
-		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		logging.FromContext(r.Context(), a.lg).Error("Post profile error", "err", err1.Error())
+		response.Status = requests.BodyStatus(err1)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -351,17 +339,17 @@ func (a *API) Profile(w http.ResponseWriter, r *http.Request) {
 	password := r.FormValue("password")
 	photo, handler, err := r.FormFile("photo")
 	if err != nil && !errors.Is(err, http.ErrMissingFile) {
-		a.lg.Error("Post profile error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("Post profile error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	isRepeatPassword, err := a.core.CheckPassword(login, password)
+	isRepeatPassword, err := a.core.CheckPassword(r.Context(), login, password)
 
 	if isRepeatPassword {
 		response.Status = http.StatusConflict
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -369,49 +357,49 @@ func (a *API) Profile(w http.ResponseWriter, r *http.Request) {
 	if handler == nil {
 		filename = ""
 
-		err = a.core.EditProfile(prevLogin, login, password, email, birthDate, filename)
+		err = a.core.EditProfile(r.Context(), prevLogin, login, password, email, birthDate, filename)
 		if err != nil {
-			a.lg.Error("Post profile error", "err", err.Error())
+			logging.FromContext(r.Context(), a.lg).Error("Post profile error", "err", err.Error())
 			response.Status = http.StatusInternalServerError
-			requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+			requests.SendResponse(w, response, a.lg)
 			return
 		}
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	filename = "/avatars/" + handler.Filename
 
 	if err != nil && handler != nil && photo != nil {
-		a.lg.Error("Post profile error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("Post profile error", "err", err.Error())
 		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	filePhoto, err := os.OpenFile("/home/ubuntu/frontend-project"+filename, os.O_WRONLY|os.O_CREATE, 0666)
 	if err != nil {
-		a.lg.Error("Post profile error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("Post profile error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 	defer filePhoto.Close()
 
 	_, err = io.Copy(filePhoto, photo)
 	if err != nil {
-		a.lg.Error("Post profile error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("Post profile error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	err = a.core.EditProfile(prevLogin, login, password, email, birthDate, filename)
+	err = a.core.EditProfile(r.Context(), prevLogin, login, password, email, birthDate, filename)
 	if err != nil {
-		a.lg.Error("Post profile error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("Post profile error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
@@ -2,56 +2,99 @@ package csrf
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
 	"github.com/go-redis/redis/v8"
 )
 
+//go:generate mockgen -source=csrf_repo.go -destination=../../mocks/csrf_repo_mock.go -package=mocks
+
+type ICsrfRepo interface {
+	AddCsrf(ctx context.Context, active models.Csrf, lg *slog.Logger) (bool, error)
+	CheckActiveCsrf(ctx context.Context, sid string, lg *slog.Logger) (bool, error)
+	SetTimer(seconds int)
+	Close() error
+}
+
 var mutex sync.RWMutex
 
 type CsrfRepo struct {
 	csrfRedisClient *redis.Client
 	Connection      bool
+	mt              *metrics.RedisMetrics
+	stop            context.CancelFunc
+	done            chan struct{}
+	timer           atomic.Int64
 }
 
-func (redisRepo *CsrfRepo) CheckRedisCsrfConnection(csrfCfg configs.DbRedisCfg) {
-	ctx := context.Background()
+func (redisRepo *CsrfRepo) CheckRedisCsrfConnection(ctx context.Context) {
+	defer close(redisRepo.done)
 	for {
+		start := time.Now()
 		_, err := redisRepo.csrfRedisClient.Ping(ctx).Result()
-		mutex.RLock()
+		if ctx.Err() != nil {
+			return
+		}
+		redisRepo.mt.Ping(start, err)
 		mutex.Lock()
 		redisRepo.Connection = err == nil
 		mutex.Unlock()
-		mutex.RUnlock()
 
-		time.Sleep(time.Duration(csrfCfg.Timer) * time.Second)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Duration(redisRepo.timer.Load()) * time.Second):
+		}
 	}
 }
 
-func GetCsrfRepo(csrfConfigs configs.DbRedisCfg, lg *slog.Logger) (*CsrfRepo, error) {
+// Close stops the connection checks and closes the client.
+func (redisRepo *CsrfRepo) Close() error {
+	redisRepo.stop()
+	<-redisRepo.done
+	return redisRepo.csrfRedisClient.Close()
+}
+
+func (redisRepo *CsrfRepo) SetTimer(seconds int) {
+	redisRepo.timer.Store(int64(seconds))
+}
+
+func GetCsrfRepo(csrfConfigs configs.DbRedisCfg, lg *slog.Logger, mt *metrics.Metrics) (*CsrfRepo, error) {
 	redisClient := redis.NewClient(&redis.Options{
-		Addr:     csrfConfigs.Host,
+		Addr:     csrfConfigs.Addr,
 		Password: csrfConfigs.Password,
 		DB:       csrfConfigs.DbNumber,
 	})
+	redisMetrics := mt.Redis("csrf")
+	redisClient.AddHook(redisMetrics)
 
 	ctx := context.Background()
 
+	start := time.Now()
 	_, err := redisClient.Ping(ctx).Result()
+	redisMetrics.Ping(start, err)
 	if err != nil {
-		return nil, err
+		return nil, errors.Join(err, redisClient.Close())
 	}
 
 	csrfRepo := CsrfRepo{
 		csrfRedisClient: redisClient,
 		Connection:      true,
+		mt:              redisMetrics,
+		done:            make(chan struct{}),
 	}
 
-	go csrfRepo.CheckRedisCsrfConnection(csrfConfigs)
+	csrfRepo.SetTimer(csrfConfigs.Timer)
+	ctx, csrfRepo.stop = context.WithCancel(context.Background())
+	go csrfRepo.CheckRedisCsrfConnection(ctx)
 
 	return &csrfRepo, nil
 }
@@ -67,7 +110,7 @@ func (redisRepo *CsrfRepo) AddCsrf(ctx context.Context, active models.Csrf, lg *
 	csrfAdded, err_check := redisRepo.CheckActiveCsrf(ctx, active.SID, lg)
 
 	if err_check != nil {
-		lg.Error("Error, cannot create csrf token " + err_check.Error())
+		lg.Error("Error, cannot create csrf token", "csrf_hash", logging.HashToken(active.SID), "err", err_check.Error())
 		return false, err_check
 	}
 
@@ -82,12 +125,12 @@ func (redisRepo *CsrfRepo) CheckActiveCsrf(ctx context.Context, sid string, lg *
 
 	_, err := redisRepo.csrfRedisClient.Get(ctx, sid).Result()
 	if err == redis.Nil {
-		lg.Error("Key " + sid + " not found")
+		lg.Error("Csrf key not found", "csrf_hash", logging.HashToken(sid))
 		return false, nil
 	}
 
 	if err != nil {
-		lg.Error("Get request could not be completed ", err)
+		lg.Error("Get request could not be completed", "err", err.Error())
 		return false, err
 	}
 
@@ -97,7 +140,7 @@ func (redisRepo *CsrfRepo) CheckActiveCsrf(ctx context.Context, sid string, lg *
 func (redisRepo *CsrfRepo) DeleteSession(ctx context.Context, sid string, lg *slog.Logger) (bool, error) {
 	_, err := redisRepo.csrfRedisClient.Del(ctx, sid).Result()
 	if err != nil {
-		lg.Error("Delete request could not be completed:", err)
+		lg.Error("Delete request could not be completed", "csrf_hash", logging.HashToken(sid), "err", err.Error())
 		return false, err
 	}
 
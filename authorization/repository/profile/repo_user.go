@@ -1,37 +1,40 @@
 package profile
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log/slog"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
 	_ "github.com/jackc/pgx/stdlib"
 	"github.com/lib/pq"
 )
 
 type IUserRepo interface {
-	GetUser(login string, password string) (*models.UserItem, bool, error)
-	GetUserProfileId(login string) (int64, error)
-	FindUser(login string) (bool, error)
-	CreateUser(login string, password string, name string, birthDate string, email string) error
-	GetUserProfile(login string) (*models.UserItem, error)
-	EditProfile(prevLogin string, login string, password string, email string, birthDate string, photo string) error
-	GetNamesAndPaths(ids []int32) ([]string, []string, error)
-	CheckUserPassword(login string, password string) (bool, error)
-	GetUserRole(login string) (string, error)
+	GetUser(ctx context.Context, login string, password string) (*models.UserItem, bool, error)
+	GetUserProfileId(ctx context.Context, login string) (int64, error)
+	FindUser(ctx context.Context, login string) (bool, error)
+	CreateUser(ctx context.Context, login string, password string, name string, birthDate string, email string) error
+	GetUserProfile(ctx context.Context, login string) (*models.UserItem, error)
+	EditProfile(ctx context.Context, prevLogin string, login string, password string, email string, birthDate string, photo string) error
+	GetNamesAndPaths(ctx context.Context, ids []int32) ([]string, []string, error)
+	CheckUserPassword(ctx context.Context, login string, password string) (bool, error)
+	GetUserRole(ctx context.Context, login string) (string, error)
+	Close() error
 }
 
 type RepoPostgre struct {
 	db *sql.DB
+	mt *metrics.DBMetrics
 }
 
-func GetUserRepo(config *configs.DbDsnCfg, lg *slog.Logger) (*RepoPostgre, error) {
+func GetUserRepo(config *configs.DbDsnCfg, lg *slog.Logger, mt *metrics.Metrics) (*RepoPostgre, error) {
 	dsn := fmt.Sprintf("user=%s dbname=%s password= %s host=%s port=%d sslmode=%s",
 		config.User, config.DbName, config.Password, config.Host, config.Port, config.Sslmode)
 	db, err := sql.Open("pgx", dsn)
@@ -39,34 +42,28 @@ func GetUserRepo(config *configs.DbDsnCfg, lg *slog.Logger) (*RepoPostgre, error
 		lg.Error("sql open error", "err", err.Error())
 		return nil, fmt.Errorf("get user repo err: %w", err)
 	}
-	err = db.Ping()
+	db.SetMaxOpenConns(config.MaxOpenConns)
+
+	dbMetrics, err := mt.OpenDB("profile", db, config.Timer, lg)
 	if err != nil {
 		lg.Error("sql ping error", "err", err.Error())
 		return nil, fmt.Errorf("get user repo err: %w", err)
 	}
-	db.SetMaxOpenConns(config.MaxOpenConns)
-
-	postgreDb := RepoPostgre{db: db}
 
-	go postgreDb.pingDb(config.Timer, lg)
-	return &postgreDb, nil
+	return &RepoPostgre{db: db, mt: dbMetrics}, nil
 }
 
-func (repo *RepoPostgre) pingDb(timer uint32, lg *slog.Logger) {
-	for {
-		err := repo.db.Ping()
-		if err != nil {
-			lg.Error("Repo Profile db ping error", "err", err.Error())
-		}
-
-		time.Sleep(time.Duration(timer) * time.Second)
-	}
+func (repo *RepoPostgre) Close() error {
+	return repo.mt.Close()
 }
 
-func (repo *RepoPostgre) CheckUserPassword(login string, password string) (bool, error) {
+func (repo *RepoPostgre) CheckUserPassword(ctx context.Context, login string, password string) (bool, error) {
+	ctx, done := repo.mt.Query(ctx, "CheckUserPassword")
+	defer done()
+
 	post := &models.UserItem{}
 
-	err := repo.db.QueryRow(
+	err := repo.db.QueryRowContext(ctx,
 		"SELECT login FROM profile "+
 			"WHERE login = $1 AND password = $2", login, password).Scan(&post.Login)
 	if err != nil {
@@ -79,10 +76,13 @@ func (repo *RepoPostgre) CheckUserPassword(login string, password string) (bool,
 	return true, nil
 }
 
-func (repo *RepoPostgre) GetUser(login string, password string) (*models.UserItem, bool, error) {
+func (repo *RepoPostgre) GetUser(ctx context.Context, login string, password string) (*models.UserItem, bool, error) {
+	ctx, done := repo.mt.Query(ctx, "GetUser")
+	defer done()
+
 	post := &models.UserItem{}
 
-	err := repo.db.QueryRow(
+	err := repo.db.QueryRowContext(ctx,
 		"SELECT login, photo FROM profile "+
 			"WHERE login = $1 AND password = $2", login, password).Scan(&post.Login, &post.Photo)
 	if err != nil {
@@ -95,10 +95,13 @@ func (repo *RepoPostgre) GetUser(login string, password string) (*models.UserIte
 	return post, true, nil
 }
 
-func (repo *RepoPostgre) FindUser(login string) (bool, error) {
+func (repo *RepoPostgre) FindUser(ctx context.Context, login string) (bool, error) {
+	ctx, done := repo.mt.Query(ctx, "FindUser")
+	defer done()
+
 	post := &models.UserItem{}
 
-	err := repo.db.QueryRow(
+	err := repo.db.QueryRowContext(ctx,
 		"SELECT login FROM profile "+
 			"WHERE login = $1", login).Scan(&post.Login)
 	if err != nil {
@@ -111,10 +114,13 @@ func (repo *RepoPostgre) FindUser(login string) (bool, error) {
 	return true, nil
 }
 
-func (repo *RepoPostgre) GetUserProfileId(login string) (int64, error) {
+func (repo *RepoPostgre) GetUserProfileId(ctx context.Context, login string) (int64, error) {
+	ctx, done := repo.mt.Query(ctx, "GetUserProfileId")
+	defer done()
+
 	var userID int64
 
-	err := repo.db.QueryRow(
+	err := repo.db.QueryRowContext(ctx,
 		"SELECT id FROM profile WHERE login = $1", login).Scan(&userID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -126,8 +132,11 @@ func (repo *RepoPostgre) GetUserProfileId(login string) (int64, error) {
 	return userID, nil
 }
 
-func (repo *RepoPostgre) CreateUser(login string, password string, name string, birthDate string, email string) error {
-	_, err := repo.db.Exec(
+func (repo *RepoPostgre) CreateUser(ctx context.Context, login string, password string, name string, birthDate string, email string) error {
+	ctx, done := repo.mt.Query(ctx, "CreateUser")
+	defer done()
+
+	_, err := repo.db.ExecContext(ctx,
 		"INSERT INTO profile(name, birth_date, photo, login, password, email, registration_date) "+
 			"VALUES($1, $2, '/avatars/default.jpg', $3, $4, $5, CURRENT_TIMESTAMP)",
 		name, birthDate, login, password, email)
@@ -138,11 +147,14 @@ func (repo *RepoPostgre) CreateUser(login string, password string, name string,
 	return nil
 }
 
-func (repo *RepoPostgre) GetNamesAndPaths(ids []int32) ([]string, []string, error) {
+func (repo *RepoPostgre) GetNamesAndPaths(ctx context.Context, ids []int32) ([]string, []string, error) {
+	ctx, done := repo.mt.Query(ctx, "GetNamesAndPaths")
+	defer done()
+
 	var s strings.Builder
 	s.WriteString("SELECT login, photo FROM profile WHERE id = ANY ($1::INTEGER[])")
 
-	rows, err := repo.db.Query(s.String(), pq.Array(ids))
+	rows, err := repo.db.QueryContext(ctx, s.String(), pq.Array(ids))
 	if err != nil {
 		return nil, nil, fmt.Errorf("GetMatchingNamesAndPaths query error: %w", err)
 	}
@@ -164,10 +176,13 @@ func (repo *RepoPostgre) GetNamesAndPaths(ids []int32) ([]string, []string, erro
 	return names, paths, nil
 }
 
-func (repo *RepoPostgre) GetUserProfile(login string) (*models.UserItem, error) {
+func (repo *RepoPostgre) GetUserProfile(ctx context.Context, login string) (*models.UserItem, error) {
+	ctx, done := repo.mt.Query(ctx, "GetUserProfile")
+	defer done()
+
 	post := &models.UserItem{}
 
-	err := repo.db.QueryRow(
+	err := repo.db.QueryRowContext(ctx,
 		"SELECT name, birth_date, login, email, photo FROM profile "+
 			"WHERE login = $1", login).Scan(&post.Name, &post.Birthdate, &post.Login, &post.Email, &post.Photo)
 	if err != nil {
@@ -177,7 +192,10 @@ func (repo *RepoPostgre) GetUserProfile(login string) (*models.UserItem, error)
 	return post, nil
 }
 
-func (repo *RepoPostgre) EditProfile(prevLogin string, login string, password string, email string, birthDate string, photo string) error {
+func (repo *RepoPostgre) EditProfile(ctx context.Context, prevLogin string, login string, password string, email string, birthDate string, photo string) error {
+	ctx, done := repo.mt.Query(ctx, "EditProfile")
+	defer done()
+
 	var s strings.Builder
 	paramNum := 1
 	var params []interface{}
@@ -223,7 +241,7 @@ func (repo *RepoPostgre) EditProfile(prevLogin string, login string, password st
 	}
 	s.WriteString(" WHERE login = $" + strconv.Itoa(paramNum))
 	params = append(params, prevLogin)
-	_, err := repo.db.Exec(s.String(), params...)
+	_, err := repo.db.ExecContext(ctx, s.String(), params...)
 	if err != nil {
 		return fmt.Errorf("failed to edit profile in db: %w", err)
 	}
@@ -231,10 +249,13 @@ func (repo *RepoPostgre) EditProfile(prevLogin string, login string, password st
 	return nil
 }
 
-func (repo *RepoPostgre) GetUserRole(login string) (string, error) {
+func (repo *RepoPostgre) GetUserRole(ctx context.Context, login string) (string, error) {
+	ctx, done := repo.mt.Query(ctx, "GetUserRole")
+	defer done()
+
 	var role string
 
-	err := repo.db.QueryRow("SELECT role FROM profile WHERE login = $1", login).Scan(&role)
+	err := repo.db.QueryRowContext(ctx, "SELECT role FROM profile WHERE login = $1", login).Scan(&role)
 	if err != nil {
 		return "", fmt.Errorf("get user role err: %w", err)
 	}
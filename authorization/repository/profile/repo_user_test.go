@@ -1,6 +1,7 @@
 package profile
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -35,7 +36,7 @@ func TestGetUser(t *testing.T) {
 		db: db,
 	}
 
-	user, foundAccount, err := repo.GetUser(expect[0].Login, expect[0].Password)
+	user, foundAccount, err := repo.GetUser(context.Background(), expect[0].Login, expect[0].Password)
 	if err != nil {
 		t.Errorf("GetUser error: %s", err)
 	}
@@ -58,7 +59,7 @@ func TestGetUser(t *testing.T) {
 		WithArgs(expect[0].Login, expect[0].Password).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	_, found, err := repo.GetUser(expect[0].Login, expect[0].Password)
+	_, found, err := repo.GetUser(context.Background(), expect[0].Login, expect[0].Password)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -96,7 +97,7 @@ func TestFindUser(t *testing.T) {
 		db: db,
 	}
 
-	foundAccount, err := repo.FindUser(expect[0].Login)
+	foundAccount, err := repo.FindUser(context.Background(), expect[0].Login)
 	if err != nil {
 		t.Errorf("GetUser error: %s", err)
 	}
@@ -114,7 +115,7 @@ func TestFindUser(t *testing.T) {
 		WithArgs(expect[0].Login).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	found, err := repo.FindUser(expect[0].Login)
+	found, err := repo.FindUser(context.Background(), expect[0].Login)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -159,7 +160,7 @@ func TestCreateUser(t *testing.T) {
 		db: db,
 	}
 
-	err = repo.CreateUser(testUser.Login, testUser.Password, testUser.Name, testUser.Birthdate, testUser.Email)
+	err = repo.CreateUser(context.Background(), testUser.Login, testUser.Password, testUser.Name, testUser.Birthdate, testUser.Email)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err)
 		return
@@ -174,7 +175,7 @@ func TestCreateUser(t *testing.T) {
 		WithArgs(testUser.Name, testUser.Birthdate, testUser.Login, testUser.Password, testUser.Email).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	err = repo.CreateUser(testUser.Login, testUser.Password, testUser.Name, testUser.Birthdate, testUser.Email)
+	err = repo.CreateUser(context.Background(), testUser.Login, testUser.Password, testUser.Name, testUser.Birthdate, testUser.Email)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -217,7 +218,7 @@ func TestEditProfile(t *testing.T) {
 		db: db,
 	}
 
-	err = repo.EditProfile(prev, testUser.Login, testUser.Password, testUser.Email, testUser.Birthdate, testUser.Photo)
+	err = repo.EditProfile(context.Background(), prev, testUser.Login, testUser.Password, testUser.Email, testUser.Birthdate, testUser.Photo)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err)
 		return
@@ -232,7 +233,7 @@ func TestEditProfile(t *testing.T) {
 		WithArgs(testUser.Login, testUser.Password, testUser.Photo, testUser.Email, testUser.Birthdate, prev).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	err = repo.EditProfile(prev, testUser.Login, testUser.Password, testUser.Email, testUser.Birthdate, testUser.Photo)
+	err = repo.EditProfile(context.Background(), prev, testUser.Login, testUser.Password, testUser.Email, testUser.Birthdate, testUser.Photo)
 	if err == nil {
 		t.Errorf("expected error, got nil")
 		return
@@ -2,11 +2,15 @@ package session
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
 	"github.com/go-redis/redis/v8"
 )
 
@@ -15,40 +19,73 @@ var mutex sync.RWMutex
 type SessionRepo struct {
 	sessionRedisClient *redis.Client
 	Connection         bool
+	mt                 *metrics.RedisMetrics
+	stop               context.CancelFunc
+	done               chan struct{}
+	timer              atomic.Int64
 }
 
-func (redisRepo *SessionRepo) CheckRedisSessionConnection(sessionCfg configs.DbRedisCfg) {
-	ctx := context.Background()
+func (redisRepo *SessionRepo) CheckRedisSessionConnection(ctx context.Context) {
+	defer close(redisRepo.done)
 	for {
+		start := time.Now()
 		_, err := redisRepo.sessionRedisClient.Ping(ctx).Result()
+		if ctx.Err() != nil {
+			return
+		}
+		redisRepo.mt.Ping(start, err)
 		mutex.Lock()
-		mutex.RLock()
 		redisRepo.Connection = err == nil
 		mutex.Unlock()
-		mutex.RUnlock()
-		time.Sleep(time.Duration(sessionCfg.Timer) * time.Second)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Duration(redisRepo.timer.Load()) * time.Second):
+		}
 	}
 }
 
-func GetSessionRepo(sessionCfg configs.DbRedisCfg, lg *slog.Logger) (*SessionRepo, error) {
+// Close stops the connection checks and closes the client.
+func (redisRepo *SessionRepo) Close() error {
+	redisRepo.stop()
+	<-redisRepo.done
+	return redisRepo.sessionRedisClient.Close()
+}
+
+// SetTimer changes the seconds between connection checks, it takes effect
+// after the current wait.
+func (redisRepo *SessionRepo) SetTimer(seconds int) {
+	redisRepo.timer.Store(int64(seconds))
+}
+
+func GetSessionRepo(sessionCfg configs.DbRedisCfg, lg *slog.Logger, mt *metrics.Metrics) (*SessionRepo, error) {
 	redisClient := redis.NewClient(&redis.Options{
-		Addr:     sessionCfg.Host,
+		Addr:     sessionCfg.Addr,
 		Password: sessionCfg.Password,
 		DB:       sessionCfg.DbNumber,
 	})
+	redisMetrics := mt.Redis("session")
+	redisClient.AddHook(redisMetrics)
 
 	ctx := context.Background()
+	start := time.Now()
 	_, err := redisClient.Ping(ctx).Result()
+	redisMetrics.Ping(start, err)
 	if err != nil {
-		return nil, err
+		return nil, errors.Join(err, redisClient.Close())
 	}
 
 	sessionRepo := SessionRepo{
 		sessionRedisClient: redisClient,
 		Connection:         true,
+		mt:                 redisMetrics,
+		done:               make(chan struct{}),
 	}
 
-	go sessionRepo.CheckRedisSessionConnection(sessionCfg)
+	sessionRepo.SetTimer(sessionCfg.Timer)
+	ctx, sessionRepo.stop = context.WithCancel(context.Background())
+	go sessionRepo.CheckRedisSessionConnection(ctx)
 
 	return &sessionRepo, nil
 }
@@ -78,7 +115,7 @@ func (redisRepo *SessionRepo) GetUserLogin(ctx context.Context, sid string, lg *
 
 	value, err := redisRepo.sessionRedisClient.Get(ctx, sid).Result()
 	if err != nil {
-		lg.Error("Error, cannot find session " + sid)
+		lg.Error("Error, cannot find session", "sid_hash", logging.HashToken(sid))
 		return "", err
 	}
 
@@ -93,12 +130,12 @@ func (redisRepo *SessionRepo) CheckActiveSession(ctx context.Context, sid string
 
 	_, err := redisRepo.sessionRedisClient.Get(ctx, sid).Result()
 	if err == redis.Nil {
-		lg.Error("Key " + sid + " not found")
+		lg.Error("Session key not found", "sid_hash", logging.HashToken(sid))
 		return false, nil
 	}
 
 	if err != nil {
-		lg.Error("Get request could not be completed ", err)
+		lg.Error("Get request could not be completed", "err", err.Error())
 		return false, err
 	}
 
@@ -108,7 +145,7 @@ func (redisRepo *SessionRepo) CheckActiveSession(ctx context.Context, sid string
 func (redisRepo *SessionRepo) DeleteSession(ctx context.Context, sid string, lg *slog.Logger) (bool, error) {
 	_, err := redisRepo.sessionRedisClient.Del(ctx, sid).Result()
 	if err != nil {
-		lg.Error("Delete request could not be completed:", err)
+		lg.Error("Delete request could not be completed", "sid_hash", logging.HashToken(sid), "err", err.Error())
 		return false, err
 	}
 
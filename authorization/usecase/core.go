@@ -14,31 +14,36 @@ import (
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/authorization/repository/profile"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/authorization/repository/session"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
 )
 
 type ICore interface {
 	CreateSession(ctx context.Context, login string) (string, session.Session, error)
+	SigninCsrf(ctx context.Context, csrfToken string) error
+	Signin(ctx context.Context, login string, password string) (string, session.Session, bool, error)
 	KillSession(ctx context.Context, sid string) error
 	FindActiveSession(ctx context.Context, sid string) (bool, error)
-	CreateUserAccount(login string, password string, name string, birthDate string, email string) error
-	FindUserAccount(login string, password string) (*models.UserItem, bool, error)
-	FindUserByLogin(login string) (bool, error)
+	CreateUserAccount(ctx context.Context, login string, password string, name string, birthDate string, email string) error
+	FindUserAccount(ctx context.Context, login string, password string) (*models.UserItem, bool, error)
+	FindUserByLogin(ctx context.Context, login string) (bool, error)
 	GetUserName(ctx context.Context, sid string) (string, error)
-	GetUserProfile(login string) (*models.UserItem, error)
-	EditProfile(prevLogin string, login string, password string, email string, birthDate string, photo string) error
+	GetUserProfile(ctx context.Context, login string) (*models.UserItem, error)
+	EditProfile(ctx context.Context, prevLogin string, login string, password string, email string, birthDate string, photo string) error
 	CheckCsrfToken(ctx context.Context, token string) (bool, error)
 	CreateCsrfToken(ctx context.Context) (string, error)
-	CheckPassword(login string, password string) (bool, error)
-	GetUserRole(login string) (string, error)
+	CheckPassword(ctx context.Context, login string, password string) (bool, error)
+	GetUserRole(ctx context.Context, login string) (string, error)
 }
 
 type Core struct {
-	sessions   session.SessionRepo
+	sessions   *session.SessionRepo
 	mutex      sync.RWMutex
 	lg         *slog.Logger
 	users      profile.IUserRepo
-	csrfTokens csrf.CsrfRepo
+	csrfTokens csrf.ICsrfRepo
+	recorder   metrics.IAuthRecorder
 }
 
 var InvalideEmail = errors.New("invalide email")
@@ -46,48 +51,61 @@ var LostConnection = errors.New("Redis connection lost")
 
 var letterRunes = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
 
-func GetCore(cfg_sql *configs.DbDsnCfg, cfg_csrf configs.DbRedisCfg, cfg_sessions configs.DbRedisCfg, lg *slog.Logger) (*Core, error) {
-	session, err := session.GetSessionRepo(cfg_sessions, lg)
+func GetCore(cfg_sql *configs.DbDsnCfg, cfg_csrf configs.DbRedisCfg, cfg_sessions configs.DbRedisCfg, lg *slog.Logger, mt *metrics.Metrics) (*Core, error) {
+	session, err := session.GetSessionRepo(cfg_sessions, lg, mt)
 
 	if err != nil {
 		lg.Error("Session repository is not responding")
 		return nil, err
 	}
 
-	users, err := profile.GetUserRepo(cfg_sql, lg)
+	users, err := profile.GetUserRepo(cfg_sql, lg, mt)
 	if err != nil {
 		lg.Error("cant create repo")
-		return nil, err
+		return nil, errors.Join(err, session.Close())
 	}
 
-	csrf, err := csrf.GetCsrfRepo(cfg_csrf, lg)
+	csrf, err := csrf.GetCsrfRepo(cfg_csrf, lg, mt)
 	if err != nil {
 		lg.Error("Csrf repository is not responding")
-		return nil, err
+		return nil, errors.Join(err, session.Close(), users.Close())
 	}
 
 	core := Core{
-		sessions:   *session,
+		sessions:   session,
 		lg:         lg.With("module", "core"),
 		users:      users,
-		csrfTokens: *csrf,
+		csrfTokens: csrf,
+		recorder:   mt.Business(),
 	}
 	return &core, nil
 }
 
-func (core *Core) CheckPassword(login string, password string) (bool, error) {
-	found, err := core.users.CheckUserPassword(login, password)
+// Close stops the connection checks and closes the Redis clients and the
+// database.
+func (core *Core) Close() error {
+	return errors.Join(core.sessions.Close(), core.csrfTokens.Close(), core.users.Close())
+}
+
+// Reconfigure applies the reloadable settings of the Redis repositories.
+func (core *Core) Reconfigure(cfg *configs.AuthCfg) {
+	core.csrfTokens.SetTimer(cfg.Csrf.Timer)
+	core.sessions.SetTimer(cfg.Session.Timer)
+}
+
+func (core *Core) CheckPassword(ctx context.Context, login string, password string) (bool, error) {
+	found, err := core.users.CheckUserPassword(ctx, login, password)
 	if err != nil {
-		core.lg.Error("find user error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("find user error", "err", err.Error())
 		return false, fmt.Errorf("FindUserAccount err: %w", err)
 	}
 	return found, nil
 }
 
-func (core *Core) EditProfile(prevLogin string, login string, password string, email string, birthDate string, photo string) error {
-	err := core.users.EditProfile(prevLogin, login, password, email, birthDate, photo)
+func (core *Core) EditProfile(ctx context.Context, prevLogin string, login string, password string, email string, birthDate string, photo string) error {
+	err := core.users.EditProfile(ctx, prevLogin, login, password, email, birthDate, photo)
 	if err != nil {
-		core.lg.Error("Edit profile error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("Edit profile error", "err", err.Error())
 		return fmt.Errorf("Edit profile error: %w", err)
 	}
 
@@ -96,7 +114,7 @@ func (core *Core) EditProfile(prevLogin string, login string, password string, e
 
 func (core *Core) GetUserName(ctx context.Context, sid string) (string, error) {
 	core.mutex.RLock()
-	login, err := core.sessions.GetUserLogin(ctx, sid, core.lg)
+	login, err := core.sessions.GetUserLogin(ctx, sid, logging.FromContext(ctx, core.lg))
 	core.mutex.RUnlock()
 
 	if err != nil {
@@ -116,7 +134,7 @@ func (core *Core) CreateSession(ctx context.Context, login string) (string, sess
 	}
 
 	core.mutex.Lock()
-	sessionAdded, err := core.sessions.AddSession(ctx, newSession, core.lg)
+	sessionAdded, err := core.sessions.AddSession(ctx, newSession, logging.FromContext(ctx, core.lg))
 	core.mutex.Unlock()
 
 	if !sessionAdded && err != nil {
@@ -130,9 +148,43 @@ func (core *Core) CreateSession(ctx context.Context, login string) (string, sess
 	return sid, newSession, nil
 }
 
+// SigninCsrf checks the CSRF token of a sign in before its body is read.
+// Unknown tokens pass, the check only fails when Redis does.
+func (core *Core) SigninCsrf(ctx context.Context, csrfToken string) error {
+	_, err := core.CheckCsrfToken(ctx, csrfToken)
+	if err != nil {
+		core.recorder.Signin(metrics.SigninRedisDown)
+		return fmt.Errorf("SigninCsrf err: %w", err)
+	}
+	return nil
+}
+
+// Signin finds the account and opens its session. A session that could not
+// be stored leaves sid empty without an error.
+func (core *Core) Signin(ctx context.Context, login string, password string) (string, session.Session, bool, error) {
+	user, found, err := core.FindUserAccount(ctx, login, password)
+	if err != nil {
+		core.recorder.Signin(metrics.SigninError)
+		return "", session.Session{}, false, fmt.Errorf("Signin err: %w", err)
+	}
+	if !found {
+		core.recorder.Signin(metrics.SigninBadPassword)
+		return "", session.Session{}, false, nil
+	}
+
+	sid, newSession, err := core.CreateSession(ctx, user.Login)
+	if err != nil || sid == "" {
+		core.recorder.Signin(metrics.SigninRedisDown)
+	} else {
+		core.recorder.Signin(metrics.SigninSuccess)
+	}
+
+	return sid, newSession, true, nil
+}
+
 func (core *Core) FindActiveSession(ctx context.Context, sid string) (bool, error) {
 	core.mutex.RLock()
-	found, err := core.sessions.CheckActiveSession(ctx, sid, core.lg)
+	found, err := core.sessions.CheckActiveSession(ctx, sid, logging.FromContext(ctx, core.lg))
 	core.mutex.RUnlock()
 
 	if err != nil {
@@ -144,7 +196,7 @@ func (core *Core) FindActiveSession(ctx context.Context, sid string) (bool, erro
 
 func (core *Core) KillSession(ctx context.Context, sid string) error {
 	core.mutex.Lock()
-	_, err := core.sessions.DeleteSession(ctx, sid, core.lg)
+	_, err := core.sessions.DeleteSession(ctx, sid, logging.FromContext(ctx, core.lg))
 	core.mutex.Unlock()
 
 	if err != nil {
@@ -154,32 +206,33 @@ func (core *Core) KillSession(ctx context.Context, sid string) error {
 	return nil
 }
 
-func (core *Core) CreateUserAccount(login string, password string, name string, birthDate string, email string) error {
+func (core *Core) CreateUserAccount(ctx context.Context, login string, password string, name string, birthDate string, email string) error {
 	if matched, _ := regexp.MatchString(`@`, email); !matched {
 		return InvalideEmail
 	}
-	err := core.users.CreateUser(login, password, name, birthDate, email)
+	err := core.users.CreateUser(ctx, login, password, name, birthDate, email)
 	if err != nil {
-		core.lg.Error("create user error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("create user error", "err", err.Error())
 		return fmt.Errorf("CreateUserAccount err: %w", err)
 	}
+	core.recorder.Signup()
 
 	return nil
 }
 
-func (core *Core) FindUserAccount(login string, password string) (*models.UserItem, bool, error) {
-	user, found, err := core.users.GetUser(login, password)
+func (core *Core) FindUserAccount(ctx context.Context, login string, password string) (*models.UserItem, bool, error) {
+	user, found, err := core.users.GetUser(ctx, login, password)
 	if err != nil {
-		core.lg.Error("find user error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("find user error", "err", err.Error())
 		return nil, false, fmt.Errorf("FindUserAccount err: %w", err)
 	}
 	return user, found, nil
 }
 
-func (core *Core) FindUserByLogin(login string) (bool, error) {
-	found, err := core.users.FindUser(login)
+func (core *Core) FindUserByLogin(ctx context.Context, login string) (bool, error) {
+	found, err := core.users.FindUser(ctx, login)
 	if err != nil {
-		core.lg.Error("find user error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("find user error", "err", err.Error())
 		return false, fmt.Errorf("FindUserByLogin err: %w", err)
 	}
 
@@ -194,10 +247,10 @@ func RandStringRunes(seed int) string {
 	return string(symbols)
 }
 
-func (core *Core) GetUserProfile(login string) (*models.UserItem, error) {
-	profile, err := core.users.GetUserProfile(login)
+func (core *Core) GetUserProfile(ctx context.Context, login string) (*models.UserItem, error) {
+	profile, err := core.users.GetUserProfile(ctx, login)
 	if err != nil {
-		core.lg.Error("GetUserProfile error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("GetUserProfile error", "err", err.Error())
 		return nil, fmt.Errorf("GetUserProfile err: %w", err)
 	}
 
@@ -206,7 +259,7 @@ func (core *Core) GetUserProfile(login string) (*models.UserItem, error) {
 
 func (core *Core) CheckCsrfToken(ctx context.Context, token string) (bool, error) {
 	core.mutex.RLock()
-	found, err := core.csrfTokens.CheckActiveCsrf(ctx, token, core.lg)
+	found, err := core.csrfTokens.CheckActiveCsrf(ctx, token, logging.FromContext(ctx, core.lg))
 	core.mutex.RUnlock()
 
 	if err != nil {
@@ -226,7 +279,7 @@ func (core *Core) CreateCsrfToken(ctx context.Context) (string, error) {
 			SID:       sid,
 			ExpiresAt: time.Now().Add(3 * time.Hour),
 		},
-		core.lg,
+		logging.FromContext(ctx, core.lg),
 	)
 	core.mutex.Unlock()
 
@@ -241,10 +294,10 @@ func (core *Core) CreateCsrfToken(ctx context.Context) (string, error) {
 	return sid, nil
 }
 
-func (core *Core) GetUserRole(login string) (string, error) {
-	role, err := core.users.GetUserRole(login)
+func (core *Core) GetUserRole(ctx context.Context, login string) (string, error) {
+	role, err := core.users.GetUserRole(ctx, login)
 	if err != nil {
-		core.lg.Error("get user role error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("get user role error", "err", err.Error())
 		return "", fmt.Errorf("get user role err: %w", err)
 	}
 
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/authorization/mocks"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
+	metricsMocks "github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics/mocks"
+	"github.com/golang/mock/gomock"
+)
+
+func TestSigninCsrf(t *testing.T) {
+	testCases := map[string]struct {
+		found   bool
+		repoErr error
+		result  string
+		err     bool
+	}{
+		"Valid token": {
+			found: true,
+		},
+		"Unknown or expired token": {},
+		"Redis down": {
+			repoErr: fmt.Errorf("connection refused"),
+			result:  metrics.SigninRedisDown,
+			err:     true,
+		},
+	}
+
+	for name, tc := range testCases {
+		mockCtrl := gomock.NewController(t)
+
+		csrfTokens := mocks.NewMockICsrfRepo(mockCtrl)
+		csrfTokens.EXPECT().CheckActiveCsrf(gomock.Any(), "token", gomock.Any()).Return(tc.found, tc.repoErr)
+		recorder := metricsMocks.NewMockIAuthRecorder(mockCtrl)
+		if tc.result != "" {
+			recorder.EXPECT().Signin(tc.result).Times(1)
+		}
+
+		var buff bytes.Buffer
+		core := Core{csrfTokens: csrfTokens, lg: slog.New(slog.NewJSONHandler(&buff, nil)), recorder: recorder}
+
+		err := core.SigninCsrf(context.Background(), "token")
+		if (err != nil) != tc.err {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+		mockCtrl.Finish()
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"os"
 
@@ -9,55 +10,90 @@ import (
 
 	delivery_auth_grpc "github.com/go-park-mail-ru/2023_2_Vkladyshi/authorization/delivery/grpc"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/authorization/usecase"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/lifecycle"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/middleware"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/tracing"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func main() {
-	logFile, _ := os.Create("auth_log.log")
-	lg := slog.New(slog.NewJSONHandler(logFile, nil))
+	config, err := configs.LoadAuth(os.Args[1:])
+	if err != nil {
+		slog.Error("read config error", "err", err.Error())
+		return
+	}
 
-	config, err := configs.ReadConfig()
+	levels, err := logging.GetLevels(config.Log)
 	if err != nil {
-		lg.Error("read config error", "err", err.Error())
+		slog.Error("logger error", "err", err.Error())
 		return
 	}
+	lg, logCloser, err := logging.New(config.Log, levels)
+	if err != nil {
+		slog.Error("logger error", "err", err.Error())
+		return
+	}
+	defer logCloser.Close()
+	defer levels.WatchSignal(lg)()
 
-	configCsrf, err := configs.ReadCsrfRedisConfig()
+	mt, err := metrics.NewMetrics(delivery_auth.Metrics.Name, prometheus.NewRegistry(), config.Metrics)
 	if err != nil {
-		lg.Error("read config error", "err", err.Error())
+		lg.Error("metrics error", "err", err.Error())
 		return
 	}
 
-	configSession, err := configs.ReadSessionRedisConfig()
+	shutdownTracing, err := tracing.Init("auth", config.Tracing)
 	if err != nil {
-		lg.Error("read config error", "err", err.Error())
+		lg.Error("tracing init error", "err", err.Error())
 		return
 	}
+	defer shutdownTracing(context.Background())
 
-	core, err := usecase.GetCore(config, *configCsrf, *configSession, lg)
+	accessLog, err := middleware.GetAccessLog(config.AccessLog, config.Log.Redact)
 	if err != nil {
-		lg.Error("cant create core")
+		lg.Error("access log error", "err", err.Error())
 		return
 	}
+	defer accessLog.Close()
 
-	api := delivery_auth.GetApi(core, lg)
+	app := lifecycle.GetManager(lg, config.ShutdownTimeout)
+	defer app.Close()
 
-	errs := make(chan error, 2)
+	core, err := usecase.GetCore(&config.DbDsnCfg, config.Csrf, config.Session, lg, mt)
+	if err != nil {
+		lg.Error("cant create core")
+		return
+	}
+	app.OnClose("core", core.Close)
+
+	api := delivery_auth.GetApi(core, lg, &config.DbDsnCfg, mt, accessLog, levels)
 
-	grpcServ, err := delivery_auth_grpc.NewServer(lg)
+	grpcServ, err := delivery_auth_grpc.NewServer(config, lg, mt)
 	if err != nil {
 		lg.Error("cant create server")
 		return
 	}
+	app.OnClose("grpc repos", grpcServ.Close)
 
-	go func() {
-		errs <- api.ListenAndServe()
-	}()
-	go func() {
-		errs <- grpcServ.ListenAndServeGrpc()
-	}()
+	reloader := configs.NewReloader(config, func() (*configs.AuthCfg, error) {
+		return configs.LoadAuth(os.Args[1:])
+	}, func(reloaded *configs.AuthCfg) error {
+		if err := api.Reconfigure(reloaded.Shared()); err != nil {
+			return err
+		}
+		core.Reconfigure(reloaded)
+		grpcServ.Reconfigure(reloaded)
+		app.SetTimeout(reloaded.ShutdownTimeout)
+		return nil
+	}, lg)
+	defer reloader.Watch(config.Files()...)()
 
-	err = <-errs
-	if err != nil {
+	app.Serve("http", api)
+	app.Serve("admin", api.Admin())
+	app.Serve("grpc", grpcServ)
+	if err := app.Run(); err != nil {
 		lg.Error("listen and serve error", "err", err.Error())
 	}
 }
@@ -1,40 +1,102 @@
 package main
 
 import (
-	"flag"
+	"context"
 	"log/slog"
 	"os"
 
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/authorization/authclient"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/comments/delivery"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/comments/repository/comment"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/comments/usecase"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/lifecycle"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/middleware"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/tracing"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func main() {
-	var path string
-	flag.StringVar(&path, "comments_log_path", "comment_log.log", "Путь к логу комментов")
-	logFile, _ := os.Create(path)
-	lg := slog.New(slog.NewJSONHandler(logFile, nil))
+	config, err := configs.LoadComments(os.Args[1:])
+	if err != nil {
+		slog.Error("read config error", "err", err.Error())
+		return
+	}
 
-	config, err := configs.ReadCommentConfig()
+	levels, err := logging.GetLevels(config.Log)
 	if err != nil {
-		lg.Error("read config error", "err", err.Error())
+		slog.Error("logger error", "err", err.Error())
 		return
 	}
+	lg, logCloser, err := logging.New(config.Log, levels)
+	if err != nil {
+		slog.Error("logger error", "err", err.Error())
+		return
+	}
+	defer logCloser.Close()
+	defer levels.WatchSignal(lg)()
+
+	mt, err := metrics.NewMetrics(delivery.Metrics.Name, prometheus.NewRegistry(), config.Metrics)
+	if err != nil {
+		lg.Error("metrics error", "err", err.Error())
+		return
+	}
+
+	shutdownTracing, err := tracing.Init("comments", config.Tracing)
+	if err != nil {
+		lg.Error("tracing init error", "err", err.Error())
+		return
+	}
+	defer shutdownTracing(context.Background())
+
+	accessLog, err := middleware.GetAccessLog(config.AccessLog, config.Log.Redact)
+	if err != nil {
+		lg.Error("access log error", "err", err.Error())
+		return
+	}
+	defer accessLog.Close()
+
+	app := lifecycle.GetManager(lg, config.ShutdownTimeout)
+	defer app.Close()
 
 	var comments comment.ICommentRepo
 	switch config.Comments_db {
 	case "postgres":
-		comments, err = comment.GetCommentRepo(config, lg)
+		comments, err = comment.GetCommentRepo(config, lg, mt)
 	}
 	if err != nil {
 		lg.Error("cant create repo")
 		return
 	}
+	app.OnClose("comments repo", comments.Close)
 
-	core := usecase.GetCore(config, lg, comments)
-	api := delivery.GetApi(core, lg, config)
+	authClient, err := authclient.GetClient(config.GrpcPort, mt)
+	if err != nil {
+		lg.Error("auth client error", "err", err.Error())
+		return
+	}
+	app.OnClose("auth client", authClient.Close)
+	mt.Health().AddCheck("auth", authClient.Ping)
 
-	api.ListenAndServe()
+	core := usecase.GetCore(authClient, lg, mt, comments)
+	api := delivery.GetApi(core, lg, config, mt, accessLog, levels)
+
+	reloader := configs.NewReloader(config, func() (*configs.CommentCfg, error) {
+		return configs.LoadComments(os.Args[1:])
+	}, func(reloaded *configs.CommentCfg) error {
+		if err := api.Reconfigure(reloaded.Shared()); err != nil {
+			return err
+		}
+		app.SetTimeout(reloaded.ShutdownTimeout)
+		return nil
+	}, lg)
+	defer reloader.Watch(config.Files()...)()
+
+	app.Serve("http", api)
+	app.Serve("admin", api.Admin())
+	if err := app.Run(); err != nil {
+		lg.Error("listen and serve error", "err", err.Error())
+	}
 }
@@ -0,0 +1,98 @@
+// Command configcheck loads the config of a service the way the service does,
+// prints the effective config with secrets masked and exits with 1 if it is
+// invalid. The arguments after the service are the flags of the service, "all"
+// checks every service:
+//
+//	PG_PASSWORD= REDIS_PASSWORD= go run ./cmd/configcheck all -profile prod
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+)
+
+type service struct {
+	name string
+	load func(args []string) (any, []string, error)
+}
+
+var services = []service{
+	{
+		name: "films",
+		load: func(args []string) (any, []string, error) {
+			config, err := configs.LoadFilms(args)
+			if err != nil {
+				return nil, nil, err
+			}
+			return config, config.Files(), nil
+		},
+	},
+	{
+		name: "comments",
+		load: func(args []string) (any, []string, error) {
+			config, err := configs.LoadComments(args)
+			if err != nil {
+				return nil, nil, err
+			}
+			return config, config.Files(), nil
+		},
+	},
+	{
+		name: "auth",
+		load: func(args []string) (any, []string, error) {
+			config, err := configs.LoadAuth(args)
+			if err != nil {
+				return nil, nil, err
+			}
+			return config, config.Files(), nil
+		},
+	},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	names := make([]string, 0, len(services))
+	for _, s := range services {
+		names = append(names, s.name)
+	}
+	if len(args) == 0 {
+		fmt.Fprintf(stderr, "usage: configcheck %s|all [-profile dev|staging|prod] [service flags]\n", strings.Join(names, "|"))
+		return 2
+	}
+
+	checked, code := 0, 0
+	for _, s := range services {
+		if args[0] != s.name && args[0] != "all" {
+			continue
+		}
+		checked++
+
+		config, files, err := s.load(args[1:])
+		if err != nil {
+			fmt.Fprintf(stderr, "%s\n", err)
+			code = 1
+			continue
+		}
+
+		out, err := configs.Masked(config)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s config err: %s\n", s.name, err)
+			code = 1
+			continue
+		}
+		fmt.Fprintf(stdout, "# %s: %s\n%s", s.name, strings.Join(files, " + "), out)
+	}
+
+	if checked == 0 {
+		fmt.Fprintf(stderr, "unknown service %q, expected one of %s or all\n", args[0], strings.Join(names, ", "))
+		return 2
+	}
+	return code
+}
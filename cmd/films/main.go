@@ -1,10 +1,11 @@
 package main
 
 import (
-	"flag"
+	"context"
 	"log/slog"
 	"os"
 
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/authorization/authclient"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/films/delivery"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/films/repository/calendar"
@@ -13,19 +14,56 @@ import (
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/films/repository/genre"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/films/repository/profession"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/films/usecase"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/lifecycle"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/middleware"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/tracing"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func main() {
-	var path string
-	flag.StringVar(&path, "films_log_path", "films_log.log", "Путь к логу фильмов")
-	logFile, _ := os.Create(path)
-	lg := slog.New(slog.NewJSONHandler(logFile, nil))
+	config, err := configs.LoadFilms(os.Args[1:])
+	if err != nil {
+		slog.Error("read config error", "err", err.Error())
+		return
+	}
 
-	config, err := configs.ReadFilmConfig()
+	levels, err := logging.GetLevels(config.Log)
 	if err != nil {
-		lg.Error("read config error", "err", err.Error())
+		slog.Error("logger error", "err", err.Error())
 		return
 	}
+	lg, logCloser, err := logging.New(config.Log, levels)
+	if err != nil {
+		slog.Error("logger error", "err", err.Error())
+		return
+	}
+	defer logCloser.Close()
+	defer levels.WatchSignal(lg)()
+
+	mt, err := metrics.NewMetrics(delivery.Metrics.Name, prometheus.NewRegistry(), config.Metrics)
+	if err != nil {
+		lg.Error("metrics error", "err", err.Error())
+		return
+	}
+
+	shutdownTracing, err := tracing.Init("films", config.Tracing)
+	if err != nil {
+		lg.Error("tracing init error", "err", err.Error())
+		return
+	}
+	defer shutdownTracing(context.Background())
+
+	accessLog, err := middleware.GetAccessLog(config.AccessLog, config.Log.Redact)
+	if err != nil {
+		lg.Error("access log error", "err", err.Error())
+		return
+	}
+	defer accessLog.Close()
+
+	app := lifecycle.GetManager(lg, config.ShutdownTimeout)
+	defer app.Close()
 
 	var (
 		films       film.IFilmsRepo
@@ -36,51 +74,79 @@ func main() {
 	)
 	switch config.Films_db {
 	case "postgres":
-		films, err = film.GetFilmRepo(config, lg)
+		films, err = film.GetFilmRepo(&config.DbDsnCfg, lg, mt)
 	}
 	if err != nil {
 		lg.Error("cant create repo")
 		return
 	}
+	app.OnClose("films repo", films.Close)
 
 	switch config.Genres_db {
 	case "postgres":
-		genres, err = genre.GetGenreRepo(config, lg)
+		genres, err = genre.GetGenreRepo(&config.DbDsnCfg, lg, mt)
 	}
 	if err != nil {
 		lg.Error("cant create repo")
 		return
 	}
+	app.OnClose("genres repo", genres.Close)
 
 	switch config.Crew_db {
 	case "postgres":
-		actors, err = crew.GetCrewRepo(config, lg)
+		actors, err = crew.GetCrewRepo(&config.DbDsnCfg, lg, mt)
 	}
 	if err != nil {
 		lg.Error("cant create repo")
 		return
 	}
+	app.OnClose("crew repo", actors.Close)
 
 	switch config.Profession_db {
 	case "postgres":
-		professions, err = profession.GetProfessionRepo(config, lg)
+		professions, err = profession.GetProfessionRepo(&config.DbDsnCfg, lg, mt)
 	}
 	if err != nil {
 		lg.Error("cant create repo")
 		return
 	}
+	app.OnClose("professions repo", professions.Close)
 
 	switch config.Calendar_db {
 	case "postgres":
-		news, err = calendar.GetCalendarRepo(config, lg)
+		news, err = calendar.GetCalendarRepo(&config.DbDsnCfg, lg, mt)
 	}
 	if err != nil {
 		lg.Error("cant creare calendar repo")
 		return
 	}
+	app.OnClose("calendar repo", news.Close)
 
-	core := usecase.GetCore(config, lg, films, genres, actors, professions, news)
-	api := delivery.GetApi(core, lg, config)
+	authClient, err := authclient.GetClient(config.DbDsnCfg.GrpcPort, mt)
+	if err != nil {
+		lg.Error("auth client error", "err", err.Error())
+		return
+	}
+	app.OnClose("auth client", authClient.Close)
+	mt.Health().AddCheck("auth", authClient.Ping)
 
-	api.ListenAndServe()
+	core := usecase.GetCore(authClient, lg, mt, films, genres, actors, professions, news)
+	api := delivery.GetApi(core, lg, &config.DbDsnCfg, mt, accessLog, levels)
+
+	reloader := configs.NewReloader(config, func() (*configs.FilmsCfg, error) {
+		return configs.LoadFilms(os.Args[1:])
+	}, func(reloaded *configs.FilmsCfg) error {
+		if err := api.Reconfigure(reloaded.Shared()); err != nil {
+			return err
+		}
+		app.SetTimeout(reloaded.ShutdownTimeout)
+		return nil
+	}, lg)
+	defer reloader.Watch(config.Files()...)()
+
+	app.Serve("http", api)
+	app.Serve("admin", api.Admin())
+	if err := app.Run(); err != nil {
+		lg.Error("listen and serve error", "err", err.Error())
+	}
 }
@@ -0,0 +1,113 @@
+// Command obsgen writes a Grafana dashboard and Prometheus alert rules for every
+// service from the metrics it registers and its metrics config.
+//
+// The configs are loaded like the services load them, so the variables they
+// reference have to be set:
+//
+//	PG_PASSWORD= go run ./cmd/obsgen -out deploy/observability
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	auth "github.com/go-park-mail-ru/2023_2_Vkladyshi/authorization/delivery/http"
+	comments "github.com/go-park-mail-ru/2023_2_Vkladyshi/comments/delivery"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	films "github.com/go-park-mail-ru/2023_2_Vkladyshi/films/delivery"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type service struct {
+	metrics metrics.Service
+	config  func() (configs.MetricsCfg, error)
+}
+
+var services = []service{
+	{
+		metrics: films.Metrics,
+		config: func() (configs.MetricsCfg, error) {
+			config, err := configs.LoadFilms(nil)
+			if err != nil {
+				return configs.MetricsCfg{}, err
+			}
+			return config.Metrics, nil
+		},
+	},
+	{
+		metrics: comments.Metrics,
+		config: func() (configs.MetricsCfg, error) {
+			config, err := configs.LoadComments(nil)
+			if err != nil {
+				return configs.MetricsCfg{}, err
+			}
+			return config.Metrics, nil
+		},
+	},
+	{
+		metrics: auth.Metrics,
+		config: func() (configs.MetricsCfg, error) {
+			config, err := configs.LoadAuth(nil)
+			if err != nil {
+				return configs.MetricsCfg{}, err
+			}
+			return config.Metrics, nil
+		},
+	},
+}
+
+func main() {
+	var out string
+	flag.StringVar(&out, "out", "deploy/observability", "directory for the dashboards and alert rules")
+	flag.Parse()
+
+	lg := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	for _, dir := range []string{"dashboards", "rules"} {
+		if err := os.MkdirAll(filepath.Join(out, dir), 0o755); err != nil {
+			lg.Error("create dir error", "err", err.Error())
+			os.Exit(1)
+		}
+	}
+
+	for _, s := range services {
+		lg := lg.With("service", s.metrics.Name)
+
+		cfg, err := s.config()
+		if err != nil {
+			lg.Error("read config error", "err", err.Error())
+			os.Exit(1)
+		}
+		mt, err := metrics.NewMetrics(s.metrics.Name, prometheus.NewRegistry(), cfg)
+		if err != nil {
+			lg.Error("metrics error", "err", err.Error())
+			os.Exit(1)
+		}
+
+		dashboard, err := mt.Dashboard(s.metrics.Components...)
+		if err != nil {
+			lg.Error("dashboard error", "err", err.Error())
+			os.Exit(1)
+		}
+		rules, err := mt.AlertRules(s.metrics.Components...)
+		if err != nil {
+			lg.Error("alert rules error", "err", err.Error())
+			os.Exit(1)
+		}
+
+		files := map[string][]byte{
+			filepath.Join(out, "dashboards", s.metrics.Name+".json"): dashboard,
+			filepath.Join(out, "rules", s.metrics.Name+".yaml"):      rules,
+		}
+		for path, content := range files {
+			if err := os.WriteFile(path, content, 0o644); err != nil {
+				lg.Error("write file error", "err", err.Error())
+				os.Exit(1)
+			}
+			lg.Info("written", "path", path)
+		}
+	}
+}
@@ -6,60 +6,57 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/comments/usecase"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/httpserver"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/middleware"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/requests"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-type API struct {
-	core   usecase.ICore
-	lg     *slog.Logger
-	mx     *http.ServeMux
-	mt     *metrics.Metrics
-	adress string
+// Metrics are the metric components the service wires up, its dashboard and
+// alert rules are generated from them.
+var Metrics = metrics.Service{
+	Name:       "comments",
+	Components: []string{metrics.ComponentHTTP, metrics.ComponentDB, metrics.ComponentGrpcClient, metrics.ComponentCommentsKPI},
 }
 
-func GetApi(c *usecase.Core, l *slog.Logger, cfg *configs.CommentCfg) *API {
+type API struct {
+	core usecase.ICore
+	lg   *slog.Logger
+	mx   *http.ServeMux
+	*httpserver.Server
+}
 
+func GetApi(c *usecase.Core, l *slog.Logger, cfg *configs.CommentCfg, mt *metrics.Metrics, al *middleware.AccessLog, levels *logging.Levels) *API {
 	api := &API{
-		core:   c,
-		lg:     l.With("module", "api"),
-		mx:     http.NewServeMux(),
-		mt:     metrics.GetMetrics(),
-		adress: cfg.ServerAdress,
+		core: c,
+		lg:   l.With("module", "api"),
+		mx:   http.NewServeMux(),
 	}
 
-	api.mx.Handle("/metrics", promhttp.Handler())
 	api.mx.HandleFunc("/api/v1/comment", api.Comment)
 	api.mx.HandleFunc("/api/v1/comment/add", api.AddComment)
 
-	return api
-}
+	api.Server = httpserver.GetServer(api.mx, cfg.ServerAdress, cfg.AdminAdress, cfg.Http, l, mt, al, levels)
 
-func (a *API) ListenAndServe() {
-	err := http.ListenAndServe(a.adress, a.mx)
-	if err != nil {
-		a.lg.Error("listen and serve error", "err", err.Error())
-	}
+	return api
 }
 
 func (a *API) Comment(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
-	start := time.Now()
 	if r.Method != http.MethodGet {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	filmId, err := strconv.ParseUint(r.URL.Query().Get("film_id"), 10, 64)
 	if err != nil {
 		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 	page, err := strconv.ParseUint(r.URL.Query().Get("page"), 10, 64)
@@ -71,11 +68,11 @@ func (a *API) Comment(w http.ResponseWriter, r *http.Request) {
 		pageSize = 10
 	}
 
-	comments, err := a.core.GetFilmComments(filmId, (page-1)*pageSize, pageSize)
+	comments, err := a.core.GetFilmComments(r.Context(), filmId, (page-1)*pageSize, pageSize)
 	if err != nil {
-		a.lg.Error("Comment", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("Comment", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -83,36 +80,35 @@ func (a *API) Comment(w http.ResponseWriter, r *http.Request) {
 
 	response.Body = commentsResponse
 
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) AddComment(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
-	start := time.Now()
 	if r.Method != http.MethodPost {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	session, err := r.Cookie("session_id")
 	if err == http.ErrNoCookie {
 		response.Status = http.StatusUnauthorized
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 	if err != nil {
-		a.lg.Error("Add comment error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("Add comment error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	userId, err := a.core.GetUserId(r.Context(), session.Value)
 	if err != nil {
-		a.lg.Error("Add comment error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("Add comment error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -120,27 +116,27 @@ func (a *API) AddComment(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		response.Status = requests.BodyStatus(err)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	if err = json.Unmarshal(body, &commentRequest); err != nil {
 		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	found, err := a.core.AddComment(commentRequest.FilmId, userId, commentRequest.Rating, commentRequest.Text)
+	found, err := a.core.AddComment(r.Context(), commentRequest.FilmId, userId, commentRequest.Rating, commentRequest.Text)
 	if err != nil {
-		a.lg.Error("Add Comment error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("Add Comment error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
 	}
 	if found {
 		response.Status = http.StatusNotAcceptable
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
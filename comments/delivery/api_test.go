@@ -24,6 +24,32 @@ func createBody(req requests.CommentRequest) io.Reader {
 	return body
 }
 
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder) requests.Response {
+	t.Helper()
+
+	var response requests.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("cant decode response: %s", err)
+	}
+
+	return response
+}
+
+func jsonValue(t *testing.T, body any) any {
+	t.Helper()
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("cant encode body: %s", err)
+	}
+
+	var value any
+	if err := json.Unmarshal(jsonBody, &value); err != nil {
+		t.Fatalf("cant decode body: %s", err)
+	}
+
+	return value
+}
 
 func TestComment(t *testing.T) {
 	testCases := map[string]struct {
@@ -52,7 +78,7 @@ func TestComment(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockCore := mocks.NewMockICore(mockCtrl)
-	mockCore.EXPECT().GetFilmComments(uint64(0), uint64(0), uint64(10)).Return(nil, fmt.Errorf("core_err")).Times(1)
+	mockCore.EXPECT().GetFilmComments(gomock.Any(), uint64(0), uint64(0), uint64(10)).Return(nil, fmt.Errorf("core_err")).Times(1)
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 
@@ -67,16 +93,16 @@ func TestComment(t *testing.T) {
 		r.URL.RawQuery = q.Encode()
 		w := httptest.NewRecorder()
 
-        response := requests.Response{Status: http.StatusBadRequest, Body: nil}
-
 		api.Comment(w, r)
 
+		response := decodeResponse(t, w)
+
 		if response.Status != curr.result.Status {
 			t.Errorf("unexpected status: %d", response.Status)
 			return
 		}
-		if !reflect.DeepEqual(response.Body, curr.result.Body) {
-			t.Errorf("wanted %v, got %v", curr.result.Body, response.Body)
+		if !reflect.DeepEqual(response.Body, jsonValue(t, curr.result.Body)) {
+			t.Errorf("wanted %v, got %v", jsonValue(t, curr.result.Body), response.Body)
 			return
 		}
 	}
@@ -145,16 +171,14 @@ func TestCommentAdd(t *testing.T) {
 	mockCore := mocks.NewMockICore(mockCtrl)
 	mockCore.EXPECT().GetUserId(context.Background(), string("sid1")).Return(uint64(0), fmt.Errorf("core_err")).Times(1)
 	mockCore.EXPECT().GetUserId(context.Background(), string("sid2")).Return(uint64(1), nil).Times(4)
-	mockCore.EXPECT().AddComment(uint64(1), uint64(1), uint16(10), string("")).Return(false, fmt.Errorf("core_err")).Times(1)
-	mockCore.EXPECT().AddComment(uint64(2), uint64(1), uint16(10), string("")).Return(true, nil).Times(1)
-	mockCore.EXPECT().AddComment(uint64(3), uint64(1), uint16(10), string("")).Return(false, nil).Times(1)
+	mockCore.EXPECT().AddComment(gomock.Any(), uint64(1), uint64(1), uint16(10), string("")).Return(false, fmt.Errorf("core_err")).Times(1)
+	mockCore.EXPECT().AddComment(gomock.Any(), uint64(2), uint64(1), uint16(10), string("")).Return(true, nil).Times(1)
+	mockCore.EXPECT().AddComment(gomock.Any(), uint64(3), uint64(1), uint16(10), string("")).Return(false, nil).Times(1)
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 
 	api := API{core: mockCore, lg: logger}
 
-	response := requests.Response{Status: http.StatusBadRequest, Body: nil}
-
 	for _, curr := range testCases {
 		r := httptest.NewRequest(curr.method, "/api/v1/comment/add", curr.body)
 		w := httptest.NewRecorder()
@@ -164,6 +188,8 @@ func TestCommentAdd(t *testing.T) {
 		}
 		api.AddComment(w, r)
 
+		response := decodeResponse(t, w)
+
 		if response.Status != curr.result.Status {
 			fmt.Println(api.lg)
 			t.Errorf("unexpected status: %d, wanted: %d", response.Status, curr.result.Status)
@@ -36,33 +36,33 @@ func (m *MockICore) EXPECT() *MockICoreMockRecorder {
 }
 
 // AddComment mocks base method.
-func (m *MockICore) AddComment(filmId, userId uint64, rating uint16, text string) (bool, error) {
+func (m *MockICore) AddComment(ctx context.Context, filmId, userId uint64, rating uint16, text string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddComment", filmId, userId, rating, text)
+	ret := m.ctrl.Call(m, "AddComment", ctx, filmId, userId, rating, text)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddComment indicates an expected call of AddComment.
-func (mr *MockICoreMockRecorder) AddComment(filmId, userId, rating, text interface{}) *gomock.Call {
+func (mr *MockICoreMockRecorder) AddComment(ctx, filmId, userId, rating, text interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddComment", reflect.TypeOf((*MockICore)(nil).AddComment), filmId, userId, rating, text)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddComment", reflect.TypeOf((*MockICore)(nil).AddComment), ctx, filmId, userId, rating, text)
 }
 
 // GetFilmComments mocks base method.
-func (m *MockICore) GetFilmComments(filmId, first, limit uint64) ([]models.CommentItem, error) {
+func (m *MockICore) GetFilmComments(ctx context.Context, filmId, first, limit uint64) ([]models.CommentItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilmComments", filmId, first, limit)
+	ret := m.ctrl.Call(m, "GetFilmComments", ctx, filmId, first, limit)
 	ret0, _ := ret[0].([]models.CommentItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFilmComments indicates an expected call of GetFilmComments.
-func (mr *MockICoreMockRecorder) GetFilmComments(filmId, first, limit interface{}) *gomock.Call {
+func (mr *MockICoreMockRecorder) GetFilmComments(ctx, filmId, first, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmComments", reflect.TypeOf((*MockICore)(nil).GetFilmComments), filmId, first, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmComments", reflect.TypeOf((*MockICore)(nil).GetFilmComments), ctx, filmId, first, limit)
 }
 
 // GetUserId mocks base method.
@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	models "github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
@@ -35,45 +36,59 @@ func (m *MockICommentRepo) EXPECT() *MockICommentRepoMockRecorder {
 }
 
 // AddComment mocks base method.
-func (m *MockICommentRepo) AddComment(filmId, userId uint64, rating uint16, text string) error {
+func (m *MockICommentRepo) AddComment(ctx context.Context, filmId, userId uint64, rating uint16, text string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddComment", filmId, userId, rating, text)
+	ret := m.ctrl.Call(m, "AddComment", ctx, filmId, userId, rating, text)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddComment indicates an expected call of AddComment.
-func (mr *MockICommentRepoMockRecorder) AddComment(filmId, userId, rating, text interface{}) *gomock.Call {
+func (mr *MockICommentRepoMockRecorder) AddComment(ctx, filmId, userId, rating, text interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddComment", reflect.TypeOf((*MockICommentRepo)(nil).AddComment), filmId, userId, rating, text)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddComment", reflect.TypeOf((*MockICommentRepo)(nil).AddComment), ctx, filmId, userId, rating, text)
+}
+
+// Close mocks base method.
+func (m *MockICommentRepo) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockICommentRepoMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockICommentRepo)(nil).Close))
 }
 
 // GetFilmComments mocks base method.
-func (m *MockICommentRepo) GetFilmComments(filmId, first, limit uint64) ([]models.CommentItem, error) {
+func (m *MockICommentRepo) GetFilmComments(ctx context.Context, filmId, first, limit uint64) ([]models.CommentItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilmComments", filmId, first, limit)
+	ret := m.ctrl.Call(m, "GetFilmComments", ctx, filmId, first, limit)
 	ret0, _ := ret[0].([]models.CommentItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFilmComments indicates an expected call of GetFilmComments.
-func (mr *MockICommentRepoMockRecorder) GetFilmComments(filmId, first, limit interface{}) *gomock.Call {
+func (mr *MockICommentRepoMockRecorder) GetFilmComments(ctx, filmId, first, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmComments", reflect.TypeOf((*MockICommentRepo)(nil).GetFilmComments), filmId, first, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmComments", reflect.TypeOf((*MockICommentRepo)(nil).GetFilmComments), ctx, filmId, first, limit)
 }
 
 // HasUsersComment mocks base method.
-func (m *MockICommentRepo) HasUsersComment(userId, filmId uint64) (bool, error) {
+func (m *MockICommentRepo) HasUsersComment(ctx context.Context, userId, filmId uint64) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "HasUsersComment", userId, filmId)
+	ret := m.ctrl.Call(m, "HasUsersComment", ctx, userId, filmId)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // HasUsersComment indicates an expected call of HasUsersComment.
-func (mr *MockICommentRepoMockRecorder) HasUsersComment(userId, filmId interface{}) *gomock.Call {
+func (mr *MockICommentRepoMockRecorder) HasUsersComment(ctx, userId, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasUsersComment", reflect.TypeOf((*MockICommentRepo)(nil).HasUsersComment), userId, filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasUsersComment", reflect.TypeOf((*MockICommentRepo)(nil).HasUsersComment), ctx, userId, filmId)
 }
@@ -1,13 +1,14 @@
 package comment
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log/slog"
-	"time"
 
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
 
 	_ "github.com/jackc/pgx/stdlib"
@@ -16,16 +17,18 @@ import (
 //go:generate mockgen -source=repo_comment.go -destination=../../mocks/repo_mock.go -package=mocks
 
 type ICommentRepo interface {
-	GetFilmComments(filmId uint64, first uint64, limit uint64) ([]models.CommentItem, error)
-	AddComment(filmId uint64, userId uint64, rating uint16, text string) error
-	HasUsersComment(userId uint64, filmId uint64) (bool, error)
+	GetFilmComments(ctx context.Context, filmId uint64, first uint64, limit uint64) ([]models.CommentItem, error)
+	AddComment(ctx context.Context, filmId uint64, userId uint64, rating uint16, text string) error
+	HasUsersComment(ctx context.Context, userId uint64, filmId uint64) (bool, error)
+	Close() error
 }
 
 type RepoPostgre struct {
 	db *sql.DB
+	mt *metrics.DBMetrics
 }
 
-func GetCommentRepo(config *configs.CommentCfg, lg *slog.Logger) (*RepoPostgre, error) {
+func GetCommentRepo(config *configs.CommentCfg, lg *slog.Logger, mt *metrics.Metrics) (*RepoPostgre, error) {
 	dsn := fmt.Sprintf("user=%s dbname=%s password= %s host=%s port=%d sslmode=%s",
 		config.User, config.DbName, config.Password, config.Host, config.Port, config.Sslmode)
 	db, err := sql.Open("pgx", dsn)
@@ -33,34 +36,28 @@ func GetCommentRepo(config *configs.CommentCfg, lg *slog.Logger) (*RepoPostgre,
 		lg.Error("sql open error", "err", err.Error())
 		return nil, fmt.Errorf("get comment repo: %w", err)
 	}
-	err = db.Ping()
+	db.SetMaxOpenConns(config.MaxOpenConns)
+
+	dbMetrics, err := mt.OpenDB("comment", db, config.Timer, lg)
 	if err != nil {
 		lg.Error("sql ping error", "err", err.Error())
 		return nil, fmt.Errorf("get comment repo: %w", err)
 	}
-	db.SetMaxOpenConns(config.MaxOpenConns)
 
-	postgreDb := RepoPostgre{db: db}
-
-	go postgreDb.pingDb(config.Timer, lg)
-	return &postgreDb, nil
+	return &RepoPostgre{db: db, mt: dbMetrics}, nil
 }
 
-func (repo *RepoPostgre) pingDb(timer uint32, lg *slog.Logger) {
-	for {
-		err := repo.db.Ping()
-		if err != nil {
-			lg.Error("repo comment db ping error", "err", err.Error())
-		}
-
-		time.Sleep(time.Duration(timer) * time.Second)
-	}
+func (repo *RepoPostgre) Close() error {
+	return repo.mt.Close()
 }
 
-func (repo *RepoPostgre) GetFilmComments(filmId uint64, first uint64, limit uint64) ([]models.CommentItem, error) {
+func (repo *RepoPostgre) GetFilmComments(ctx context.Context, filmId uint64, first uint64, limit uint64) ([]models.CommentItem, error) {
+	ctx, done := repo.mt.Query(ctx, "GetFilmComments")
+	defer done()
+
 	comments := []models.CommentItem{}
 
-	rows, err := repo.db.Query(
+	rows, err := repo.db.QueryContext(ctx,
 		"SELECT id_user, rating, comment FROM users_comment "+
 			"WHERE id_film = $1 "+
 			"OFFSET $2 LIMIT $3", filmId, first, limit)
@@ -81,8 +78,11 @@ func (repo *RepoPostgre) GetFilmComments(filmId uint64, first uint64, limit uint
 	return comments, nil
 }
 
-func (repo *RepoPostgre) AddComment(filmId uint64, userId uint64, rating uint16, text string) error {
-	_, err := repo.db.Exec(
+func (repo *RepoPostgre) AddComment(ctx context.Context, filmId uint64, userId uint64, rating uint16, text string) error {
+	ctx, done := repo.mt.Query(ctx, "AddComment")
+	defer done()
+
+	_, err := repo.db.ExecContext(ctx,
 		"INSERT INTO users_comment(id_film, rating, comment, id_user) "+
 			"VALUES($1, $2, $3, $4)", filmId, rating, text, userId)
 	if err != nil {
@@ -92,9 +92,12 @@ func (repo *RepoPostgre) AddComment(filmId uint64, userId uint64, rating uint16,
 	return nil
 }
 
-func (repo *RepoPostgre) HasUsersComment(userId uint64, filmId uint64) (bool, error) {
+func (repo *RepoPostgre) HasUsersComment(ctx context.Context, userId uint64, filmId uint64) (bool, error) {
+	ctx, done := repo.mt.Query(ctx, "HasUsersComment")
+	defer done()
+
 	var id uint64
-	err := repo.db.QueryRow(
+	err := repo.db.QueryRowContext(ctx,
 		"SELECT id_user FROM users_comment "+
 			"WHERE id_user = $1 AND id_film = $2", userId, filmId).Scan(&id)
 	if err != nil {
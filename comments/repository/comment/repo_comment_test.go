@@ -1,6 +1,7 @@
 package comment
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
@@ -37,7 +38,7 @@ func TestGetFilmComments(t *testing.T) {
 		db: db,
 	}
 
-	comments, err := repo.GetFilmComments(1, 0, 5)
+	comments, err := repo.GetFilmComments(context.Background(), 1, 0, 5)
 	if err != nil {
 		t.Errorf("GetFilm error: %s", err)
 	}
@@ -57,7 +58,7 @@ func TestGetFilmComments(t *testing.T) {
 		WithArgs(1, 0, 5).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	comments, err = repo.GetFilmComments(1, 0, 5)
+	comments, err = repo.GetFilmComments(context.Background(), 1, 0, 5)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -96,7 +97,7 @@ func TestAddComment(t *testing.T) {
 		db: db,
 	}
 
-	err = repo.AddComment(testComment.IdFilm, idUser, testComment.Rating, testComment.Comment)
+	err = repo.AddComment(context.Background(), testComment.IdFilm, idUser, testComment.Rating, testComment.Comment)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err)
 		return
@@ -111,7 +112,7 @@ func TestAddComment(t *testing.T) {
 		WithArgs(testComment.IdFilm, testComment.Rating, testComment.Comment, idUser).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	err = repo.AddComment(testComment.IdFilm, idUser, testComment.Rating, testComment.Comment)
+	err = repo.AddComment(context.Background(), testComment.IdFilm, idUser, testComment.Rating, testComment.Comment)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -146,7 +147,7 @@ func TestHasUsersComment(t *testing.T) {
 		db: db,
 	}
 
-	found, err := repo.HasUsersComment(idUser, idFilm)
+	found, err := repo.HasUsersComment(context.Background(), idUser, idFilm)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err)
 		return
@@ -165,7 +166,7 @@ func TestHasUsersComment(t *testing.T) {
 		WithArgs(idUser, idFilm).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	found, err = repo.HasUsersComment(idUser, idFilm)
+	found, err = repo.HasUsersComment(context.Background(), idUser, idFilm)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -184,7 +185,7 @@ func TestHasUsersComment(t *testing.T) {
 		WithArgs(idUser, idFilm).
 		WillReturnError(sql.ErrNoRows)
 
-	found, err = repo.HasUsersComment(idUser, idFilm)
+	found, err = repo.HasUsersComment(context.Background(), idUser, idFilm)
 	if err != nil {
 		t.Errorf("waited no errors")
 		return
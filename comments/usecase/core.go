@@ -7,17 +7,16 @@ import (
 
 	auth "github.com/go-park-mail-ru/2023_2_Vkladyshi/authorization/proto"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/comments/repository/comment"
-	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 //go:generate mockgen -source=core.go -destination=../mocks/core_mock.go -package=mocks
 
 type ICore interface {
-	GetFilmComments(filmId uint64, first uint64, limit uint64) ([]models.CommentItem, error)
-	AddComment(filmId uint64, userId uint64, rating uint16, text string) (bool, error)
+	GetFilmComments(ctx context.Context, filmId uint64, first uint64, limit uint64) ([]models.CommentItem, error)
+	AddComment(ctx context.Context, filmId uint64, userId uint64, rating uint16, text string) (bool, error)
 	GetUserId(ctx context.Context, sid string) (uint64, error)
 }
 
@@ -25,36 +24,23 @@ type Core struct {
 	lg       *slog.Logger
 	comments comment.ICommentRepo
 	client   auth.AuthorizationClient
+	recorder metrics.ICommentsRecorder
 }
 
-func GetClient(port string) (auth.AuthorizationClient, error) {
-	conn, err := grpc.Dial(port, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return nil, fmt.Errorf("grpc connect err: %w", err)
-	}
-	client := auth.NewAuthorizationClient(conn)
-
-	return client, nil
-}
-
-func GetCore(cfg_sql *configs.CommentCfg, lg *slog.Logger, comments comment.ICommentRepo) *Core {
-	client, err := GetClient(cfg_sql.GrpcPort)
-	if err != nil {
-		lg.Error("get client error", "err", err.Error())
-		return nil
-	}
+func GetCore(client auth.AuthorizationClient, lg *slog.Logger, mt *metrics.Metrics, comments comment.ICommentRepo) *Core {
 	core := Core{
 		lg:       lg.With("module", "core"),
 		comments: comments,
 		client:   client,
+		recorder: mt.Business(),
 	}
 	return &core
 }
 
-func (core *Core) GetFilmComments(filmId uint64, first uint64, limit uint64) ([]models.CommentItem, error) {
-	comments, err := core.comments.GetFilmComments(filmId, first, limit)
+func (core *Core) GetFilmComments(ctx context.Context, filmId uint64, first uint64, limit uint64) ([]models.CommentItem, error) {
+	comments, err := core.comments.GetFilmComments(ctx, filmId, first, limit)
 	if err != nil {
-		core.lg.Error("Get Film Comments error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("Get Film Comments error", "err", err.Error())
 		return nil, fmt.Errorf("GetFilmComments err: %w", err)
 	}
 	ids := make([]int32, len(comments))
@@ -62,9 +48,9 @@ func (core *Core) GetFilmComments(filmId uint64, first uint64, limit uint64) ([]
 		ids[i] = int32(comments[i].IdUser)
 	}
 
-	namesAndPhotos, err := core.client.GetIdsAndPaths(context.Background(), &auth.NamesAndPathsListRequest{Ids: ids})
+	namesAndPhotos, err := core.client.GetIdsAndPaths(ctx, &auth.NamesAndPathsListRequest{Ids: ids})
 	if err != nil {
-		core.lg.Error("get film comments grpc error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("get film comments grpc error", "err", err.Error())
 		return nil, fmt.Errorf("get film comments grpc err: %w", err)
 	}
 	for i := 0; i < len(namesAndPhotos.Names); i++ {
@@ -74,21 +60,22 @@ func (core *Core) GetFilmComments(filmId uint64, first uint64, limit uint64) ([]
 	return comments, nil
 }
 
-func (core *Core) AddComment(filmId uint64, userId uint64, rating uint16, text string) (bool, error) {
-	found, err := core.comments.HasUsersComment(userId, filmId)
+func (core *Core) AddComment(ctx context.Context, filmId uint64, userId uint64, rating uint16, text string) (bool, error) {
+	found, err := core.comments.HasUsersComment(ctx, userId, filmId)
 	if err != nil {
-		core.lg.Error("find users comment error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("find users comment error", "err", err.Error())
 		return false, fmt.Errorf("find users comment error: %w", err)
 	}
 	if found {
 		return found, nil
 	}
 
-	err = core.comments.AddComment(filmId, userId, rating, text)
+	err = core.comments.AddComment(ctx, filmId, userId, rating, text)
 	if err != nil {
-		core.lg.Error("add Comment error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("add Comment error", "err", err.Error())
 		return false, fmt.Errorf("add comment err: %w", err)
 	}
+	core.recorder.CommentAdded()
 
 	return false, nil
 }
@@ -98,7 +85,7 @@ func (core *Core) GetUserId(ctx context.Context, sid string) (uint64, error) {
 
 	response, err := core.client.GetId(ctx, &request)
 	if err != nil {
-		core.lg.Error("get user id error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("get user id error", "err", err.Error())
 		return 0, fmt.Errorf("get user id err: %w", err)
 	}
 	return uint64(response.Value), nil
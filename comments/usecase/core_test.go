@@ -2,11 +2,13 @@ package usecase
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log/slog"
 	"testing"
 
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/comments/mocks"
+	metricsMocks "github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics/mocks"
 	"github.com/golang/mock/gomock"
 )
 
@@ -15,18 +17,21 @@ func TestAddComment(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockObj := mocks.NewMockICommentRepo(mockCtrl)
-	mockObj.EXPECT().HasUsersComment(uint64(1), uint64(1)).Return(false, nil)
-	mockObj.EXPECT().HasUsersComment(uint64(0), uint64(1)).Return(false, fmt.Errorf("repo_error"))
-	mockObj.EXPECT().HasUsersComment(uint64(2), uint64(1)).Return(true, nil)
-	mockObj.EXPECT().HasUsersComment(uint64(2), uint64(2)).Return(false, nil)
-	mockObj.EXPECT().AddComment(uint64(1), uint64(1), uint16(1), string("t")).Return(nil)
-	mockObj.EXPECT().AddComment(uint64(2), uint64(2), uint16(1), string("t")).Return(fmt.Errorf("repo_error"))
+	mockObj.EXPECT().HasUsersComment(gomock.Any(), uint64(1), uint64(1)).Return(false, nil)
+	mockObj.EXPECT().HasUsersComment(gomock.Any(), uint64(0), uint64(1)).Return(false, fmt.Errorf("repo_error"))
+	mockObj.EXPECT().HasUsersComment(gomock.Any(), uint64(2), uint64(1)).Return(true, nil)
+	mockObj.EXPECT().HasUsersComment(gomock.Any(), uint64(2), uint64(2)).Return(false, nil)
+	mockObj.EXPECT().AddComment(gomock.Any(), uint64(1), uint64(1), uint16(1), string("t")).Return(nil)
+	mockObj.EXPECT().AddComment(gomock.Any(), uint64(2), uint64(2), uint16(1), string("t")).Return(fmt.Errorf("repo_error"))
 
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
-	core := Core{comments: mockObj, lg: logger}
+	recorder := metricsMocks.NewMockICommentsRecorder(mockCtrl)
+	recorder.EXPECT().CommentAdded().Times(1)
 
-	found, err := core.AddComment(1, 1, 1, "t")
+	core := Core{comments: mockObj, lg: logger, recorder: recorder}
+
+	found, err := core.AddComment(context.Background(), 1, 1, 1, "t")
 	if err != nil {
 		t.Errorf("waited no errors")
 		return
@@ -36,7 +41,7 @@ func TestAddComment(t *testing.T) {
 		return
 	}
 
-	found, err = core.AddComment(1, 0, 1, "t")
+	found, err = core.AddComment(context.Background(), 1, 0, 1, "t")
 	if err == nil {
 		t.Errorf("waited error")
 		return
@@ -46,7 +51,7 @@ func TestAddComment(t *testing.T) {
 		return
 	}
 
-	found, err = core.AddComment(1, 2, 1, "t")
+	found, err = core.AddComment(context.Background(), 1, 2, 1, "t")
 	if err != nil {
 		t.Errorf("waited no errors")
 		return
@@ -56,7 +61,7 @@ func TestAddComment(t *testing.T) {
 		return
 	}
 
-	found, err = core.AddComment(2, 2, 1, "t")
+	found, err = core.AddComment(context.Background(), 2, 2, 1, "t")
 	if err == nil {
 		t.Errorf("waited find error")
 		return
@@ -1,158 +1,198 @@
 package configs
 
 import (
-	"flag"
-	"os"
-
-	"gopkg.in/yaml.v2"
+	"time"
 )
 
 type DbDsnCfg struct {
-	User          string `yaml:"user"`
-	DbName        string `yaml:"dbname"`
-	Password      string `yaml:"password"`
-	Host          string `yaml:"host"`
-	Port          int    `yaml:"port"`
-	Sslmode       string `yaml:"sslmode"`
-	MaxOpenConns  int    `yaml:"max_open_conns"`
-	Timer         uint32 `yaml:"timer"`
-	Films_db      string `yaml:"films_db"`
-	Genres_db     string `yaml:"genres_db"`
-	Crew_db       string `yaml:"crew_db"`
-	Profession_db string `yaml:"profession_db"`
-	Calendar_db   string `yaml:"calendar_db"`
-	ServerAdress  string `yaml:"server_adress"`
-	GrpcPort      string `yaml:"grpc_port"`
+	Environment     string        `yaml:"environment"`
+	User            string        `yaml:"user" env:"DB_USER"`
+	DbName          string        `yaml:"dbname" env:"DB_NAME"`
+	Password        string        `yaml:"password" env:"DB_PASSWORD" secret:"true"`
+	PasswordFile    string        `yaml:"password_file" env:"DB_PASSWORD_FILE"`
+	Host            string        `yaml:"host" env:"DB_HOST"`
+	Port            int           `yaml:"port" env:"DB_PORT"`
+	Sslmode         string        `yaml:"sslmode" env:"DB_SSLMODE"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	Timer           uint32        `yaml:"timer"`
+	Films_db        string        `yaml:"films_db"`
+	Genres_db       string        `yaml:"genres_db"`
+	Crew_db         string        `yaml:"crew_db"`
+	Profession_db   string        `yaml:"profession_db"`
+	Calendar_db     string        `yaml:"calendar_db"`
+	ServerAdress    string        `yaml:"server_adress"`
+	AdminAdress     string        `yaml:"admin_adress"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" reload:"true"`
+	GrpcPort        string        `yaml:"grpc_port"`
+	Http            HttpCfg       `yaml:"http"`
+	Metrics         MetricsCfg    `yaml:"metrics"`
+	Tracing         TracingCfg    `yaml:"tracing"`
+	AccessLog       AccessLogCfg  `yaml:"access_log"`
+	Log             LogCfg        `yaml:"log"`
+
+	files []string
 }
 
-type CommentCfg struct {
-	User         string `yaml:"user"`
-	DbName       string `yaml:"dbname"`
-	Password     string `yaml:"password"`
-	Host         string `yaml:"host"`
-	Port         int    `yaml:"port"`
-	Sslmode      string `yaml:"sslmode"`
-	MaxOpenConns int    `yaml:"max_open_conns"`
-	Timer        uint32 `yaml:"timer"`
-	Comments_db  string `yaml:"comment_db"`
-	ServerAdress string `yaml:"server_adress"`
-	GrpcPort     string `yaml:"grpc_port"`
+// Files are the YAML file the config was read from and its profile overlay.
+func (cfg *DbDsnCfg) Files() []string {
+	return cfg.files
 }
 
-type DbRedisCfg struct {
-	Host     string `yaml:"host"`
-	Password string `yaml:"password"`
-	DbNumber int    `yaml:"db"`
-	Timer    int    `yaml:"timer"`
+func (cfg *DbDsnCfg) setFiles(files []string) {
+	cfg.files = files
 }
 
-type GrpcConfig struct {
-	Port           string `yaml:"port"`
-	ConnectionType string `yaml:"connection_type"`
+// FilmsCfg is DbDsnCfg as the films service uses it. Auth shares DbDsnCfg but
+// has no film storages to validate.
+type FilmsCfg struct {
+	DbDsnCfg `yaml:",inline"`
 }
 
-func ReadGrpcConfig() (*GrpcConfig, error) {
-	flag.Parse()
-	var path string
-	flag.StringVar(&path, "config_path_auth", "../../configs/auth_server_cfg.yaml", "Путь к конфигу")
-
-	grpcConfig := GrpcConfig{}
-	grpcFile, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-
-	err = yaml.Unmarshal(grpcFile, &grpcConfig)
-	if err != nil {
-		return nil, err
-	}
-
-	return &grpcConfig, nil
+type AuthCfg struct {
+	DbDsnCfg `yaml:",inline"`
+	Csrf     DbRedisCfg `yaml:"csrf"`
+	Session  DbRedisCfg `yaml:"session"`
+	Grpc     GrpcConfig `yaml:"grpc"`
 }
 
-func ReadCsrfRedisConfig() (*DbRedisCfg, error) {
-	flag.Parse()
-	var path string
-	flag.StringVar(&path, "config_path", "../../configs/db_csrf.yaml", "Путь к конфигу")
-
-	csrfConfig := DbRedisCfg{}
-	csrfFile, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
+type CommentCfg struct {
+	Environment     string        `yaml:"environment"`
+	User            string        `yaml:"user" env:"DB_USER"`
+	DbName          string        `yaml:"dbname" env:"DB_NAME"`
+	Password        string        `yaml:"password" env:"DB_PASSWORD" secret:"true"`
+	PasswordFile    string        `yaml:"password_file" env:"DB_PASSWORD_FILE"`
+	Host            string        `yaml:"host" env:"DB_HOST"`
+	Port            int           `yaml:"port" env:"DB_PORT"`
+	Sslmode         string        `yaml:"sslmode" env:"DB_SSLMODE"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	Timer           uint32        `yaml:"timer"`
+	Comments_db     string        `yaml:"comment_db"`
+	ServerAdress    string        `yaml:"server_adress"`
+	AdminAdress     string        `yaml:"admin_adress"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" reload:"true"`
+	GrpcPort        string        `yaml:"grpc_port"`
+	Http            HttpCfg       `yaml:"http"`
+	Metrics         MetricsCfg    `yaml:"metrics"`
+	Tracing         TracingCfg    `yaml:"tracing"`
+	AccessLog       AccessLogCfg  `yaml:"access_log"`
+	Log             LogCfg        `yaml:"log"`
+
+	files []string
+}
 
-	err = yaml.Unmarshal(csrfFile, &csrfConfig)
-	if err != nil {
-		return nil, err
-	}
+func (cfg *CommentCfg) Files() []string {
+	return cfg.files
+}
 
-	return &csrfConfig, nil
+func (cfg *CommentCfg) setFiles(files []string) {
+	cfg.files = files
 }
 
-func ReadSessionRedisConfig() (*DbRedisCfg, error) {
-	sessionConfig := DbRedisCfg{}
-	sessionFile, err := os.ReadFile("../../configs/db_session.yaml")
-	if err != nil {
-		return nil, err
-	}
+// Shared are the reloadable settings every service has.
+type Shared struct {
+	Http      HttpCfg
+	AccessLog AccessLogCfg
+	Log       LogCfg
+}
 
-	err = yaml.Unmarshal(sessionFile, &sessionConfig)
-	if err != nil {
-		return nil, err
-	}
+func (cfg *DbDsnCfg) Shared() Shared {
+	return Shared{Http: cfg.Http, AccessLog: cfg.AccessLog, Log: cfg.Log}
+}
 
-	return &sessionConfig, nil
+func (cfg *CommentCfg) Shared() Shared {
+	return Shared{Http: cfg.Http, AccessLog: cfg.AccessLog, Log: cfg.Log}
 }
 
-func ReadConfig() (*DbDsnCfg, error) {
-	dsnConfig := DbDsnCfg{}
-	dsnFile, err := os.ReadFile("../../configs/db_dsn.yaml")
-	if err != nil {
-		return nil, err
-	}
+// HttpCfg hardens the API server. Bodies of multipart routes are limited by
+// MultipartLimit, the other bodies by BodyLimit unless Routes has their route.
+// The body limits are reloaded. The timeouts and MaxHeaderBytes are read once
+// when the server starts: http.Server reads them from every connection without
+// a lock, so they cannot be changed while it serves.
+type HttpCfg struct {
+	ReadTimeout       time.Duration    `yaml:"read_timeout"`
+	ReadHeaderTimeout time.Duration    `yaml:"read_header_timeout"`
+	WriteTimeout      time.Duration    `yaml:"write_timeout"`
+	IdleTimeout       time.Duration    `yaml:"idle_timeout"`
+	MaxHeaderBytes    int              `yaml:"max_header_bytes"`
+	BodyLimit         int64            `yaml:"body_limit" reload:"true"`
+	MultipartLimit    int64            `yaml:"multipart_limit" reload:"true"`
+	Routes            map[string]int64 `yaml:"routes" reload:"true"`
+}
 
-	err = yaml.Unmarshal(dsnFile, &dsnConfig)
-	if err != nil {
-		return nil, err
-	}
+type MetricsCfg struct {
+	RouteLimit  int          `yaml:"route_limit"`
+	Latency     HistogramCfg `yaml:"latency"`
+	LegacyNames bool         `yaml:"legacy_names"`
+	Slo         []SloCfg     `yaml:"slo"`
+}
 
-	return &dsnConfig, nil
+type SloCfg struct {
+	Route         string  `yaml:"route"`
+	Availability  float64 `yaml:"availability"`
+	Latency       float64 `yaml:"latency"`
+	LatencyTarget float64 `yaml:"latency_target"`
 }
 
-func ReadFilmConfig() (*DbDsnCfg, error) {
-	flag.Parse()
-	var path string
-	flag.StringVar(&path, "films_config_path", "../../configs/db_film_dsn.yaml", "Путь к конфигу фильмов")
+type TracingCfg struct {
+	Exporter    string  `yaml:"exporter"`
+	Endpoint    string  `yaml:"endpoint"`
+	Insecure    bool    `yaml:"insecure"`
+	File        string  `yaml:"file"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
 
-	dsnConfig := DbDsnCfg{}
-	dsnFile, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
+type LogCfg struct {
+	Level    string            `yaml:"level" reload:"true"`
+	Modules  map[string]string `yaml:"modules" reload:"true"`
+	LevelTTL time.Duration     `yaml:"level_ttl" reload:"true"`
+	Format   string            `yaml:"format"`
+	Output   string            `yaml:"output"`
+	File     string            `yaml:"file"`
+	Rotation RotationCfg       `yaml:"rotation"`
+	Redact   RedactCfg         `yaml:"redact"`
+}
 
-	err = yaml.Unmarshal(dsnFile, &dsnConfig)
-	if err != nil {
-		return nil, err
-	}
+type RedactCfg struct {
+	Keys     []string `yaml:"keys"`
+	Patterns []string `yaml:"patterns"`
+}
 
-	return &dsnConfig, nil
+type RotationCfg struct {
+	MaxSize    int  `yaml:"max_size"`
+	MaxAge     int  `yaml:"max_age"`
+	MaxBackups int  `yaml:"max_backups"`
+	Compress   bool `yaml:"compress"`
 }
 
-func ReadCommentConfig() (*CommentCfg, error) {
-	flag.Parse()
-	var path string
-	flag.StringVar(&path, "comments_config_path", "../../configs/db_comment_dsn.yaml", "Путь к конфигу комментов")
+type AccessLogCfg struct {
+	Enabled        bool               `yaml:"enabled"`
+	Output         string             `yaml:"output"`
+	File           string             `yaml:"file"`
+	Format         string             `yaml:"format"`
+	Rotation       RotationCfg        `yaml:"rotation"`
+	Sampling       map[string]float64 `yaml:"sampling" reload:"true"`
+	TrustedProxies []string           `yaml:"trusted_proxies" reload:"true"`
+}
 
-	dsnConfig := CommentCfg{}
-	dsnFile, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
+type HistogramCfg struct {
+	Type               string    `yaml:"type"`
+	Start              float64   `yaml:"start"`
+	Factor             float64   `yaml:"factor"`
+	Count              int       `yaml:"count"`
+	Buckets            []float64 `yaml:"buckets"`
+	NativeBucketFactor float64   `yaml:"native_bucket_factor"`
+	NativeMaxBuckets   uint32    `yaml:"native_max_buckets"`
+}
 
-	err = yaml.Unmarshal(dsnFile, &dsnConfig)
-	if err != nil {
-		return nil, err
-	}
+type DbRedisCfg struct {
+	Addr         string `yaml:"addr"`
+	Password     string `yaml:"password" secret:"true"`
+	PasswordFile string `yaml:"password_file"`
+	DbNumber     int    `yaml:"db"`
+	Timer        int    `yaml:"timer" reload:"true"`
+}
 
-	return &dsnConfig, nil
+type GrpcConfig struct {
+	Port           string `yaml:"port"`
+	ConnectionType string `yaml:"connection_type"`
 }
@@ -0,0 +1,409 @@
+package configs
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	filmsConfigPath    = "db_film_dsn.yaml"
+	commentsConfigPath = "db_comment_dsn.yaml"
+	authConfigPath     = "db_dsn.yaml"
+)
+
+// defaultPath finds the config name in the configs directory of the working
+// directory or of its closest parent that has one, so a service finds its
+// config started from the module root as well as from cmd/<service>.
+func defaultPath(name string) string {
+	dir, err := os.Getwd()
+	for err == nil {
+		path := filepath.Join(dir, "configs", name)
+		if _, statErr := os.Stat(path); statErr == nil {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	return filepath.Join("configs", name)
+}
+
+type validator interface {
+	Validate() error
+	setFiles(files []string)
+}
+
+// profiles maps a profile to the environment it runs in.
+var profiles = map[string]string{"dev": EnvDevelopment, "staging": EnvStaging, "prod": EnvProduction}
+
+// overlay is the file a profile applies over path: configs/db_dsn.yaml becomes
+// configs/db_dsn.prod.yaml.
+func overlay(path string, profile string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + profile + ext
+}
+
+type source struct {
+	service string
+	path    string
+	// aliases maps flags kept for compatibility to the flag they stand for.
+	aliases map[string]string
+}
+
+// field is a scalar setting reachable by an environment variable and a flag.
+type field struct {
+	flag   string
+	env    string
+	secret bool
+	value  reflect.Value
+}
+
+// fields lists the settings of v, a struct. The flag is the dotted YAML path,
+// the variable the upper-cased path joined by underscores unless the field has
+// an env tag. Maps and lists of structs are only settable from YAML.
+func fields(v reflect.Value, flagPrefix string, envPrefix string) []field {
+	var result []field
+	for i := 0; i < v.NumField(); i++ {
+		structField := v.Type().Field(i)
+		name, opts, _ := strings.Cut(structField.Tag.Get("yaml"), ",")
+		if name == "-" || !structField.IsExported() {
+			continue
+		}
+
+		value := v.Field(i)
+		if opts == "inline" {
+			result = append(result, fields(value, flagPrefix, envPrefix)...)
+			continue
+		}
+
+		env := envPrefix + strings.ToUpper(name)
+		if tag := structField.Tag.Get("env"); tag != "" {
+			env = envPrefix + tag
+		}
+
+		switch {
+		case value.Kind() == reflect.Struct:
+			result = append(result, fields(value, flagPrefix+name+".", env+"_")...)
+		case settable(value.Type()):
+			result = append(result, field{
+				flag:   flagPrefix + name,
+				env:    env,
+				secret: structField.Tag.Get("secret") == "true",
+				value:  value,
+			})
+		}
+	}
+	return result
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func settable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+func (f field) set(raw string) error {
+	v := f.value
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case v.CanInt():
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case v.CanUint():
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case v.CanFloat():
+		n, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case v.Kind() == reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	}
+	return nil
+}
+
+// rawFlag only records the value, fields are set after the YAML file is read.
+type rawFlag struct {
+	value   *string
+	boolean bool
+}
+
+func (f rawFlag) String() string {
+	if f.value == nil {
+		return ""
+	}
+	return *f.value
+}
+
+func (f rawFlag) IsBoolFlag() bool {
+	return f.boolean
+}
+
+func (f rawFlag) Set(raw string) error {
+	*f.value = raw
+	return nil
+}
+
+// load fills cfg, holding the defaults, from in increasing precedence the YAML
+// file, the overlay of the profile, <SERVICE>_* environment variables and
+// command line flags, then validates it. Every bad value and field is
+// reported, not just the first. The overlay merges into maps, replaces lists
+// and the profile sets the environment. ${VAR} in YAML strings is expanded and
+// secrets may be read from the file in their *_file sibling.
+func load(src source, cfg validator, args []string) error {
+	prefix := strings.ToUpper(src.service) + "_"
+	all := fields(reflect.ValueOf(cfg).Elem(), "", prefix)
+
+	flags := flag.NewFlagSet(src.service, flag.ContinueOnError)
+
+	path, found := os.LookupEnv(prefix + "CONFIG")
+	if !found {
+		path = defaultPath(src.path)
+	}
+	flags.StringVar(&path, "config", path, "path to the YAML config")
+	profile := os.Getenv(prefix + "PROFILE")
+	flags.StringVar(&profile, "profile", profile, "dev, staging or prod, reads the <config>.<profile>.yaml overlay if it exists")
+
+	raw := make(map[string]*string, len(all))
+	for _, f := range all {
+		raw[f.flag] = new(string)
+		flags.Var(rawFlag{raw[f.flag], f.value.Kind() == reflect.Bool}, f.flag, "overrides "+f.env)
+	}
+	for alias, target := range src.aliases {
+		if target == "config" {
+			flags.StringVar(&path, alias, path, "deprecated, use -config")
+			continue
+		}
+		flags.Var(rawFlag{value: raw[target]}, alias, "deprecated, use -"+target)
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%s config err: %w", src.service, err)
+	}
+
+	files := []string{path}
+	if profile != "" {
+		if _, found := profiles[profile]; !found {
+			return fmt.Errorf("%s config err: unknown profile %q", src.service, profile)
+		}
+		files = append(files, overlay(path, profile))
+	}
+	for i, name := range files {
+		file, err := os.ReadFile(name)
+		if i > 0 && errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s config err: %w", src.service, err)
+		}
+		// Strict decoding refuses keys the base file already set in a map, so
+		// unknown keys of an overlay are caught on an empty config.
+		strict := any(cfg)
+		if i > 0 {
+			strict = reflect.New(reflect.TypeOf(cfg).Elem()).Interface()
+		}
+		if err := yaml.UnmarshalStrict(file, strict); err != nil {
+			return fmt.Errorf("%s config %s err: %w", src.service, name, err)
+		}
+		if i > 0 {
+			if err := yaml.Unmarshal(file, cfg); err != nil {
+				return fmt.Errorf("%s config %s err: %w", src.service, name, err)
+			}
+		}
+	}
+	cfg.setFiles(files)
+
+	for _, f := range all {
+		if profile != "" && f.flag == "environment" {
+			f.value.SetString(profiles[profile])
+		}
+	}
+
+	inline := inlineSecrets(all)
+
+	set := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) {
+		if target, found := src.aliases[f.Name]; found {
+			set[target] = true
+		}
+		set[f.Name] = true
+	})
+
+	// Overrides replace ${VAR} references before they are expanded, so the
+	// variable of an overridden setting does not have to be set.
+	var errs []error
+	overridden := map[string]bool{}
+	for _, f := range all {
+		if env, found := os.LookupEnv(f.env); found {
+			overridden[f.flag] = true
+			if err := f.set(env); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+			}
+		}
+	}
+	for _, f := range all {
+		if set[f.flag] {
+			overridden[f.flag] = true
+			if err := f.set(*raw[f.flag]); err != nil {
+				errs = append(errs, fmt.Errorf("-%s: %w", f.flag, err))
+			}
+			if f.secret {
+				inline = append(inline, "-"+f.flag)
+			}
+		}
+	}
+	skip := withFile(all)
+	for flag := range overridden {
+		skip[flag] = true
+	}
+	errs = append(errs, expand(all, skip)...)
+	errs = append(errs, readSecretFiles(all)...)
+	if environment(all) == EnvProduction {
+		for _, source := range inline {
+			errs = append(errs, fmt.Errorf("%s: inline secrets are refused in production, use ${VAR} or a *_file", source))
+		}
+	}
+	errs = append(errs, cfg.Validate())
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("%s config %s is invalid:\n%w", src.service, path, err)
+	}
+	return nil
+}
+
+func LoadFilms(args []string) (*FilmsCfg, error) {
+	cfg := FilmsCfg{DbDsnCfg: defaultDbDsn(":8082", "127.0.0.1:9082")}
+	cfg.Films_db, cfg.Genres_db, cfg.Crew_db, cfg.Profession_db, cfg.Calendar_db =
+		StoragePostgres, StoragePostgres, StoragePostgres, StoragePostgres, StoragePostgres
+
+	err := load(source{
+		service: "films",
+		path:    filmsConfigPath,
+		aliases: map[string]string{"films_config_path": "config", "films_log_path": "log.file"},
+	}, &cfg, args)
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func LoadComments(args []string) (*CommentCfg, error) {
+	db := defaultDbDsn(":8083", "127.0.0.1:9083")
+	cfg := CommentCfg{
+		Environment:     db.Environment,
+		Host:            db.Host,
+		Port:            db.Port,
+		Sslmode:         db.Sslmode,
+		MaxOpenConns:    db.MaxOpenConns,
+		Timer:           db.Timer,
+		Comments_db:     StoragePostgres,
+		ServerAdress:    db.ServerAdress,
+		AdminAdress:     db.AdminAdress,
+		ShutdownTimeout: db.ShutdownTimeout,
+		GrpcPort:        db.GrpcPort,
+		Http:            db.Http,
+		Tracing:         db.Tracing,
+		Log:             db.Log,
+	}
+
+	err := load(source{
+		service: "comments",
+		path:    commentsConfigPath,
+		aliases: map[string]string{"comments_config_path": "config", "comments_log_path": "log.file"},
+	}, &cfg, args)
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func LoadAuth(args []string) (*AuthCfg, error) {
+	cfg := AuthCfg{
+		DbDsnCfg: defaultDbDsn(":8081", "127.0.0.1:9081"),
+		Csrf:     DbRedisCfg{Addr: "localhost:6379", DbNumber: 1, Timer: 15},
+		Session:  DbRedisCfg{Addr: "localhost:6379", DbNumber: 0, Timer: 15},
+		Grpc:     GrpcConfig{Port: "50051", ConnectionType: "tcp"},
+	}
+
+	err := load(source{
+		service: "auth",
+		path:    authConfigPath,
+		aliases: map[string]string{"config_path_auth": "config", "auth_log_path": "log.file"},
+	}, &cfg, args)
+	if err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func defaultDbDsn(adress string, admin string) DbDsnCfg {
+	return DbDsnCfg{
+		Environment:     EnvDevelopment,
+		Host:            "127.0.0.1",
+		Port:            5432,
+		Sslmode:         "disable",
+		MaxOpenConns:    10,
+		Timer:           1,
+		ServerAdress:    adress,
+		AdminAdress:     admin,
+		ShutdownTimeout: 15 * time.Second,
+		GrpcPort:        ":50051",
+		Http: HttpCfg{
+			ReadTimeout:       10 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			MaxHeaderBytes:    1 << 20,
+			BodyLimit:         1 << 20,
+			MultipartLimit:    10 << 20,
+		},
+		Tracing: TracingCfg{SampleRatio: 1},
+		Log:     LogCfg{Level: "info", Format: "json", Output: "stdout"},
+	}
+}
@@ -0,0 +1,280 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return path
+}
+
+const commentsYaml = `
+user: "boss"
+dbname: "comments_service"
+password: "from yaml"
+port: 5433
+log:
+  level: "warn"
+`
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfig(t, commentsYaml)
+	t.Setenv("COMMENTS_DB_PASSWORD", "from env")
+	t.Setenv("COMMENTS_DB_PORT", "5434")
+	t.Setenv("COMMENTS_LOG_LEVEL_TTL", "5m")
+
+	cfg, err := LoadComments([]string{"-config", path, "-port", "5435", "-access_log.enabled", "-access_log.trusted_proxies", "10.0.0.0/8, 127.0.0.1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	testCases := map[string]struct {
+		got      any
+		expected any
+	}{
+		"Default":       {cfg.Host, "127.0.0.1"},
+		"Yaml":          {cfg.Log.Level, "warn"},
+		"Env over yaml": {cfg.Password, "from env"},
+		"Env duration":  {cfg.Log.LevelTTL, 5 * time.Minute},
+		"Flag over env": {cfg.Port, 5435},
+		"Bool flag":     {cfg.AccessLog.Enabled, true},
+		"List flag":     {strings.Join(cfg.AccessLog.TrustedProxies, " "), "10.0.0.0/8 127.0.0.1"},
+	}
+
+	for name, tc := range testCases {
+		if tc.got != tc.expected {
+			t.Errorf("%s: got %v, expected %v", name, tc.got, tc.expected)
+		}
+	}
+}
+
+func TestLoadOverrideReference(t *testing.T) {
+	path := writeConfig(t, "user: boss\ndbname: comments\npassword: \"${TEST_PG_UNSET}\"\nhost: \"${TEST_PG_UNSET}\"\n")
+	t.Setenv("COMMENTS_DB_PASSWORD", "from env")
+
+	cfg, err := LoadComments([]string{"-config", path, "-host", "db.local"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cfg.Password != "from env" || cfg.Host != "db.local" {
+		t.Errorf("overrides not applied: %q %q", cfg.Password, cfg.Host)
+	}
+
+	if _, err := LoadComments([]string{"-config", path}); err == nil || !strings.Contains(err.Error(), "host: ${TEST_PG_UNSET} is not set") {
+		t.Errorf("unset reference without override: %v", err)
+	}
+}
+
+func TestLoadSampleRatio(t *testing.T) {
+	cfg, err := LoadComments([]string{"-config", writeConfig(t, commentsYaml)})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cfg.Tracing.SampleRatio != 1 {
+		t.Errorf("unset ratio: got %v, expected 1", cfg.Tracing.SampleRatio)
+	}
+
+	cfg, err = LoadComments([]string{"-config", writeConfig(t, commentsYaml+"tracing:\n  sample_ratio: 0\n")})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cfg.Tracing.SampleRatio != 0 {
+		t.Errorf("sampling off: got %v, expected 0", cfg.Tracing.SampleRatio)
+	}
+}
+
+func TestLoadConfigPath(t *testing.T) {
+	path := writeConfig(t, commentsYaml)
+
+	t.Setenv("COMMENTS_CONFIG", path)
+	if _, err := LoadComments(nil); err != nil {
+		t.Errorf("config from env: unexpected error: %s", err)
+	}
+
+	t.Setenv("COMMENTS_CONFIG", "missing.yaml")
+	cfg, err := LoadComments([]string{"-comments_config_path", path, "-comments_log_path", "comments.log"})
+	if err != nil {
+		t.Fatalf("deprecated flags: unexpected error: %s", err)
+	}
+	if cfg.Log.File != "comments.log" {
+		t.Errorf("deprecated log flag ignored: %q", cfg.Log.File)
+	}
+}
+
+func TestLoadDefaultPath(t *testing.T) {
+	root := t.TempDir()
+	service := filepath.Join(root, "cmd", "comments")
+	if err := os.MkdirAll(service, 0o755); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := os.Mkdir(filepath.Join(root, "configs"), 0o755); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "configs", commentsConfigPath), []byte(commentsYaml), 0o600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	for _, dir := range []string{root, service} {
+		if err := os.Chdir(dir); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if _, err := LoadComments(nil); err != nil {
+			t.Errorf("started from %s: unexpected error: %s", dir, err)
+		}
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	path := writeConfig(t, commentsYaml+"access_log:\n  sampling:\n    2xx: 0.1\n    4xx: 1\n")
+	prod := overlay(path, "prod")
+	if err := os.WriteFile(prod, []byte("password: \"${TEST_PG_PASSWORD}\"\naccess_log:\n  sampling:\n    2xx: 0.01\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	t.Setenv("TEST_PG_PASSWORD", "from env")
+
+	cfg, err := LoadComments([]string{"-config", path, "-profile", "prod"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	testCases := map[string]struct {
+		got      any
+		expected any
+	}{
+		"Environment":     {cfg.Environment, EnvProduction},
+		"Overlay":         {cfg.Password, "from env"},
+		"Base":            {cfg.Log.Level, "warn"},
+		"Merged map":      {cfg.AccessLog.Sampling["2xx"], 0.01},
+		"Kept map key":    {cfg.AccessLog.Sampling["4xx"], 1.0},
+		"Watched overlay": {strings.Join(cfg.Files(), " "), path + " " + prod},
+	}
+	for name, tc := range testCases {
+		if tc.got != tc.expected {
+			t.Errorf("%s: got %v, expected %v", name, tc.got, tc.expected)
+		}
+	}
+
+	t.Setenv("COMMENTS_PROFILE", "staging")
+	if cfg, err := LoadComments([]string{"-config", path}); err != nil || cfg.Environment != EnvStaging {
+		t.Errorf("profile without overlay: %v", err)
+	}
+	if _, err := LoadComments([]string{"-config", path, "-profile", "production"}); err == nil {
+		t.Errorf("unknown profile was accepted")
+	}
+
+	if err := os.WriteFile(prod, []byte("pasword: \"${TEST_PG_PASSWORD}\"\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := LoadComments([]string{"-config", path, "-profile", "prod"}); err == nil || !strings.Contains(err.Error(), "pasword") {
+		t.Errorf("unknown overlay key: %v", err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	testCases := map[string]struct {
+		yaml   string
+		args   []string
+		errors []string
+	}{
+		"Unknown key": {
+			yaml:   "user: boss\nhots: localhost\n",
+			errors: []string{"hots"},
+		},
+		"Every invalid field": {
+			yaml: "user: \"\"\nsslmode: sometimes\nlog:\n  level: loud\n  output: file\n",
+			args: []string{"-port", "70000", "-server_adress", "8083", "-shutdown_timeout", "0s"},
+			errors: []string{
+				"user: is required", "dbname: is required", "port: 70000", "sslmode:",
+				"server_adress:", "shutdown_timeout:", "log.level:", "log.file: is required",
+			},
+		},
+		"Bad http limits": {
+			yaml:   commentsYaml + "http:\n  read_timeout: 1s\n  read_header_timeout: 5s\n  write_timeout: 0s\n  body_limit: -1\n  routes:\n    api/v1/comment/add: 0\n",
+			errors: []string{"http.read_header_timeout: 5s is longer", "http.write_timeout: must be positive", "http.body_limit:", "http.routes: \"api/v1/comment/add\"", "http.routes.api/v1/comment/add:"},
+		},
+		"Bad latency buckets": {
+			yaml:   commentsYaml + "metrics:\n  latency:\n    type: explicit\n    buckets: [0.1, 0.5, 0.5]\n",
+			errors: []string{"metrics.latency.buckets: [0.1 0.5 0.5] are not strictly increasing"},
+		},
+		"Bad exponential latency": {
+			yaml:   commentsYaml + "metrics:\n  latency:\n    type: exponential\n    factor: 1\n",
+			errors: []string{"metrics.latency.start: must be positive", "metrics.latency.factor: 1 is not above 1", "metrics.latency.count:"},
+		},
+		"Admin on the API address": {
+			yaml:   commentsYaml,
+			args:   []string{"-admin_adress", ":8083"},
+			errors: []string{"admin_adress: is server_adress"},
+		},
+		"Bad flag value": {
+			yaml:   commentsYaml,
+			args:   []string{"-max_open_conns", "many", "-dbname", ""},
+			errors: []string{"-max_open_conns", "dbname: is required"},
+		},
+		"Unknown flag": {
+			yaml:   commentsYaml,
+			args:   []string{"-colour", "blue"},
+			errors: []string{"colour"},
+		},
+	}
+
+	for name, tc := range testCases {
+		path := writeConfig(t, tc.yaml)
+		_, err := LoadComments(append([]string{"-config", path}, tc.args...))
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+			continue
+		}
+		for _, expected := range tc.errors {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("%s: %q is not reported in %s", name, expected, err)
+			}
+		}
+	}
+}
+
+func TestLoadRepoConfigs(t *testing.T) {
+	t.Setenv("PG_PASSWORD", "secret")
+	t.Setenv("FILMS_ENVIRONMENT", EnvProduction)
+
+	if _, err := LoadFilms([]string{"-config", "db_film_dsn.yaml"}); err != nil {
+		t.Errorf("films: %s", err)
+	}
+	if _, err := LoadComments([]string{"-config", "db_comment_dsn.yaml"}); err != nil {
+		t.Errorf("comments: %s", err)
+	}
+
+	cfg, err := LoadAuth([]string{"-config", "db_dsn.yaml"})
+	if err != nil {
+		t.Fatalf("auth: %s", err)
+	}
+	if cfg.Csrf.Addr == "" || cfg.Session.Addr == "" || cfg.Csrf.DbNumber == cfg.Session.DbNumber {
+		t.Errorf("auth redis configs are not read: %+v %+v", cfg.Csrf, cfg.Session)
+	}
+
+	t.Setenv("REDIS_PASSWORD", "secret")
+	for profile := range profiles {
+		if _, err := LoadFilms([]string{"-config", "db_film_dsn.yaml", "-profile", profile}); err != nil {
+			t.Errorf("films %s: %s", profile, err)
+		}
+		if _, err := LoadComments([]string{"-config", "db_comment_dsn.yaml", "-profile", profile}); err != nil {
+			t.Errorf("comments %s: %s", profile, err)
+		}
+		if _, err := LoadAuth([]string{"-config", "db_dsn.yaml", "-profile", profile}); err != nil {
+			t.Errorf("auth %s: %s", profile, err)
+		}
+	}
+}
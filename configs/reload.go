@@ -0,0 +1,203 @@
+package configs
+
+import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const watchInterval = 5 * time.Second
+
+// Change is a setting that differs in a reloaded config. Settings tagged
+// reload:"true" are applied to the running service, the others need a restart.
+type Change struct {
+	Field   string
+	Restart bool
+}
+
+type leaf struct {
+	path   string
+	reload bool
+	value  reflect.Value
+}
+
+// leaves lists every setting of v, a struct, by its dotted YAML path. Unlike
+// fields it keeps maps and lists, they are compared as a whole.
+func leaves(v reflect.Value, prefix string) []leaf {
+	var result []leaf
+	for i := 0; i < v.NumField(); i++ {
+		structField := v.Type().Field(i)
+		name, opts, _ := strings.Cut(structField.Tag.Get("yaml"), ",")
+		if name == "-" || !structField.IsExported() {
+			continue
+		}
+
+		value := v.Field(i)
+		switch {
+		case opts == "inline":
+			result = append(result, leaves(value, prefix)...)
+		case value.Kind() == reflect.Struct:
+			result = append(result, leaves(value, prefix+name+".")...)
+		default:
+			result = append(result, leaf{
+				path:   prefix + name,
+				reload: structField.Tag.Get("reload") == "true",
+				value:  value,
+			})
+		}
+	}
+	return result
+}
+
+// Diff lists the settings changed between running and reloaded, pointers to
+// configs of the same type.
+func Diff(running any, reloaded any) []Change {
+	before := leaves(reflect.ValueOf(running).Elem(), "")
+	after := leaves(reflect.ValueOf(reloaded).Elem(), "")
+
+	var changes []Change
+	for i := range before {
+		if !reflect.DeepEqual(before[i].value.Interface(), after[i].value.Interface()) {
+			changes = append(changes, Change{Field: before[i].path, Restart: !before[i].reload})
+		}
+	}
+	return changes
+}
+
+// Reloader reads the config of a running service again and hands the new one
+// to apply, which swaps the reloadable settings into the components.
+type Reloader[T any] struct {
+	load  func() (*T, error)
+	apply func(*T) error
+	lg    *slog.Logger
+
+	mutex sync.Mutex
+	// running holds the started config with the applied changes, so settings
+	// waiting for a restart are reported again on every reload.
+	running  T
+	interval time.Duration
+}
+
+func NewReloader[T any](running *T, load func() (*T, error), apply func(*T) error, lg *slog.Logger) *Reloader[T] {
+	return &Reloader[T]{
+		load:     load,
+		apply:    apply,
+		lg:       lg.With("module", "config"),
+		running:  *running,
+		interval: watchInterval,
+	}
+}
+
+// Reload validates the config and applies it if a reloadable setting changed.
+// Nothing is applied when the config is invalid or apply fails, so apply has
+// to check every setting before it changes any.
+func (r *Reloader[T]) Reload() ([]Change, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	reloaded, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	changes := Diff(&r.running, reloaded)
+	applied := false
+	for _, change := range changes {
+		applied = applied || !change.Restart
+	}
+	if !applied {
+		return changes, nil
+	}
+
+	if err := r.apply(reloaded); err != nil {
+		return nil, err
+	}
+
+	running := leaves(reflect.ValueOf(&r.running).Elem(), "")
+	for i, l := range leaves(reflect.ValueOf(reloaded).Elem(), "") {
+		if l.reload {
+			running[i].value.Set(l.value)
+		}
+	}
+	return changes, nil
+}
+
+func (r *Reloader[T]) reload(reason string) {
+	changes, err := r.Reload()
+	if err != nil {
+		r.lg.Error("config reload error", "reason", reason, "err", err.Error())
+		return
+	}
+
+	applied, restart := []string{}, []string{}
+	for _, change := range changes {
+		if change.Restart {
+			restart = append(restart, change.Field)
+		} else {
+			applied = append(applied, change.Field)
+		}
+	}
+	r.lg.Info("config reloaded", "reason", reason, "applied", applied, "restart_required", restart)
+	if len(restart) != 0 {
+		r.lg.Warn("config changes need a restart", "fields", restart)
+	}
+}
+
+// Watch reloads on SIGHUP and whenever one of the files changes until the
+// returned func is called.
+func (r *Reloader[T]) Watch(files ...string) func() {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGHUP)
+
+	stamps := make([]time.Time, len(files))
+	for i, file := range files {
+		stamps[i] = modTime(file)
+	}
+
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-signals:
+				r.reload("signal")
+			case <-ticker.C:
+				changed := false
+				for i, file := range files {
+					if stamp := modTime(file); !stamp.Equal(stamps[i]) {
+						stamps[i] = stamp
+						changed = true
+					}
+				}
+				if changed {
+					r.reload("file")
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
+
+func modTime(file string) time.Time {
+	info, err := os.Stat(file)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
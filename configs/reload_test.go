@@ -0,0 +1,138 @@
+package configs
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	running := &AuthCfg{Csrf: DbRedisCfg{Timer: 15}, Session: DbRedisCfg{Addr: "localhost:6379"}}
+	reloaded := &AuthCfg{Csrf: DbRedisCfg{Timer: 5}, Session: DbRedisCfg{Addr: "redis:6379"}}
+	running.Log.Level, reloaded.Log.Level = "info", "debug"
+	reloaded.AccessLog.Sampling = map[string]float64{"2xx": 0.1}
+	reloaded.User = "boss"
+	reloaded.ShutdownTimeout = time.Minute
+	reloaded.Http.ReadTimeout, reloaded.Http.BodyLimit = time.Second, 1024
+
+	expected := []Change{
+		{Field: "user", Restart: true},
+		{Field: "shutdown_timeout"},
+		{Field: "http.read_timeout", Restart: true},
+		{Field: "http.body_limit"},
+		{Field: "access_log.sampling"},
+		{Field: "log.level"},
+		{Field: "csrf.timer"},
+		{Field: "session.addr", Restart: true},
+	}
+
+	changes := Diff(running, reloaded)
+	if len(changes) != len(expected) {
+		t.Fatalf("got %v, expected %v", changes, expected)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("change %d: got %v, expected %v", i, changes[i], expected[i])
+		}
+	}
+}
+
+func TestReloader(t *testing.T) {
+	path := writeConfig(t, commentsYaml)
+	running, err := LoadComments([]string{"-config", path})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var applied *CommentCfg
+	var applyErr error
+	reloader := NewReloader(running, func() (*CommentCfg, error) {
+		return LoadComments([]string{"-config", path})
+	}, func(cfg *CommentCfg) error {
+		applied = cfg
+		return applyErr
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	testCases := []struct {
+		name     string
+		yaml     string
+		applyErr error
+		applied  bool
+		changes  []string
+		err      bool
+	}{
+		{"Nothing changed", commentsYaml, nil, false, nil, false},
+		{"Restart only", commentsYaml + "max_open_conns: 5\n", nil, false, []string{"max_open_conns"}, false},
+		{"Reloadable", commentsYaml + "  level_ttl: 1m\nmax_open_conns: 5\n", nil, true, []string{"max_open_conns", "log.level_ttl"}, false},
+		{"Applied is kept", commentsYaml + "  level_ttl: 1m\nmax_open_conns: 5\n", nil, false, []string{"max_open_conns"}, false},
+		{"Invalid", commentsYaml + "  level_ttl: 1m\n  format: xml\n", nil, false, nil, true},
+		{"Apply error", commentsYaml + "  level_ttl: 2m\n", errors.New("refused"), true, nil, true},
+		{"Refused is retried", commentsYaml + "  level_ttl: 2m\n", nil, true, []string{"log.level_ttl"}, false},
+	}
+
+	for _, tc := range testCases {
+		if err := os.WriteFile(path, []byte(tc.yaml), 0o600); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		applied, applyErr = nil, tc.applyErr
+
+		changes, err := reloader.Reload()
+		if (err != nil) != tc.err {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+		}
+		if (applied != nil) != tc.applied {
+			t.Errorf("%s: applied %v, expected %v", tc.name, applied != nil, tc.applied)
+		}
+
+		var fields []string
+		for _, change := range changes {
+			fields = append(fields, change.Field)
+		}
+		if strings.Join(fields, " ") != strings.Join(tc.changes, " ") {
+			t.Errorf("%s: changes %v, expected %v", tc.name, fields, tc.changes)
+		}
+	}
+}
+
+func TestReloaderWatch(t *testing.T) {
+	path := writeConfig(t, commentsYaml)
+	running, err := LoadComments([]string{"-config", path})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if files := running.Files(); len(files) != 1 || files[0] != path {
+		t.Errorf("files %v, expected %s", files, path)
+	}
+
+	applied := make(chan *CommentCfg, 1)
+	reloader := NewReloader(running, func() (*CommentCfg, error) {
+		return LoadComments([]string{"-config", path})
+	}, func(cfg *CommentCfg) error {
+		applied <- cfg
+		return nil
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	reloader.interval = 10 * time.Millisecond
+	stop := reloader.Watch(running.Files()...)
+	defer stop()
+
+	if err := os.WriteFile(path, []byte(commentsYaml+"  level_ttl: 1m\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	select {
+	case cfg := <-applied:
+		if cfg.Log.LevelTTL != time.Minute {
+			t.Errorf("level ttl %s, expected 1m", cfg.Log.LevelTTL)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("file change was not picked up")
+	}
+}
@@ -0,0 +1,138 @@
+package configs
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+const masked = "******"
+
+// Only the braced form is expanded, passwords may well contain a bare $.
+var reference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+func environment(all []field) string {
+	for _, f := range all {
+		if f.flag == "environment" {
+			return f.value.String()
+		}
+	}
+	return ""
+}
+
+// inlineSecrets lists the secrets written into the YAML file itself rather
+// than referenced from the environment.
+func inlineSecrets(all []field) []string {
+	var inline []string
+	for _, f := range all {
+		if f.secret && strings.TrimSpace(reference.ReplaceAllString(f.value.String(), "")) != "" {
+			inline = append(inline, f.flag)
+		}
+	}
+	return inline
+}
+
+// expand replaces the ${VAR} references in the string settings but the
+// skipped ones.
+func expand(all []field, skip map[string]bool) []error {
+	var errs []error
+	for _, f := range all {
+		if f.value.Kind() != reflect.String || skip[f.flag] {
+			continue
+		}
+		f.value.SetString(reference.ReplaceAllStringFunc(f.value.String(), func(ref string) string {
+			name := reference.FindStringSubmatch(ref)[1]
+			value, found := os.LookupEnv(name)
+			if !found {
+				errs = append(errs, fmt.Errorf("%s: ${%s} is not set", f.flag, name))
+			}
+			return value
+		}))
+	}
+	return errs
+}
+
+// secretFiles maps every secret to its *_file sibling.
+func secretFiles(all []field) map[string]field {
+	byFlag := make(map[string]field, len(all))
+	for _, f := range all {
+		byFlag[f.flag] = f
+	}
+
+	files := map[string]field{}
+	for _, f := range all {
+		if file, found := byFlag[f.flag+"_file"]; f.secret && found {
+			files[f.flag] = file
+		}
+	}
+	return files
+}
+
+// withFile lists the secrets whose *_file sibling is set. The file takes
+// precedence over ${VAR} references in the secret, they are not expanded.
+func withFile(all []field) map[string]bool {
+	result := map[string]bool{}
+	for secret, file := range secretFiles(all) {
+		if file.value.String() != "" {
+			result[secret] = true
+		}
+	}
+	return result
+}
+
+// readSecretFiles fills every secret from the file named by its *_file
+// sibling, as Docker and Kubernetes mount them. A secret holding only ${VAR}
+// references is replaced, any other value conflicts with the file.
+func readSecretFiles(all []field) []error {
+	files := secretFiles(all)
+
+	var errs []error
+	for _, f := range all {
+		file, found := files[f.flag]
+		if !found || file.value.String() == "" {
+			continue
+		}
+		if strings.TrimSpace(reference.ReplaceAllString(f.value.String(), "")) != "" {
+			errs = append(errs, fmt.Errorf("%s: set together with %s", f.flag, file.flag))
+			continue
+		}
+
+		content, err := os.ReadFile(file.value.String())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file.flag, err))
+			continue
+		}
+		f.value.SetString(strings.TrimRight(string(content), "\r\n"))
+	}
+	return errs
+}
+
+// Masked renders cfg, a pointer to a loaded config, as YAML with the secrets
+// that are set masked.
+func Masked(cfg any) ([]byte, error) {
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := reflect.New(reflect.TypeOf(cfg).Elem())
+	if err := yaml.Unmarshal(out, copied.Interface()); err != nil {
+		return nil, err
+	}
+	for _, f := range fields(copied.Elem(), "", "") {
+		if f.secret && f.value.String() != "" {
+			f.value.SetString(masked)
+		}
+	}
+	return yaml.Marshal(copied.Interface())
+}
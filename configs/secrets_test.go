@@ -0,0 +1,125 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSecrets(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "pg")
+	if err := os.WriteFile(secretFile, []byte("from file\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	t.Setenv("TEST_PG_PASSWORD", "from env")
+	t.Setenv("TEST_PG_USER", "boss")
+
+	testCases := map[string]struct {
+		yaml     string
+		args     []string
+		password string
+		errors   []string
+	}{
+		"Expansion": {
+			yaml:     "user: \"${TEST_PG_USER}\"\ndbname: comments\npassword: \"${TEST_PG_PASSWORD}\"\n",
+			password: "from env",
+		},
+		"Bare dollar is kept": {
+			yaml:     "user: boss\ndbname: comments\npassword: \"p0$TGR3s$\"\n",
+			password: "p0$TGR3s$",
+		},
+		"Unset variable": {
+			yaml:   "user: boss\ndbname: comments\npassword: \"${TEST_PG_MISSING}\"\n",
+			errors: []string{"password: ${TEST_PG_MISSING} is not set"},
+		},
+		"Password file": {
+			yaml:     "user: boss\ndbname: comments\npassword_file: \"" + secretFile + "\"\n",
+			password: "from file",
+		},
+		"Password file over unset reference": {
+			yaml:     "user: boss\ndbname: comments\npassword: \"${TEST_PG_MISSING}\"\n",
+			args:     []string{"-password_file", secretFile},
+			password: "from file",
+		},
+		"Missing password file": {
+			yaml:   "user: boss\ndbname: comments\npassword_file: \"" + secretFile + ".missing\"\n",
+			errors: []string{"password_file:"},
+		},
+		"Password and file": {
+			yaml:   "user: boss\ndbname: comments\npassword: x\npassword_file: \"" + secretFile + "\"\n",
+			errors: []string{"password: set together with password_file"},
+		},
+		"Production with references": {
+			yaml:     "environment: production\nuser: boss\ndbname: comments\npassword: \"${TEST_PG_PASSWORD}\"\n",
+			password: "from env",
+		},
+		"Production with file": {
+			yaml:     "environment: production\nuser: boss\ndbname: comments\npassword_file: \"" + secretFile + "\"\n",
+			password: "from file",
+		},
+		"Production with inline secret": {
+			yaml:   "environment: production\nuser: boss\ndbname: comments\npassword: \"p0$TGR3s$\"\n",
+			errors: []string{"password: inline secrets are refused in production"},
+		},
+		"Production with secret flag": {
+			yaml:   "environment: production\nuser: boss\ndbname: comments\n",
+			args:   []string{"-password", "hunter2"},
+			errors: []string{"-password: inline secrets are refused in production"},
+		},
+		"Bad environment": {
+			yaml:   "environment: prod\nuser: boss\ndbname: comments\n",
+			errors: []string{"environment:"},
+		},
+	}
+
+	for name, tc := range testCases {
+		path := writeConfig(t, tc.yaml)
+		cfg, err := LoadComments(append([]string{"-config", path}, tc.args...))
+
+		if len(tc.errors) == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %s", name, err)
+			} else if cfg.Password != tc.password {
+				t.Errorf("%s: password %q, expected %q", name, cfg.Password, tc.password)
+			}
+			continue
+		}
+
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+			continue
+		}
+		for _, expected := range tc.errors {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("%s: %q is not reported in %s", name, expected, err)
+			}
+		}
+	}
+}
+
+func TestMasked(t *testing.T) {
+	cfg := &AuthCfg{
+		DbDsnCfg: DbDsnCfg{User: "boss", Password: "hunter2"},
+		Csrf:     DbRedisCfg{Addr: "localhost:6379"},
+		Session:  DbRedisCfg{Password: "swordfish"},
+	}
+
+	out, err := Masked(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, secret := range []string{"hunter2", "swordfish"} {
+		if strings.Contains(string(out), secret) {
+			t.Errorf("%s is not masked:\n%s", secret, out)
+		}
+	}
+	for _, expected := range []string{"user: boss", "password: '******'", "addr: localhost:6379", "password: \"\""} {
+		if !strings.Contains(string(out), expected) {
+			t.Errorf("%q is missing:\n%s", expected, out)
+		}
+	}
+	if cfg.Password != "hunter2" {
+		t.Errorf("config was changed: %q", cfg.Password)
+	}
+}
@@ -0,0 +1,283 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const StoragePostgres = "postgres"
+
+var (
+	sslmodes        = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	histogramTypes  = []string{"", "exponential", "explicit", "native"}
+	tracingExporter = []string{"", "otlp", "stdout", "file"}
+	logOutputs      = []string{"", "stdout", "stderr", "file"}
+	logFormats      = []string{"", "json", "text"}
+	environments    = []string{EnvDevelopment, EnvStaging, EnvProduction}
+)
+
+// problems collects every invalid field so they are all reported at once.
+type problems []error
+
+func (p *problems) add(field string, format string, args ...any) {
+	*p = append(*p, fmt.Errorf("%s: "+format, append([]any{field}, args...)...))
+}
+
+func (p *problems) required(field string, value string) {
+	if value == "" {
+		p.add(field, "is required")
+	}
+}
+
+func (p *problems) oneOf(field string, value string, allowed []string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	p.add(field, "%q is not one of %s", value, strings.Join(allowed, ", "))
+}
+
+func (p *problems) address(field string, value string) {
+	if _, _, err := net.SplitHostPort(value); err != nil {
+		p.add(field, "%q is not a host:port address", value)
+	}
+}
+
+func (p *problems) port(field string, value int) {
+	if value <= 0 || value > 65535 {
+		p.add(field, "%d is not a port", value)
+	}
+}
+
+func (p *problems) level(field string, value string) {
+	var level slog.Level
+	if value != "" && level.UnmarshalText([]byte(value)) != nil {
+		p.add(field, "%q is not a log level", value)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (p problems) err() error {
+	return errors.Join(p...)
+}
+
+func (p *problems) db(user, dbName, host string, port int, sslmode string, maxOpenConns int, timer uint32) {
+	p.required("user", user)
+	p.required("dbname", dbName)
+	p.required("host", host)
+	p.port("port", port)
+	p.oneOf("sslmode", sslmode, sslmodes)
+	if maxOpenConns <= 0 {
+		p.add("max_open_conns", "must be positive")
+	}
+	if timer == 0 {
+		p.add("timer", "must be positive")
+	}
+}
+
+func (p *problems) redis(name string, cfg DbRedisCfg) {
+	p.address(name+".addr", cfg.Addr)
+	if cfg.DbNumber < 0 {
+		p.add(name+".db", "must not be negative")
+	}
+	if cfg.Timer <= 0 {
+		p.add(name+".timer", "must be positive")
+	}
+}
+
+func (p *problems) http(cfg HttpCfg) {
+	timeouts := map[string]time.Duration{
+		"http.read_timeout":        cfg.ReadTimeout,
+		"http.read_header_timeout": cfg.ReadHeaderTimeout,
+		"http.write_timeout":       cfg.WriteTimeout,
+		"http.idle_timeout":        cfg.IdleTimeout,
+	}
+	for _, field := range sortedKeys(timeouts) {
+		if timeouts[field] <= 0 {
+			p.add(field, "must be positive")
+		}
+	}
+	if cfg.ReadHeaderTimeout > cfg.ReadTimeout {
+		p.add("http.read_header_timeout", "%s is longer than http.read_timeout", cfg.ReadHeaderTimeout)
+	}
+	if cfg.MaxHeaderBytes <= 0 {
+		p.add("http.max_header_bytes", "must be positive")
+	}
+	if cfg.BodyLimit <= 0 {
+		p.add("http.body_limit", "must be positive")
+	}
+	if cfg.MultipartLimit <= 0 {
+		p.add("http.multipart_limit", "must be positive")
+	}
+	for _, route := range sortedKeys(cfg.Routes) {
+		if !strings.HasPrefix(route, "/") {
+			p.add("http.routes", "%q is not a route", route)
+		}
+		if cfg.Routes[route] <= 0 {
+			p.add("http.routes."+route, "must be positive")
+		}
+	}
+}
+
+// Validate reports every malformed histogram setting. Metrics checks the
+// histograms it is given with it, so configs built in code fail the same way.
+func (cfg HistogramCfg) Validate() error {
+	var p problems
+	p.histogram("histogram", cfg)
+	return p.err()
+}
+
+func (p *problems) histogram(field string, cfg HistogramCfg) {
+	p.oneOf(field+".type", cfg.Type, histogramTypes)
+	switch cfg.Type {
+	case "exponential":
+		if cfg.Start <= 0 {
+			p.add(field+".start", "must be positive")
+		}
+		if cfg.Factor <= 1 {
+			p.add(field+".factor", "%v is not above 1", cfg.Factor)
+		}
+		if cfg.Count <= 0 {
+			p.add(field+".count", "must be positive")
+		}
+	case "explicit":
+		if len(cfg.Buckets) == 0 {
+			p.add(field+".buckets", "is required")
+		}
+		for i := 1; i < len(cfg.Buckets); i++ {
+			if cfg.Buckets[i] <= cfg.Buckets[i-1] {
+				p.add(field+".buckets", "%v are not strictly increasing", cfg.Buckets)
+				break
+			}
+		}
+	case "native":
+		if cfg.NativeBucketFactor != 0 && cfg.NativeBucketFactor <= 1 {
+			p.add(field+".native_bucket_factor", "%v is not above 1", cfg.NativeBucketFactor)
+		}
+	}
+}
+
+func (p *problems) service(metrics MetricsCfg, tracing TracingCfg, log LogCfg, accessLog AccessLogCfg) {
+	p.histogram("metrics.latency", metrics.Latency)
+	for i, slo := range metrics.Slo {
+		field := fmt.Sprintf("metrics.slo[%d]", i)
+		if slo.Route == "" {
+			p.add(field+".route", "is required")
+		}
+		if slo.Availability < 0 || slo.Availability >= 1 {
+			p.add(field+".availability", "%v is not in [0, 1)", slo.Availability)
+		}
+		if slo.LatencyTarget < 0 || slo.LatencyTarget >= 1 {
+			p.add(field+".latency_target", "%v is not in [0, 1)", slo.LatencyTarget)
+		}
+	}
+
+	p.oneOf("tracing.exporter", tracing.Exporter, tracingExporter)
+	if tracing.Exporter == "otlp" {
+		p.required("tracing.endpoint", tracing.Endpoint)
+	}
+	if tracing.Exporter == "file" {
+		p.required("tracing.file", tracing.File)
+	}
+	if tracing.SampleRatio < 0 || tracing.SampleRatio > 1 {
+		p.add("tracing.sample_ratio", "%v is not in [0, 1]", tracing.SampleRatio)
+	}
+
+	p.level("log.level", log.Level)
+	for _, module := range sortedKeys(log.Modules) {
+		p.level("log.modules."+module, log.Modules[module])
+	}
+	p.oneOf("log.format", log.Format, logFormats)
+	p.oneOf("log.output", log.Output, logOutputs)
+	if log.Output == "file" {
+		p.required("log.file", log.File)
+	}
+
+	if accessLog.Enabled {
+		p.oneOf("access_log.format", accessLog.Format, logFormats)
+		p.oneOf("access_log.output", accessLog.Output, logOutputs)
+		if accessLog.Output == "file" {
+			p.required("access_log.file", accessLog.File)
+		}
+		for _, key := range sortedKeys(accessLog.Sampling) {
+			if rate := accessLog.Sampling[key]; rate < 0 || rate > 1 {
+				p.add("access_log.sampling."+key, "%v is not in [0, 1]", rate)
+			}
+		}
+	}
+}
+
+func (cfg *DbDsnCfg) problems() problems {
+	var p problems
+	p.oneOf("environment", cfg.Environment, environments)
+	p.db(cfg.User, cfg.DbName, cfg.Host, cfg.Port, cfg.Sslmode, cfg.MaxOpenConns, cfg.Timer)
+	p.address("server_adress", cfg.ServerAdress)
+	p.address("admin_adress", cfg.AdminAdress)
+	if cfg.AdminAdress == cfg.ServerAdress {
+		p.add("admin_adress", "is server_adress, the admin endpoints must not be served with the API")
+	}
+	if cfg.ShutdownTimeout <= 0 {
+		p.add("shutdown_timeout", "must be positive")
+	}
+	p.http(cfg.Http)
+	p.service(cfg.Metrics, cfg.Tracing, cfg.Log, cfg.AccessLog)
+	return p
+}
+
+func (cfg *FilmsCfg) Validate() error {
+	p := cfg.problems()
+	p.address("grpc_port", cfg.GrpcPort)
+	p.oneOf("films_db", cfg.Films_db, []string{StoragePostgres})
+	p.oneOf("genres_db", cfg.Genres_db, []string{StoragePostgres})
+	p.oneOf("crew_db", cfg.Crew_db, []string{StoragePostgres})
+	p.oneOf("profession_db", cfg.Profession_db, []string{StoragePostgres})
+	p.oneOf("calendar_db", cfg.Calendar_db, []string{StoragePostgres})
+	return p.err()
+}
+
+func (cfg *AuthCfg) Validate() error {
+	p := cfg.problems()
+	p.redis("csrf", cfg.Csrf)
+	p.redis("session", cfg.Session)
+	if port, err := strconv.Atoi(cfg.Grpc.Port); err != nil {
+		p.add("grpc.port", "%q is not a port", cfg.Grpc.Port)
+	} else {
+		p.port("grpc.port", port)
+	}
+	p.oneOf("grpc.connection_type", cfg.Grpc.ConnectionType, []string{"tcp", "tcp4", "tcp6"})
+	return p.err()
+}
+
+func (cfg *CommentCfg) Validate() error {
+	var p problems
+	p.oneOf("environment", cfg.Environment, environments)
+	p.db(cfg.User, cfg.DbName, cfg.Host, cfg.Port, cfg.Sslmode, cfg.MaxOpenConns, cfg.Timer)
+	p.address("server_adress", cfg.ServerAdress)
+	p.address("admin_adress", cfg.AdminAdress)
+	if cfg.AdminAdress == cfg.ServerAdress {
+		p.add("admin_adress", "is server_adress, the admin endpoints must not be served with the API")
+	}
+	if cfg.ShutdownTimeout <= 0 {
+		p.add("shutdown_timeout", "must be positive")
+	}
+	p.http(cfg.Http)
+	p.address("grpc_port", cfg.GrpcPort)
+	p.oneOf("comment_db", cfg.Comments_db, []string{StoragePostgres})
+	p.service(cfg.Metrics, cfg.Tracing, cfg.Log, cfg.AccessLog)
+	return p.err()
+}
@@ -9,35 +9,38 @@ import (
 	"net/http"
 	"os"
 	"strconv"
-	"time"
 
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/films/usecase"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/httpserver"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/middleware"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/requests"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// Metrics are the metric components the service wires up, its dashboard and
+// alert rules are generated from them.
+var Metrics = metrics.Service{
+	Name:       "films",
+	Components: []string{metrics.ComponentHTTP, metrics.ComponentDB, metrics.ComponentGrpcClient, metrics.ComponentFilmsKPI},
+}
+
 type API struct {
-	core   usecase.ICore
-	lg     *slog.Logger
-	mx     *http.ServeMux
-	mt     *metrics.Metrics
-	adress string
+	core usecase.ICore
+	lg   *slog.Logger
+	mx   *http.ServeMux
+	*httpserver.Server
 }
 
-func GetApi(c *usecase.Core, l *slog.Logger, cfg *configs.DbDsnCfg) *API {
+func GetApi(c *usecase.Core, l *slog.Logger, cfg *configs.DbDsnCfg, mt *metrics.Metrics, al *middleware.AccessLog, levels *logging.Levels) *API {
 	api := &API{
-		core:   c,
-		lg:     l.With("module", "api"),
-		mx:     http.NewServeMux(),
-		mt:     metrics.GetMetrics(),
-		adress: cfg.ServerAdress,
+		core: c,
+		lg:   l.With("module", "api"),
+		mx:   http.NewServeMux(),
 	}
 
-	api.mx.Handle("/metrics", promhttp.Handler())
 	api.mx.HandleFunc("/api/v1/films", api.Films)
 	api.mx.HandleFunc("/api/v1/film", api.Film)
 	api.mx.HandleFunc("/api/v1/actor", api.Actor)
@@ -53,23 +56,16 @@ func GetApi(c *usecase.Core, l *slog.Logger, cfg *configs.DbDsnCfg) *API {
 	api.mx.Handle("/api/v1/rating/add", middleware.AuthCheck(http.HandlerFunc(api.AddRating), c, l))
 	api.mx.HandleFunc("/api/v1/add/film", api.AddFilm)
 
-	return api
-}
+	api.Server = httpserver.GetServer(api.mx, cfg.ServerAdress, cfg.AdminAdress, cfg.Http, l, mt, al, levels, "/api/v1/add/film")
 
-func (a *API) ListenAndServe() {
-	err := http.ListenAndServe(a.adress, a.mx)
-	if err != nil {
-		a.lg.Error("listen and serve error", "err", err.Error())
-	}
+	return api
 }
 
 func (a *API) Films(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
-	start := time.Now()
-
 	if r.Method != http.MethodGet {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -89,11 +85,11 @@ func (a *API) Films(w http.ResponseWriter, r *http.Request) {
 
 	var films []models.FilmItem
 
-	films, genre, err := a.core.GetFilmsAndGenreTitle(genreId, uint64((page-1)*pageSize), pageSize)
+	films, genre, err := a.core.GetFilmsAndGenreTitle(r.Context(), genreId, uint64((page-1)*pageSize), pageSize)
 	if err != nil {
-		a.lg.Error("get films error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("get films error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -106,84 +102,81 @@ func (a *API) Films(w http.ResponseWriter, r *http.Request) {
 	}
 	response.Body = filmsResponse
 
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) Film(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
-	start := time.Now()
 	if r.Method != http.MethodGet {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	filmId, err := strconv.ParseUint(r.URL.Query().Get("film_id"), 10, 64)
 	if err != nil {
 		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	film, err := a.core.GetFilmInfo(filmId)
+	film, err := a.core.GetFilmInfo(r.Context(), filmId)
 	if err != nil {
 		if errors.Is(err, usecase.ErrNotFound) {
 			response.Status = http.StatusNotFound
-			requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+			requests.SendResponse(w, response, a.lg)
 			return
 		}
-		a.lg.Error("film error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("film error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	response.Body = film
 
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) Actor(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
-	start := time.Now()
 	if r.Method != http.MethodGet {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	actorId, err := strconv.ParseUint(r.URL.Query().Get("actor_id"), 10, 64)
 	if err != nil {
-		a.lg.Error("actor error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("actor error", "err", err.Error())
 		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	actor, err := a.core.GetActorInfo(actorId)
+	actor, err := a.core.GetActorInfo(r.Context(), actorId)
 	if err != nil {
 		if errors.Is(err, usecase.ErrNotFound) {
 			response.Status = http.StatusNotFound
-			requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+			requests.SendResponse(w, response, a.lg)
 			return
 		}
-		a.lg.Error("actor error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("actor error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
 
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 	response.Body = actor
 
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) FindFilm(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
-	start := time.Now()
 	if r.Method != http.MethodPost {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -191,30 +184,30 @@ func (a *API) FindFilm(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.lg.Error("find film error", "err", err.Error())
-		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		logging.FromContext(r.Context(), a.lg).Error("find film error", "err", err.Error())
+		response.Status = requests.BodyStatus(err)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	if err = json.Unmarshal(body, &request); err != nil {
-		a.lg.Error("find film error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("find film error", "err", err.Error())
 		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	films, err := a.core.FindFilm(request.Title, request.DateFrom, request.DateTo, request.RatingFrom, request.RatingTo, request.Mpaa, request.Genres, request.Actors)
+	films, err := a.core.FindFilm(r.Context(), request.Title, request.DateFrom, request.DateTo, request.RatingFrom, request.RatingTo, request.Mpaa, request.Genres, request.Actors)
 	if err != nil {
 		if errors.Is(err, usecase.ErrNotFound) {
 			response.Status = http.StatusNotFound
-			requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+			requests.SendResponse(w, response, a.lg)
 			return
 		}
 
-		a.lg.Error("find film error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("find film error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -224,15 +217,14 @@ func (a *API) FindFilm(w http.ResponseWriter, r *http.Request) {
 	}
 	response.Body = filmsResponse
 
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) FavoriteFilmsAdd(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
-	start := time.Now()
 	if r.Method != http.MethodGet {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -241,33 +233,32 @@ func (a *API) FavoriteFilmsAdd(w http.ResponseWriter, r *http.Request) {
 	filmId, err := strconv.ParseUint(r.URL.Query().Get("film_id"), 10, 64)
 	if err != nil {
 		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	err = a.core.FavoriteFilmsAdd(userId, filmId)
+	err = a.core.FavoriteFilmsAdd(r.Context(), userId, filmId)
 	if err != nil {
 		if errors.Is(err, usecase.ErrFoundFavorite) {
 			response.Status = http.StatusNotAcceptable
-			requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+			requests.SendResponse(w, response, a.lg)
 			return
 		}
 
-		a.lg.Error("favorite films error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("favorite films error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) FavoriteFilmsRemove(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
-	start := time.Now()
 	if r.Method != http.MethodGet {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -276,27 +267,26 @@ func (a *API) FavoriteFilmsRemove(w http.ResponseWriter, r *http.Request) {
 	filmId, err := strconv.ParseUint(r.URL.Query().Get("film_id"), 10, 64)
 	if err != nil {
 		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	err = a.core.FavoriteFilmsRemove(userId, filmId)
+	err = a.core.FavoriteFilmsRemove(r.Context(), userId, filmId)
 	if err != nil {
-		a.lg.Error("favorite films error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("favorite films error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) FavoriteFilms(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
-	start := time.Now()
 	if r.Method != http.MethodGet {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -312,45 +302,43 @@ func (a *API) FavoriteFilms(w http.ResponseWriter, r *http.Request) {
 		pageSize = 8
 	}
 
-	films, err := a.core.FavoriteFilms(userId, uint64((page-1)*pageSize), pageSize)
+	films, err := a.core.FavoriteFilms(r.Context(), userId, uint64((page-1)*pageSize), pageSize)
 	if err != nil {
-		a.lg.Error("favorite films error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("favorite films error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 	response.Body = films
 
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) Calendar(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
-	start := time.Now()
 	if r.Method != http.MethodGet {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	calendar, err := a.core.GetCalendar()
+	calendar, err := a.core.GetCalendar(r.Context())
 	if err != nil {
-		a.lg.Error("calendar error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("calendar error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 	response.Body = calendar
 
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) FindActor(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
-	start := time.Now()
 	if r.Method != http.MethodPost {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -358,30 +346,30 @@ func (a *API) FindActor(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		a.lg.Error("find actor error", "err", err.Error())
-		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		logging.FromContext(r.Context(), a.lg).Error("find actor error", "err", err.Error())
+		response.Status = requests.BodyStatus(err)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	if err = json.Unmarshal(body, &request); err != nil {
-		a.lg.Error("find actor error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("find actor error", "err", err.Error())
 		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	actors, err := a.core.FindActor(request.Name, request.BirthDate, request.Films, request.Career, request.Country)
+	actors, err := a.core.FindActor(r.Context(), request.Name, request.BirthDate, request.Films, request.Career, request.Country)
 	if err != nil {
 		if errors.Is(err, usecase.ErrNotFound) {
 			response.Status = http.StatusNotFound
-			requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+			requests.SendResponse(w, response, a.lg)
 			return
 		}
 
-		a.lg.Error("find actor error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("find actor error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -390,15 +378,14 @@ func (a *API) FindActor(w http.ResponseWriter, r *http.Request) {
 	}
 	response.Body = actorsResponse
 
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) AddRating(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
-	start := time.Now()
 	if r.Method != http.MethodPost {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -408,45 +395,44 @@ func (a *API) AddRating(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		response.Status = requests.BodyStatus(err)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	if err = json.Unmarshal(body, &commentRequest); err != nil {
 		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	found, err := a.core.AddRating(commentRequest.FilmId, userId, commentRequest.Rating)
+	found, err := a.core.AddRating(r.Context(), commentRequest.FilmId, userId, commentRequest.Rating)
 	if err != nil {
-		a.lg.Error("add rating error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("add rating error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
 	}
 	if found {
 		response.Status = http.StatusNotAcceptable
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) AddFilm(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
-	start := time.Now()
 	if r.Method != http.MethodPost {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	err := r.ParseMultipartForm(10 << 20)
+	err := r.ParseMultipartForm(a.Limit("/api/v1/add/film"))
 	if err != nil {
-		a.lg.Error("add film error", "err", err.Error())
-		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		logging.FromContext(r.Context(), a.lg).Error("add film error", "err", err.Error())
+		response.Status = requests.BodyStatus(err)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -462,9 +448,9 @@ func (a *API) AddFilm(w http.ResponseWriter, r *http.Request) {
 		if genresString[i] == ',' {
 			genreUint, err := strconv.ParseUint(genresString[prev:i], 10, 64)
 			if err != nil {
-				a.lg.Error("add film error", "err", err.Error())
+				logging.FromContext(r.Context(), a.lg).Error("add film error", "err", err.Error())
 				response.Status = http.StatusBadRequest
-				requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+				requests.SendResponse(w, response, a.lg)
 				return
 			}
 			genres = append(genres, genreUint)
@@ -473,9 +459,9 @@ func (a *API) AddFilm(w http.ResponseWriter, r *http.Request) {
 	}
 	genreUint, err := strconv.ParseUint(genresString[prev:], 10, 64)
 	if err != nil {
-		a.lg.Error("add film error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("add film error", "err", err.Error())
 		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 	genres = append(genres, genreUint)
@@ -487,9 +473,9 @@ func (a *API) AddFilm(w http.ResponseWriter, r *http.Request) {
 		if actorsString[i] == ',' {
 			actorUint, err := strconv.ParseUint(actorsString[prev:i], 10, 64)
 			if err != nil {
-				a.lg.Error("add film error", "err", err.Error())
+				logging.FromContext(r.Context(), a.lg).Error("add film error", "err", err.Error())
 				response.Status = http.StatusBadRequest
-				requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+				requests.SendResponse(w, response, a.lg)
 				return
 			}
 			actors = append(actors, actorUint)
@@ -498,9 +484,9 @@ func (a *API) AddFilm(w http.ResponseWriter, r *http.Request) {
 	}
 	actorUint, err := strconv.ParseUint(actorsString[prev:], 10, 64)
 	if err != nil {
-		a.lg.Error("add film error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("add film error", "err", err.Error())
 		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 	actors = append(actors, actorUint)
@@ -508,34 +494,34 @@ func (a *API) AddFilm(w http.ResponseWriter, r *http.Request) {
 	fmt.Println(actors, genres)
 	poster, handler, err := r.FormFile("photo")
 	if err != nil && !errors.Is(err, http.ErrMissingFile) {
-		a.lg.Error("add film error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("add film error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	filename := "/icons/" + handler.Filename
 	if err != nil && handler != nil && poster != nil {
-		a.lg.Error("Post profile error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("Post profile error", "err", err.Error())
 		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
 	filePhoto, err := os.OpenFile("/home/ubuntu/frontend-project"+filename, os.O_WRONLY|os.O_CREATE, 0666)
 	if err != nil {
-		a.lg.Error("add film error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("add film error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 	defer filePhoto.Close()
 
 	_, err = io.Copy(filePhoto, poster)
 	if err != nil {
-		a.lg.Error("add film error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("add film error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -547,23 +533,22 @@ func (a *API) AddFilm(w http.ResponseWriter, r *http.Request) {
 		Country:     country,
 	}
 
-	err = a.core.AddFilm(film, genres, actors)
+	err = a.core.AddFilm(r.Context(), film, genres, actors)
 	if err != nil {
-		a.lg.Error("add film error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("add film error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) FavoriteActorsAdd(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
-	start := time.Now()
 	if r.Method != http.MethodGet {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -572,32 +557,31 @@ func (a *API) FavoriteActorsAdd(w http.ResponseWriter, r *http.Request) {
 	actorId, err := strconv.ParseUint(r.URL.Query().Get("actor_id"), 10, 64)
 	if err != nil {
 		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	err = a.core.FavoriteActorsAdd(userId, actorId)
+	err = a.core.FavoriteActorsAdd(r.Context(), userId, actorId)
 	if err != nil {
 		if errors.Is(err, usecase.ErrFoundFavorite) {
 			response.Status = http.StatusNotAcceptable
-			requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+			requests.SendResponse(w, response, a.lg)
 			return
 		}
-		a.lg.Error("favorite actors error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("favorite actors error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) FavoriteActorsRemove(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
-	start := time.Now()
 	if r.Method != http.MethodGet {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -606,27 +590,26 @@ func (a *API) FavoriteActorsRemove(w http.ResponseWriter, r *http.Request) {
 	actorId, err := strconv.ParseUint(r.URL.Query().Get("actor_id"), 10, 64)
 	if err != nil {
 		response.Status = http.StatusBadRequest
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	err = a.core.FavoriteActorsRemove(userId, actorId)
+	err = a.core.FavoriteActorsRemove(r.Context(), userId, actorId)
 	if err != nil {
-		a.lg.Error("favorite actors error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("favorite actors error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
 
 func (a *API) FavoriteActors(w http.ResponseWriter, r *http.Request) {
 	response := requests.Response{Status: http.StatusOK, Body: nil}
-	start := time.Now()
 	if r.Method != http.MethodGet {
 		response.Status = http.StatusMethodNotAllowed
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -641,11 +624,11 @@ func (a *API) FavoriteActors(w http.ResponseWriter, r *http.Request) {
 		pageSize = 8
 	}
 
-	actors, err := a.core.FavoriteActors(userId, uint64((page-1)*pageSize), pageSize)
+	actors, err := a.core.FavoriteActors(r.Context(), userId, uint64((page-1)*pageSize), pageSize)
 	if err != nil {
-		a.lg.Error("favorite actors error", "err", err.Error())
+		logging.FromContext(r.Context(), a.lg).Error("favorite actors error", "err", err.Error())
 		response.Status = http.StatusInternalServerError
-		requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+		requests.SendResponse(w, response, a.lg)
 		return
 	}
 
@@ -655,5 +638,5 @@ func (a *API) FavoriteActors(w http.ResponseWriter, r *http.Request) {
 
 	response.Body = actorsResponse
 
-	requests.SendResponse(w, r.URL.Path, response, a.lg, a.mt, start)
+	requests.SendResponse(w, response, a.lg)
 }
@@ -41,6 +41,33 @@ func createRatingBody(req requests.CommentRequest) io.Reader {
 	return body
 }
 
+func decodeResponse(t *testing.T, w *httptest.ResponseRecorder) requests.Response {
+	t.Helper()
+
+	var response requests.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("cant decode response: %s", err)
+	}
+
+	return response
+}
+
+func jsonValue(t *testing.T, body any) any {
+	t.Helper()
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("cant encode body: %s", err)
+	}
+
+	var value any
+	if err := json.Unmarshal(jsonBody, &value); err != nil {
+		t.Fatalf("cant decode body: %s", err)
+	}
+
+	return value
+}
+
 var resp requests.Response = requests.Response{
 	Status: http.StatusOK,
 	Body:   nil,
@@ -82,15 +109,13 @@ func TestFilms(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockCore := mocks.NewMockICore(mockCtrl)
-	mockCore.EXPECT().GetFilmsAndGenreTitle(uint64(0), uint64(0), uint64(8)).Return(nil, "", fmt.Errorf("core_err")).Times(1)
-	mockCore.EXPECT().GetFilmsAndGenreTitle(uint64(1), uint64(0), uint64(8)).Return(expectedFilms, expectedGenre, nil).Times(1)
+	mockCore.EXPECT().GetFilmsAndGenreTitle(gomock.Any(), uint64(0), uint64(0), uint64(8)).Return(nil, "", fmt.Errorf("core_err")).Times(1)
+	mockCore.EXPECT().GetFilmsAndGenreTitle(gomock.Any(), uint64(1), uint64(0), uint64(8)).Return(expectedFilms, expectedGenre, nil).Times(1)
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 
 	api := API{core: mockCore, lg: logger}
 
-	response := requests.Response{Status: http.StatusOK, Body: nil}
-
 	for _, curr := range testCases {
 		r := httptest.NewRequest(curr.method, "/api/v1/films", nil)
 		q := r.URL.Query()
@@ -102,12 +127,14 @@ func TestFilms(t *testing.T) {
 
 		api.Films(w, r)
 
+		response := decodeResponse(t, w)
+
 		if response.Status != curr.result.Status {
 			t.Errorf("unexpected status: %d, want %d", response.Status, curr.result.Status)
 			return
 		}
-		if !reflect.DeepEqual(response.Body, curr.result.Body) {
-			t.Errorf("wanted %v, got %v", curr.result.Body, response.Body)
+		if !reflect.DeepEqual(response.Body, jsonValue(t, curr.result.Body)) {
+			t.Errorf("wanted %v, got %v", jsonValue(t, curr.result.Body), response.Body)
 			return
 		}
 	}
@@ -162,16 +189,14 @@ func TestFilm(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockCore := mocks.NewMockICore(mockCtrl)
-	mockCore.EXPECT().GetFilmInfo(uint64(1)).Return(nil, fmt.Errorf("core_err")).Times(1)
-	mockCore.EXPECT().GetFilmInfo(uint64(2)).Return(nil, usecase.ErrNotFound).Times(1)
-	mockCore.EXPECT().GetFilmInfo(uint64(3)).Return(expectedResponse, nil).Times(1)
+	mockCore.EXPECT().GetFilmInfo(gomock.Any(), uint64(1)).Return(nil, fmt.Errorf("core_err")).Times(1)
+	mockCore.EXPECT().GetFilmInfo(gomock.Any(), uint64(2)).Return(nil, usecase.ErrNotFound).Times(1)
+	mockCore.EXPECT().GetFilmInfo(gomock.Any(), uint64(3)).Return(expectedResponse, nil).Times(1)
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 
 	api := API{core: mockCore, lg: logger}
 
-	response := requests.Response{Status: http.StatusOK, Body: nil}
-
 	for _, curr := range testCases {
 		r := httptest.NewRequest(curr.method, "/api/v1/film", nil)
 		q := r.URL.Query()
@@ -183,12 +208,14 @@ func TestFilm(t *testing.T) {
 
 		api.Film(w, r)
 
+		response := decodeResponse(t, w)
+
 		if response.Status != curr.result.Status {
 			t.Errorf("unexpected status: %d, want %d", response.Status, curr.result.Status)
 			return
 		}
-		if !reflect.DeepEqual(response.Body, curr.result.Body) {
-			t.Errorf("wanted %v, got %v", curr.result.Body, response.Body)
+		if !reflect.DeepEqual(response.Body, jsonValue(t, curr.result.Body)) {
+			t.Errorf("wanted %v, got %v", jsonValue(t, curr.result.Body), response.Body)
 			return
 		}
 	}
@@ -237,16 +264,14 @@ func TestActor(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockCore := mocks.NewMockICore(mockCtrl)
-	mockCore.EXPECT().GetActorInfo(uint64(1)).Return(nil, fmt.Errorf("core_err")).Times(1)
-	mockCore.EXPECT().GetActorInfo(uint64(2)).Return(nil, usecase.ErrNotFound).Times(1)
-	mockCore.EXPECT().GetActorInfo(uint64(3)).Return(expectedResponse, nil).Times(1)
+	mockCore.EXPECT().GetActorInfo(gomock.Any(), uint64(1)).Return(nil, fmt.Errorf("core_err")).Times(1)
+	mockCore.EXPECT().GetActorInfo(gomock.Any(), uint64(2)).Return(nil, usecase.ErrNotFound).Times(1)
+	mockCore.EXPECT().GetActorInfo(gomock.Any(), uint64(3)).Return(expectedResponse, nil).Times(1)
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 
 	api := API{core: mockCore, lg: logger}
 
-	response := requests.Response{Status: http.StatusOK, Body: nil}
-
 	for _, curr := range testCases {
 		r := httptest.NewRequest(curr.method, "/api/v1/actor", nil)
 		q := r.URL.Query()
@@ -258,12 +283,14 @@ func TestActor(t *testing.T) {
 
 		api.Actor(w, r)
 
+		response := decodeResponse(t, w)
+
 		if response.Status != curr.result.Status {
 			t.Errorf("unexpected status: %d, want %d", response.Status, curr.result.Status)
 			return
 		}
-		if !reflect.DeepEqual(response.Body, curr.result.Body) {
-			t.Errorf("wanted %v, got %v", curr.result.Body, response.Body)
+		if !reflect.DeepEqual(response.Body, jsonValue(t, curr.result.Body)) {
+			t.Errorf("wanted %v, got %v", jsonValue(t, curr.result.Body), response.Body)
 			return
 		}
 	}
@@ -312,28 +339,28 @@ func TestFindFilm(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockCore := mocks.NewMockICore(mockCtrl)
-	mockCore.EXPECT().FindFilm(string("t1"), string(""), string(""), float32(0), float32(0), string(""), nil, nil).Return(nil, fmt.Errorf("core_err")).Times(1)
-	mockCore.EXPECT().FindFilm(string("t2"), string(""), string(""), float32(0), float32(0), string(""), nil, nil).Return(nil, usecase.ErrNotFound).Times(1)
-	mockCore.EXPECT().FindFilm(string("t3"), string(""), string(""), float32(0), float32(0), string(""), nil, nil).Return(films, nil).Times(1)
+	mockCore.EXPECT().FindFilm(gomock.Any(), string("t1"), string(""), string(""), float32(0), float32(0), string(""), nil, nil).Return(nil, fmt.Errorf("core_err")).Times(1)
+	mockCore.EXPECT().FindFilm(gomock.Any(), string("t2"), string(""), string(""), float32(0), float32(0), string(""), nil, nil).Return(nil, usecase.ErrNotFound).Times(1)
+	mockCore.EXPECT().FindFilm(gomock.Any(), string("t3"), string(""), string(""), float32(0), float32(0), string(""), nil, nil).Return(films, nil).Times(1)
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 
 	api := API{core: mockCore, lg: logger}
 
-	response := requests.Response{Status: http.StatusOK, Body: nil}
-
 	for _, curr := range testCases {
 		r := httptest.NewRequest(curr.method, "/api/v1/search/film", curr.body)
 		w := httptest.NewRecorder()
 
 		api.FindFilm(w, r)
 
+		response := decodeResponse(t, w)
+
 		if response.Status != curr.result.Status {
 			t.Errorf("unexpected status: %d, want %d", response.Status, curr.result.Status)
 			return
 		}
-		if !reflect.DeepEqual(response.Body, curr.result.Body) {
-			t.Errorf("wanted %v, got %v", curr.result.Body, response.Body)
+		if !reflect.DeepEqual(response.Body, jsonValue(t, curr.result.Body)) {
+			t.Errorf("wanted %v, got %v", jsonValue(t, curr.result.Body), response.Body)
 			return
 		}
 	}
@@ -381,28 +408,28 @@ func TestFindActor(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockCore := mocks.NewMockICore(mockCtrl)
-	mockCore.EXPECT().FindActor(string("n1"), string(""), nil, nil, string("")).Return(nil, fmt.Errorf("core_err")).Times(1)
-	mockCore.EXPECT().FindActor(string("n2"), string(""), nil, nil, string("")).Return(nil, usecase.ErrNotFound).Times(1)
-	mockCore.EXPECT().FindActor(string("n3"), string(""), nil, nil, string("")).Return(actors, nil).Times(1)
+	mockCore.EXPECT().FindActor(gomock.Any(), string("n1"), string(""), nil, nil, string("")).Return(nil, fmt.Errorf("core_err")).Times(1)
+	mockCore.EXPECT().FindActor(gomock.Any(), string("n2"), string(""), nil, nil, string("")).Return(nil, usecase.ErrNotFound).Times(1)
+	mockCore.EXPECT().FindActor(gomock.Any(), string("n3"), string(""), nil, nil, string("")).Return(actors, nil).Times(1)
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 
 	api := API{core: mockCore, lg: logger}
 
-	response := requests.Response{Status: http.StatusOK, Body: nil}
-
 	for _, curr := range testCases {
 		r := httptest.NewRequest(curr.method, "/api/v1/search/actor", curr.body)
 		w := httptest.NewRecorder()
 
 		api.FindActor(w, r)
 
+		response := decodeResponse(t, w)
+
 		if response.Status != curr.result.Status {
 			t.Errorf("unexpected status: %d, want %d", response.Status, curr.result.Status)
 			return
 		}
-		if !reflect.DeepEqual(response.Body, curr.result.Body) {
-			t.Errorf("wanted %v, got %v", curr.result.Body, response.Body)
+		if !reflect.DeepEqual(response.Body, jsonValue(t, curr.result.Body)) {
+			t.Errorf("wanted %v, got %v", jsonValue(t, curr.result.Body), response.Body)
 			return
 		}
 	}
@@ -415,20 +442,27 @@ func TestCalendar(t *testing.T) {
 	}
 
 	testCases := map[string]struct {
-		method string
-		result *requests.Response
+		method   string
+		coreCall bool
+		calendar *requests.CalendarResponse
+		coreErr  error
+		result   *requests.Response
 	}{
 		"Bad method": {
 			method: http.MethodPost,
 			result: &requests.Response{Status: http.StatusMethodNotAllowed, Body: nil},
 		},
 		"Core error": {
-			method: http.MethodGet,
-			result: &requests.Response{Status: http.StatusInternalServerError, Body: nil},
+			method:   http.MethodGet,
+			coreCall: true,
+			coreErr:  fmt.Errorf("core_err"),
+			result:   &requests.Response{Status: http.StatusInternalServerError, Body: nil},
 		},
 		"Ok": {
-			method: http.MethodGet,
-			result: &requests.Response{Status: http.StatusOK, Body: expectedResponse},
+			method:   http.MethodGet,
+			coreCall: true,
+			calendar: expectedResponse,
+			result:   &requests.Response{Status: http.StatusOK, Body: expectedResponse},
 		},
 	}
 
@@ -436,27 +470,29 @@ func TestCalendar(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockCore := mocks.NewMockICore(mockCtrl)
-	mockCore.EXPECT().GetCalendar().Return(nil, fmt.Errorf("core_err")).Times(1)
-	mockCore.EXPECT().GetCalendar().Return(expectedResponse, nil).Times(1)
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 
 	api := API{core: mockCore, lg: logger}
 
-	response := requests.Response{Status: http.StatusOK, Body: nil}
-
 	for _, curr := range testCases {
+		if curr.coreCall {
+			mockCore.EXPECT().GetCalendar(gomock.Any()).Return(curr.calendar, curr.coreErr).Times(1)
+		}
+
 		r := httptest.NewRequest(curr.method, "/api/v1/calendar", nil)
 		w := httptest.NewRecorder()
 
 		api.Calendar(w, r)
 
+		response := decodeResponse(t, w)
+
 		if response.Status != curr.result.Status {
 			t.Errorf("unexpected status: %d, want %d", response.Status, curr.result.Status)
 			return
 		}
-		if !reflect.DeepEqual(response.Body, curr.result.Body) {
-			t.Errorf("wanted %v, got %v", curr.result.Body, response.Body)
+		if !reflect.DeepEqual(response.Body, jsonValue(t, curr.result.Body)) {
+			t.Errorf("wanted %v, got %v", jsonValue(t, curr.result.Body), response.Body)
 			return
 		}
 	}
@@ -498,16 +534,14 @@ func TestFavoriteFilmsAdd(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockCore := mocks.NewMockICore(mockCtrl)
-	mockCore.EXPECT().FavoriteFilmsAdd(uint64(1), uint64(1)).Return(fmt.Errorf("core_err")).Times(1)
-	mockCore.EXPECT().FavoriteFilmsAdd(uint64(1), uint64(2)).Return(usecase.ErrFoundFavorite).Times(1)
-	mockCore.EXPECT().FavoriteFilmsAdd(uint64(1), uint64(3)).Return(nil).Times(1)
+	mockCore.EXPECT().FavoriteFilmsAdd(gomock.Any(), uint64(1), uint64(1)).Return(fmt.Errorf("core_err")).Times(1)
+	mockCore.EXPECT().FavoriteFilmsAdd(gomock.Any(), uint64(1), uint64(2)).Return(usecase.ErrFoundFavorite).Times(1)
+	mockCore.EXPECT().FavoriteFilmsAdd(gomock.Any(), uint64(1), uint64(3)).Return(nil).Times(1)
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 
 	api := API{core: mockCore, lg: logger}
 
-	response := requests.Response{Status: http.StatusOK, Body: nil}
-
 	for _, curr := range testCases {
 		r := httptest.NewRequest(curr.method, "/api/v1/favorite/film/add", nil)
 		newReq := r.WithContext(context.WithValue(r.Context(), middleware.UserIDKey, uint64(1)))
@@ -520,12 +554,14 @@ func TestFavoriteFilmsAdd(t *testing.T) {
 
 		api.FavoriteFilmsAdd(w, newReq)
 
+		response := decodeResponse(t, w)
+
 		if response.Status != curr.result.Status {
 			t.Errorf("unexpected status: %d, want %d", response.Status, curr.result.Status)
 			return
 		}
-		if !reflect.DeepEqual(response.Body, curr.result.Body) {
-			t.Errorf("wanted %v, got %v", curr.result.Body, response.Body)
+		if !reflect.DeepEqual(response.Body, jsonValue(t, curr.result.Body)) {
+			t.Errorf("wanted %v, got %v", jsonValue(t, curr.result.Body), response.Body)
 			return
 		}
 	}
@@ -562,15 +598,13 @@ func TestFavoriteFilmsRemove(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockCore := mocks.NewMockICore(mockCtrl)
-	mockCore.EXPECT().FavoriteFilmsRemove(uint64(1), uint64(1)).Return(fmt.Errorf("core_err")).Times(1)
-	mockCore.EXPECT().FavoriteFilmsRemove(uint64(1), uint64(3)).Return(nil).Times(1)
+	mockCore.EXPECT().FavoriteFilmsRemove(gomock.Any(), uint64(1), uint64(1)).Return(fmt.Errorf("core_err")).Times(1)
+	mockCore.EXPECT().FavoriteFilmsRemove(gomock.Any(), uint64(1), uint64(3)).Return(nil).Times(1)
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 
 	api := API{core: mockCore, lg: logger}
 
-	response := requests.Response{Status: http.StatusOK, Body: nil}
-
 	for _, curr := range testCases {
 		r := httptest.NewRequest(curr.method, "/api/v1/favorite/film/remove", nil)
 		newReq := r.WithContext(context.WithValue(r.Context(), middleware.UserIDKey, uint64(1)))
@@ -583,12 +617,14 @@ func TestFavoriteFilmsRemove(t *testing.T) {
 
 		api.FavoriteFilmsRemove(w, newReq)
 
+		response := decodeResponse(t, w)
+
 		if response.Status != curr.result.Status {
 			t.Errorf("unexpected status: %d, want %d", response.Status, curr.result.Status)
 			return
 		}
-		if !reflect.DeepEqual(response.Body, curr.result.Body) {
-			t.Errorf("wanted %v, got %v", curr.result.Body, response.Body)
+		if !reflect.DeepEqual(response.Body, jsonValue(t, curr.result.Body)) {
+			t.Errorf("wanted %v, got %v", jsonValue(t, curr.result.Body), response.Body)
 			return
 		}
 	}
@@ -623,15 +659,13 @@ func TestFavoriteFilms(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockCore := mocks.NewMockICore(mockCtrl)
-	mockCore.EXPECT().FavoriteFilms(uint64(1), uint64(8), uint64(8)).Return(nil, fmt.Errorf("core_err")).Times(1)
-	mockCore.EXPECT().FavoriteFilms(uint64(1), uint64(0), uint64(8)).Return(films, nil).Times(1)
+	mockCore.EXPECT().FavoriteFilms(gomock.Any(), uint64(1), uint64(8), uint64(8)).Return(nil, fmt.Errorf("core_err")).Times(1)
+	mockCore.EXPECT().FavoriteFilms(gomock.Any(), uint64(1), uint64(0), uint64(8)).Return(films, nil).Times(1)
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 
 	api := API{core: mockCore, lg: logger}
 
-	response := requests.Response{Status: http.StatusOK, Body: nil}
-
 	for _, curr := range testCases {
 		r := httptest.NewRequest(curr.method, "/api/v1/favorite/films", nil)
 		newReq := r.WithContext(context.WithValue(r.Context(), middleware.UserIDKey, uint64(1)))
@@ -644,12 +678,14 @@ func TestFavoriteFilms(t *testing.T) {
 
 		api.FavoriteFilms(w, newReq)
 
+		response := decodeResponse(t, w)
+
 		if response.Status != curr.result.Status {
 			t.Errorf("unexpected status: %d, want %d", response.Status, curr.result.Status)
 			return
 		}
-		if !reflect.DeepEqual(response.Body, curr.result.Body) {
-			t.Errorf("wanted %v, got %v", curr.result.Body, response.Body)
+		if !reflect.DeepEqual(response.Body, jsonValue(t, curr.result.Body)) {
+			t.Errorf("wanted %v, got %v", jsonValue(t, curr.result.Body), response.Body)
 			return
 		}
 	}
@@ -691,16 +727,14 @@ func TestFavoriteActorsAdd(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockCore := mocks.NewMockICore(mockCtrl)
-	mockCore.EXPECT().FavoriteActorsAdd(uint64(1), uint64(1)).Return(fmt.Errorf("core_err")).Times(1)
-	mockCore.EXPECT().FavoriteActorsAdd(uint64(1), uint64(2)).Return(usecase.ErrFoundFavorite).Times(1)
-	mockCore.EXPECT().FavoriteActorsAdd(uint64(1), uint64(3)).Return(nil).Times(1)
+	mockCore.EXPECT().FavoriteActorsAdd(gomock.Any(), uint64(1), uint64(1)).Return(fmt.Errorf("core_err")).Times(1)
+	mockCore.EXPECT().FavoriteActorsAdd(gomock.Any(), uint64(1), uint64(2)).Return(usecase.ErrFoundFavorite).Times(1)
+	mockCore.EXPECT().FavoriteActorsAdd(gomock.Any(), uint64(1), uint64(3)).Return(nil).Times(1)
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 
 	api := API{core: mockCore, lg: logger}
 
-	response := requests.Response{Status: http.StatusOK, Body: nil}
-
 	for _, curr := range testCases {
 		r := httptest.NewRequest(curr.method, "/api/v1/favorite/actor/add", nil)
 		newReq := r.WithContext(context.WithValue(r.Context(), middleware.UserIDKey, uint64(1)))
@@ -713,12 +747,14 @@ func TestFavoriteActorsAdd(t *testing.T) {
 
 		api.FavoriteActorsAdd(w, newReq)
 
+		response := decodeResponse(t, w)
+
 		if response.Status != curr.result.Status {
 			t.Errorf("unexpected status: %d, want %d", response.Status, curr.result.Status)
 			return
 		}
-		if !reflect.DeepEqual(response.Body, curr.result.Body) {
-			t.Errorf("wanted %v, got %v", curr.result.Body, response.Body)
+		if !reflect.DeepEqual(response.Body, jsonValue(t, curr.result.Body)) {
+			t.Errorf("wanted %v, got %v", jsonValue(t, curr.result.Body), response.Body)
 			return
 		}
 	}
@@ -755,15 +791,13 @@ func TestFavoriteActorsRemove(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockCore := mocks.NewMockICore(mockCtrl)
-	mockCore.EXPECT().FavoriteActorsRemove(uint64(1), uint64(1)).Return(fmt.Errorf("core_err")).Times(1)
-	mockCore.EXPECT().FavoriteActorsRemove(uint64(1), uint64(3)).Return(nil).Times(1)
+	mockCore.EXPECT().FavoriteActorsRemove(gomock.Any(), uint64(1), uint64(1)).Return(fmt.Errorf("core_err")).Times(1)
+	mockCore.EXPECT().FavoriteActorsRemove(gomock.Any(), uint64(1), uint64(3)).Return(nil).Times(1)
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 
 	api := API{core: mockCore, lg: logger}
 
-	response := requests.Response{Status: http.StatusOK, Body: nil}
-
 	for _, curr := range testCases {
 		r := httptest.NewRequest(curr.method, "/api/v1/favorite/actor/remove", nil)
 		newReq := r.WithContext(context.WithValue(r.Context(), middleware.UserIDKey, uint64(1)))
@@ -776,12 +810,14 @@ func TestFavoriteActorsRemove(t *testing.T) {
 
 		api.FavoriteActorsRemove(w, newReq)
 
+		response := decodeResponse(t, w)
+
 		if response.Status != curr.result.Status {
 			t.Errorf("unexpected status: %d, want %d", response.Status, curr.result.Status)
 			return
 		}
-		if !reflect.DeepEqual(response.Body, curr.result.Body) {
-			t.Errorf("wanted %v, got %v", curr.result.Body, response.Body)
+		if !reflect.DeepEqual(response.Body, jsonValue(t, curr.result.Body)) {
+			t.Errorf("wanted %v, got %v", jsonValue(t, curr.result.Body), response.Body)
 			return
 		}
 	}
@@ -817,15 +853,13 @@ func TestFavoriteActors(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockCore := mocks.NewMockICore(mockCtrl)
-	mockCore.EXPECT().FavoriteActors(uint64(1), uint64(8), uint64(8)).Return(nil, fmt.Errorf("core_err")).Times(1)
-	mockCore.EXPECT().FavoriteActors(uint64(1), uint64(0), uint64(8)).Return(actors, nil).Times(1)
+	mockCore.EXPECT().FavoriteActors(gomock.Any(), uint64(1), uint64(8), uint64(8)).Return(nil, fmt.Errorf("core_err")).Times(1)
+	mockCore.EXPECT().FavoriteActors(gomock.Any(), uint64(1), uint64(0), uint64(8)).Return(actors, nil).Times(1)
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 
 	api := API{core: mockCore, lg: logger}
 
-	responseHttp := requests.Response{Status: http.StatusOK, Body: nil}
-
 	for _, curr := range testCases {
 		r := httptest.NewRequest(curr.method, "/api/v1/favorite/actors", nil)
 		newReq := r.WithContext(context.WithValue(r.Context(), middleware.UserIDKey, uint64(1)))
@@ -838,12 +872,14 @@ func TestFavoriteActors(t *testing.T) {
 
 		api.FavoriteActors(w, newReq)
 
-		if responseHttp.Status != curr.result.Status {
-			t.Errorf("unexpected status: %d, want %d", responseHttp.Status, curr.result.Status)
+		response := decodeResponse(t, w)
+
+		if response.Status != curr.result.Status {
+			t.Errorf("unexpected status: %d, want %d", response.Status, curr.result.Status)
 			return
 		}
-		if !reflect.DeepEqual(responseHttp.Body, curr.result.Body) {
-			t.Errorf("wanted %v, got %v", curr.result.Body, responseHttp.Body)
+		if !reflect.DeepEqual(response.Body, jsonValue(t, curr.result.Body)) {
+			t.Errorf("wanted %v, got %v", jsonValue(t, curr.result.Body), response.Body)
 			return
 		}
 	}
@@ -885,16 +921,14 @@ func TestAddRating(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockCore := mocks.NewMockICore(mockCtrl)
-	mockCore.EXPECT().AddRating(uint64(1), uint64(1), uint16(0)).Return(false, fmt.Errorf("core_err")).Times(1)
-	mockCore.EXPECT().AddRating(uint64(2), uint64(1), uint16(0)).Return(true, nil).Times(1)
-	mockCore.EXPECT().AddRating(uint64(3), uint64(1), uint16(0)).Return(false, nil).Times(1)
+	mockCore.EXPECT().AddRating(gomock.Any(), uint64(1), uint64(1), uint16(0)).Return(false, fmt.Errorf("core_err")).Times(1)
+	mockCore.EXPECT().AddRating(gomock.Any(), uint64(2), uint64(1), uint16(0)).Return(true, nil).Times(1)
+	mockCore.EXPECT().AddRating(gomock.Any(), uint64(3), uint64(1), uint16(0)).Return(false, nil).Times(1)
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 
 	api := API{core: mockCore, lg: logger}
 
-	response := requests.Response{Status: http.StatusOK, Body: nil}
-
 	for _, curr := range testCases {
 		r := httptest.NewRequest(curr.method, "/api/v1/rating/add", curr.body)
 		newReq := r.WithContext(context.WithValue(r.Context(), middleware.UserIDKey, uint64(1)))
@@ -903,12 +937,14 @@ func TestAddRating(t *testing.T) {
 
 		api.AddRating(w, newReq)
 
+		response := decodeResponse(t, w)
+
 		if response.Status != curr.result.Status {
 			t.Errorf("unexpected status: %d, want %d", response.Status, curr.result.Status)
 			return
 		}
-		if !reflect.DeepEqual(response.Body, curr.result.Body) {
-			t.Errorf("wanted %v, got %v", curr.result.Body, response.Body)
+		if !reflect.DeepEqual(response.Body, jsonValue(t, curr.result.Body)) {
+			t.Errorf("wanted %v, got %v", jsonValue(t, curr.result.Body), response.Body)
 			return
 		}
 	}
@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	models "github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
@@ -34,17 +35,31 @@ func (m *MockICalendarRepo) EXPECT() *MockICalendarRepoMockRecorder {
 	return m.recorder
 }
 
+// Close mocks base method.
+func (m *MockICalendarRepo) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockICalendarRepoMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockICalendarRepo)(nil).Close))
+}
+
 // GetCalendar mocks base method.
-func (m *MockICalendarRepo) GetCalendar() ([]models.DayItem, error) {
+func (m *MockICalendarRepo) GetCalendar(ctx context.Context) ([]models.DayItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCalendar")
+	ret := m.ctrl.Call(m, "GetCalendar", ctx)
 	ret0, _ := ret[0].([]models.DayItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCalendar indicates an expected call of GetCalendar.
-func (mr *MockICalendarRepoMockRecorder) GetCalendar() *gomock.Call {
+func (mr *MockICalendarRepoMockRecorder) GetCalendar(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendar", reflect.TypeOf((*MockICalendarRepo)(nil).GetCalendar))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendar", reflect.TypeOf((*MockICalendarRepo)(nil).GetCalendar), ctx)
 }
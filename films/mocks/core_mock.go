@@ -6,10 +6,11 @@ package mocks
 
 import (
 	context "context"
+	reflect "reflect"
+
 	models "github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
 	requests "github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/requests"
 	gomock "github.com/golang/mock/gomock"
-	reflect "reflect"
 )
 
 type contextKey string
@@ -40,214 +41,214 @@ func (m *MockICore) EXPECT() *MockICoreMockRecorder {
 }
 
 // AddFilm mocks base method.
-func (m *MockICore) AddFilm(film models.FilmItem, genres, actors []uint64) error {
+func (m *MockICore) AddFilm(ctx context.Context, film models.FilmItem, genres, actors []uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddFilm", film, genres, actors)
+	ret := m.ctrl.Call(m, "AddFilm", ctx, film, genres, actors)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddFilm indicates an expected call of AddFilm.
-func (mr *MockICoreMockRecorder) AddFilm(film, genres, actors interface{}) *gomock.Call {
+func (mr *MockICoreMockRecorder) AddFilm(ctx, film, genres, actors interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFilm", reflect.TypeOf((*MockICore)(nil).AddFilm), film, genres, actors)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFilm", reflect.TypeOf((*MockICore)(nil).AddFilm), ctx, film, genres, actors)
 }
 
 // AddRating mocks base method.
-func (m *MockICore) AddRating(filmId, userId uint64, rating uint16) (bool, error) {
+func (m *MockICore) AddRating(ctx context.Context, filmId, userId uint64, rating uint16) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddRating", filmId, userId, rating)
+	ret := m.ctrl.Call(m, "AddRating", ctx, filmId, userId, rating)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddRating indicates an expected call of AddRating.
-func (mr *MockICoreMockRecorder) AddRating(filmId, userId, rating interface{}) *gomock.Call {
+func (mr *MockICoreMockRecorder) AddRating(ctx, filmId, userId, rating interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRating", reflect.TypeOf((*MockICore)(nil).AddRating), filmId, userId, rating)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRating", reflect.TypeOf((*MockICore)(nil).AddRating), ctx, filmId, userId, rating)
 }
 
 // FavoriteActors mocks base method.
-func (m *MockICore) FavoriteActors(userId, start, end uint64) ([]models.Character, error) {
+func (m *MockICore) FavoriteActors(ctx context.Context, userId, start, end uint64) ([]models.Character, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FavoriteActors", userId, start, end)
+	ret := m.ctrl.Call(m, "FavoriteActors", ctx, userId, start, end)
 	ret0, _ := ret[0].([]models.Character)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FavoriteActors indicates an expected call of FavoriteActors.
-func (mr *MockICoreMockRecorder) FavoriteActors(userId, start, end interface{}) *gomock.Call {
+func (mr *MockICoreMockRecorder) FavoriteActors(ctx, userId, start, end interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FavoriteActors", reflect.TypeOf((*MockICore)(nil).FavoriteActors), userId, start, end)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FavoriteActors", reflect.TypeOf((*MockICore)(nil).FavoriteActors), ctx, userId, start, end)
 }
 
 // FavoriteActorsAdd mocks base method.
-func (m *MockICore) FavoriteActorsAdd(userId, filmId uint64) error {
+func (m *MockICore) FavoriteActorsAdd(ctx context.Context, userId, filmId uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FavoriteActorsAdd", userId, filmId)
+	ret := m.ctrl.Call(m, "FavoriteActorsAdd", ctx, userId, filmId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // FavoriteActorsAdd indicates an expected call of FavoriteActorsAdd.
-func (mr *MockICoreMockRecorder) FavoriteActorsAdd(userId, filmId interface{}) *gomock.Call {
+func (mr *MockICoreMockRecorder) FavoriteActorsAdd(ctx, userId, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FavoriteActorsAdd", reflect.TypeOf((*MockICore)(nil).FavoriteActorsAdd), userId, filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FavoriteActorsAdd", reflect.TypeOf((*MockICore)(nil).FavoriteActorsAdd), ctx, userId, filmId)
 }
 
 // FavoriteActorsRemove mocks base method.
-func (m *MockICore) FavoriteActorsRemove(userId, filmId uint64) error {
+func (m *MockICore) FavoriteActorsRemove(ctx context.Context, userId, filmId uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FavoriteActorsRemove", userId, filmId)
+	ret := m.ctrl.Call(m, "FavoriteActorsRemove", ctx, userId, filmId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // FavoriteActorsRemove indicates an expected call of FavoriteActorsRemove.
-func (mr *MockICoreMockRecorder) FavoriteActorsRemove(userId, filmId interface{}) *gomock.Call {
+func (mr *MockICoreMockRecorder) FavoriteActorsRemove(ctx, userId, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FavoriteActorsRemove", reflect.TypeOf((*MockICore)(nil).FavoriteActorsRemove), userId, filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FavoriteActorsRemove", reflect.TypeOf((*MockICore)(nil).FavoriteActorsRemove), ctx, userId, filmId)
 }
 
 // FavoriteFilms mocks base method.
-func (m *MockICore) FavoriteFilms(userId, start, end uint64) ([]models.FilmItem, error) {
+func (m *MockICore) FavoriteFilms(ctx context.Context, userId, start, end uint64) ([]models.FilmItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FavoriteFilms", userId, start, end)
+	ret := m.ctrl.Call(m, "FavoriteFilms", ctx, userId, start, end)
 	ret0, _ := ret[0].([]models.FilmItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FavoriteFilms indicates an expected call of FavoriteFilms.
-func (mr *MockICoreMockRecorder) FavoriteFilms(userId, start, end interface{}) *gomock.Call {
+func (mr *MockICoreMockRecorder) FavoriteFilms(ctx, userId, start, end interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FavoriteFilms", reflect.TypeOf((*MockICore)(nil).FavoriteFilms), userId, start, end)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FavoriteFilms", reflect.TypeOf((*MockICore)(nil).FavoriteFilms), ctx, userId, start, end)
 }
 
 // FavoriteFilmsAdd mocks base method.
-func (m *MockICore) FavoriteFilmsAdd(userId, filmId uint64) error {
+func (m *MockICore) FavoriteFilmsAdd(ctx context.Context, userId, filmId uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FavoriteFilmsAdd", userId, filmId)
+	ret := m.ctrl.Call(m, "FavoriteFilmsAdd", ctx, userId, filmId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // FavoriteFilmsAdd indicates an expected call of FavoriteFilmsAdd.
-func (mr *MockICoreMockRecorder) FavoriteFilmsAdd(userId, filmId interface{}) *gomock.Call {
+func (mr *MockICoreMockRecorder) FavoriteFilmsAdd(ctx, userId, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FavoriteFilmsAdd", reflect.TypeOf((*MockICore)(nil).FavoriteFilmsAdd), userId, filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FavoriteFilmsAdd", reflect.TypeOf((*MockICore)(nil).FavoriteFilmsAdd), ctx, userId, filmId)
 }
 
 // FavoriteFilmsRemove mocks base method.
-func (m *MockICore) FavoriteFilmsRemove(userId, filmId uint64) error {
+func (m *MockICore) FavoriteFilmsRemove(ctx context.Context, userId, filmId uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FavoriteFilmsRemove", userId, filmId)
+	ret := m.ctrl.Call(m, "FavoriteFilmsRemove", ctx, userId, filmId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // FavoriteFilmsRemove indicates an expected call of FavoriteFilmsRemove.
-func (mr *MockICoreMockRecorder) FavoriteFilmsRemove(userId, filmId interface{}) *gomock.Call {
+func (mr *MockICoreMockRecorder) FavoriteFilmsRemove(ctx, userId, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FavoriteFilmsRemove", reflect.TypeOf((*MockICore)(nil).FavoriteFilmsRemove), userId, filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FavoriteFilmsRemove", reflect.TypeOf((*MockICore)(nil).FavoriteFilmsRemove), ctx, userId, filmId)
 }
 
 // FindActor mocks base method.
-func (m *MockICore) FindActor(name, birthDate string, films, career []string, country string) ([]models.Character, error) {
+func (m *MockICore) FindActor(ctx context.Context, name, birthDate string, films, career []string, country string) ([]models.Character, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindActor", name, birthDate, films, career, country)
+	ret := m.ctrl.Call(m, "FindActor", ctx, name, birthDate, films, career, country)
 	ret0, _ := ret[0].([]models.Character)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindActor indicates an expected call of FindActor.
-func (mr *MockICoreMockRecorder) FindActor(name, birthDate, films, career, country interface{}) *gomock.Call {
+func (mr *MockICoreMockRecorder) FindActor(ctx, name, birthDate, films, career, country interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActor", reflect.TypeOf((*MockICore)(nil).FindActor), name, birthDate, films, career, country)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActor", reflect.TypeOf((*MockICore)(nil).FindActor), ctx, name, birthDate, films, career, country)
 }
 
 // FindFilm mocks base method.
-func (m *MockICore) FindFilm(title, dateFrom, dateTo string, ratingFrom, ratingTo float32, mpaa string, genres []uint32, actors []string) ([]models.FilmItem, error) {
+func (m *MockICore) FindFilm(ctx context.Context, title, dateFrom, dateTo string, ratingFrom, ratingTo float32, mpaa string, genres []uint32, actors []string) ([]models.FilmItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindFilm", title, dateFrom, dateTo, ratingFrom, ratingTo, mpaa, genres, actors)
+	ret := m.ctrl.Call(m, "FindFilm", ctx, title, dateFrom, dateTo, ratingFrom, ratingTo, mpaa, genres, actors)
 	ret0, _ := ret[0].([]models.FilmItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindFilm indicates an expected call of FindFilm.
-func (mr *MockICoreMockRecorder) FindFilm(title, dateFrom, dateTo, ratingFrom, ratingTo, mpaa, genres, actors interface{}) *gomock.Call {
+func (mr *MockICoreMockRecorder) FindFilm(ctx, title, dateFrom, dateTo, ratingFrom, ratingTo, mpaa, genres, actors interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFilm", reflect.TypeOf((*MockICore)(nil).FindFilm), title, dateFrom, dateTo, ratingFrom, ratingTo, mpaa, genres, actors)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFilm", reflect.TypeOf((*MockICore)(nil).FindFilm), ctx, title, dateFrom, dateTo, ratingFrom, ratingTo, mpaa, genres, actors)
 }
 
 // GetActorInfo mocks base method.
-func (m *MockICore) GetActorInfo(actorId uint64) (*requests.ActorResponse, error) {
+func (m *MockICore) GetActorInfo(ctx context.Context, actorId uint64) (*requests.ActorResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActorInfo", actorId)
+	ret := m.ctrl.Call(m, "GetActorInfo", ctx, actorId)
 	ret0, _ := ret[0].(*requests.ActorResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetActorInfo indicates an expected call of GetActorInfo.
-func (mr *MockICoreMockRecorder) GetActorInfo(actorId interface{}) *gomock.Call {
+func (mr *MockICoreMockRecorder) GetActorInfo(ctx, actorId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorInfo", reflect.TypeOf((*MockICore)(nil).GetActorInfo), actorId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorInfo", reflect.TypeOf((*MockICore)(nil).GetActorInfo), ctx, actorId)
 }
 
 // GetActorsCareer mocks base method.
-func (m *MockICore) GetActorsCareer(actorId uint64) ([]models.ProfessionItem, error) {
+func (m *MockICore) GetActorsCareer(ctx context.Context, actorId uint64) ([]models.ProfessionItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActorsCareer", actorId)
+	ret := m.ctrl.Call(m, "GetActorsCareer", ctx, actorId)
 	ret0, _ := ret[0].([]models.ProfessionItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetActorsCareer indicates an expected call of GetActorsCareer.
-func (mr *MockICoreMockRecorder) GetActorsCareer(actorId interface{}) *gomock.Call {
+func (mr *MockICoreMockRecorder) GetActorsCareer(ctx, actorId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorsCareer", reflect.TypeOf((*MockICore)(nil).GetActorsCareer), actorId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorsCareer", reflect.TypeOf((*MockICore)(nil).GetActorsCareer), ctx, actorId)
 }
 
 // GetCalendar mocks base method.
-func (m *MockICore) GetCalendar() (*requests.CalendarResponse, error) {
+func (m *MockICore) GetCalendar(ctx context.Context) (*requests.CalendarResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCalendar")
+	ret := m.ctrl.Call(m, "GetCalendar", ctx)
 	ret0, _ := ret[0].(*requests.CalendarResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCalendar indicates an expected call of GetCalendar.
-func (mr *MockICoreMockRecorder) GetCalendar() *gomock.Call {
+func (mr *MockICoreMockRecorder) GetCalendar(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendar", reflect.TypeOf((*MockICore)(nil).GetCalendar))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendar", reflect.TypeOf((*MockICore)(nil).GetCalendar), ctx)
 }
 
 // GetFilmInfo mocks base method.
-func (m *MockICore) GetFilmInfo(filmId uint64) (*requests.FilmResponse, error) {
+func (m *MockICore) GetFilmInfo(ctx context.Context, filmId uint64) (*requests.FilmResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilmInfo", filmId)
+	ret := m.ctrl.Call(m, "GetFilmInfo", ctx, filmId)
 	ret0, _ := ret[0].(*requests.FilmResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFilmInfo indicates an expected call of GetFilmInfo.
-func (mr *MockICoreMockRecorder) GetFilmInfo(filmId interface{}) *gomock.Call {
+func (mr *MockICoreMockRecorder) GetFilmInfo(ctx, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmInfo", reflect.TypeOf((*MockICore)(nil).GetFilmInfo), filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmInfo", reflect.TypeOf((*MockICore)(nil).GetFilmInfo), ctx, filmId)
 }
 
 // GetFilmsAndGenreTitle mocks base method.
-func (m *MockICore) GetFilmsAndGenreTitle(genreId, start, end uint64) ([]models.FilmItem, string, error) {
+func (m *MockICore) GetFilmsAndGenreTitle(ctx context.Context, genreId, start, end uint64) ([]models.FilmItem, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilmsAndGenreTitle", genreId, start, end)
+	ret := m.ctrl.Call(m, "GetFilmsAndGenreTitle", ctx, genreId, start, end)
 	ret0, _ := ret[0].([]models.FilmItem)
 	ret1, _ := ret[1].(string)
 	ret2, _ := ret[2].(error)
@@ -255,24 +256,24 @@ func (m *MockICore) GetFilmsAndGenreTitle(genreId, start, end uint64) ([]models.
 }
 
 // GetFilmsAndGenreTitle indicates an expected call of GetFilmsAndGenreTitle.
-func (mr *MockICoreMockRecorder) GetFilmsAndGenreTitle(genreId, start, end interface{}) *gomock.Call {
+func (mr *MockICoreMockRecorder) GetFilmsAndGenreTitle(ctx, genreId, start, end interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmsAndGenreTitle", reflect.TypeOf((*MockICore)(nil).GetFilmsAndGenreTitle), genreId, start, end)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmsAndGenreTitle", reflect.TypeOf((*MockICore)(nil).GetFilmsAndGenreTitle), ctx, genreId, start, end)
 }
 
 // GetGenre mocks base method.
-func (m *MockICore) GetGenre(genreId uint64) (string, error) {
+func (m *MockICore) GetGenre(ctx context.Context, genreId uint64) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetGenre", genreId)
+	ret := m.ctrl.Call(m, "GetGenre", ctx, genreId)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetGenre indicates an expected call of GetGenre.
-func (mr *MockICoreMockRecorder) GetGenre(genreId interface{}) *gomock.Call {
+func (mr *MockICoreMockRecorder) GetGenre(ctx, genreId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenre", reflect.TypeOf((*MockICore)(nil).GetGenre), genreId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenre", reflect.TypeOf((*MockICore)(nil).GetGenre), ctx, genreId)
 }
 
 // GetUserId mocks base method.
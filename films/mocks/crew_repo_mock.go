@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	models "github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
@@ -35,148 +36,162 @@ func (m *MockICrewRepo) EXPECT() *MockICrewRepoMockRecorder {
 }
 
 // AddFavoriteActor mocks base method.
-func (m *MockICrewRepo) AddFavoriteActor(userId, actorId uint64) error {
+func (m *MockICrewRepo) AddFavoriteActor(ctx context.Context, userId, actorId uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddFavoriteActor", userId, actorId)
+	ret := m.ctrl.Call(m, "AddFavoriteActor", ctx, userId, actorId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddFavoriteActor indicates an expected call of AddFavoriteActor.
-func (mr *MockICrewRepoMockRecorder) AddFavoriteActor(userId, actorId interface{}) *gomock.Call {
+func (mr *MockICrewRepoMockRecorder) AddFavoriteActor(ctx, userId, actorId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFavoriteActor", reflect.TypeOf((*MockICrewRepo)(nil).AddFavoriteActor), userId, actorId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFavoriteActor", reflect.TypeOf((*MockICrewRepo)(nil).AddFavoriteActor), ctx, userId, actorId)
 }
 
 // AddFilm mocks base method.
-func (m *MockICrewRepo) AddFilm(actors []uint64, filmId uint64) error {
+func (m *MockICrewRepo) AddFilm(ctx context.Context, actors []uint64, filmId uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddFilm", actors, filmId)
+	ret := m.ctrl.Call(m, "AddFilm", ctx, actors, filmId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddFilm indicates an expected call of AddFilm.
-func (mr *MockICrewRepoMockRecorder) AddFilm(actors, filmId interface{}) *gomock.Call {
+func (mr *MockICrewRepoMockRecorder) AddFilm(ctx, actors, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFilm", reflect.TypeOf((*MockICrewRepo)(nil).AddFilm), actors, filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFilm", reflect.TypeOf((*MockICrewRepo)(nil).AddFilm), ctx, actors, filmId)
 }
 
 // CheckActor mocks base method.
-func (m *MockICrewRepo) CheckActor(userId, actorId uint64) (bool, error) {
+func (m *MockICrewRepo) CheckActor(ctx context.Context, userId, actorId uint64) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckActor", userId, actorId)
+	ret := m.ctrl.Call(m, "CheckActor", ctx, userId, actorId)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CheckActor indicates an expected call of CheckActor.
-func (mr *MockICrewRepoMockRecorder) CheckActor(userId, actorId interface{}) *gomock.Call {
+func (mr *MockICrewRepoMockRecorder) CheckActor(ctx, userId, actorId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckActor", reflect.TypeOf((*MockICrewRepo)(nil).CheckActor), userId, actorId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckActor", reflect.TypeOf((*MockICrewRepo)(nil).CheckActor), ctx, userId, actorId)
+}
+
+// Close mocks base method.
+func (m *MockICrewRepo) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockICrewRepoMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockICrewRepo)(nil).Close))
 }
 
 // FindActor mocks base method.
-func (m *MockICrewRepo) FindActor(name, birthDate string, films, career []string, country string) ([]models.Character, error) {
+func (m *MockICrewRepo) FindActor(ctx context.Context, name, birthDate string, films, career []string, country string) ([]models.Character, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindActor", name, birthDate, films, career, country)
+	ret := m.ctrl.Call(m, "FindActor", ctx, name, birthDate, films, career, country)
 	ret0, _ := ret[0].([]models.Character)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindActor indicates an expected call of FindActor.
-func (mr *MockICrewRepoMockRecorder) FindActor(name, birthDate, films, career, country interface{}) *gomock.Call {
+func (mr *MockICrewRepoMockRecorder) FindActor(ctx, name, birthDate, films, career, country interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActor", reflect.TypeOf((*MockICrewRepo)(nil).FindActor), name, birthDate, films, career, country)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActor", reflect.TypeOf((*MockICrewRepo)(nil).FindActor), ctx, name, birthDate, films, career, country)
 }
 
 // GetActor mocks base method.
-func (m *MockICrewRepo) GetActor(actorId uint64) (*models.CrewItem, error) {
+func (m *MockICrewRepo) GetActor(ctx context.Context, actorId uint64) (*models.CrewItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActor", actorId)
+	ret := m.ctrl.Call(m, "GetActor", ctx, actorId)
 	ret0, _ := ret[0].(*models.CrewItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetActor indicates an expected call of GetActor.
-func (mr *MockICrewRepoMockRecorder) GetActor(actorId interface{}) *gomock.Call {
+func (mr *MockICrewRepoMockRecorder) GetActor(ctx, actorId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActor", reflect.TypeOf((*MockICrewRepo)(nil).GetActor), actorId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActor", reflect.TypeOf((*MockICrewRepo)(nil).GetActor), ctx, actorId)
 }
 
 // GetFavoriteActors mocks base method.
-func (m *MockICrewRepo) GetFavoriteActors(userId, start, end uint64) ([]models.Character, error) {
+func (m *MockICrewRepo) GetFavoriteActors(ctx context.Context, userId, start, end uint64) ([]models.Character, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFavoriteActors", userId, start, end)
+	ret := m.ctrl.Call(m, "GetFavoriteActors", ctx, userId, start, end)
 	ret0, _ := ret[0].([]models.Character)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFavoriteActors indicates an expected call of GetFavoriteActors.
-func (mr *MockICrewRepoMockRecorder) GetFavoriteActors(userId, start, end interface{}) *gomock.Call {
+func (mr *MockICrewRepoMockRecorder) GetFavoriteActors(ctx, userId, start, end interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFavoriteActors", reflect.TypeOf((*MockICrewRepo)(nil).GetFavoriteActors), userId, start, end)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFavoriteActors", reflect.TypeOf((*MockICrewRepo)(nil).GetFavoriteActors), ctx, userId, start, end)
 }
 
 // GetFilmCharacters mocks base method.
-func (m *MockICrewRepo) GetFilmCharacters(filmId uint64) ([]models.Character, error) {
+func (m *MockICrewRepo) GetFilmCharacters(ctx context.Context, filmId uint64) ([]models.Character, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilmCharacters", filmId)
+	ret := m.ctrl.Call(m, "GetFilmCharacters", ctx, filmId)
 	ret0, _ := ret[0].([]models.Character)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFilmCharacters indicates an expected call of GetFilmCharacters.
-func (mr *MockICrewRepoMockRecorder) GetFilmCharacters(filmId interface{}) *gomock.Call {
+func (mr *MockICrewRepoMockRecorder) GetFilmCharacters(ctx, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmCharacters", reflect.TypeOf((*MockICrewRepo)(nil).GetFilmCharacters), filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmCharacters", reflect.TypeOf((*MockICrewRepo)(nil).GetFilmCharacters), ctx, filmId)
 }
 
 // GetFilmDirectors mocks base method.
-func (m *MockICrewRepo) GetFilmDirectors(filmId uint64) ([]models.CrewItem, error) {
+func (m *MockICrewRepo) GetFilmDirectors(ctx context.Context, filmId uint64) ([]models.CrewItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilmDirectors", filmId)
+	ret := m.ctrl.Call(m, "GetFilmDirectors", ctx, filmId)
 	ret0, _ := ret[0].([]models.CrewItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFilmDirectors indicates an expected call of GetFilmDirectors.
-func (mr *MockICrewRepoMockRecorder) GetFilmDirectors(filmId interface{}) *gomock.Call {
+func (mr *MockICrewRepoMockRecorder) GetFilmDirectors(ctx, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmDirectors", reflect.TypeOf((*MockICrewRepo)(nil).GetFilmDirectors), filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmDirectors", reflect.TypeOf((*MockICrewRepo)(nil).GetFilmDirectors), ctx, filmId)
 }
 
 // GetFilmScenarists mocks base method.
-func (m *MockICrewRepo) GetFilmScenarists(filmId uint64) ([]models.CrewItem, error) {
+func (m *MockICrewRepo) GetFilmScenarists(ctx context.Context, filmId uint64) ([]models.CrewItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilmScenarists", filmId)
+	ret := m.ctrl.Call(m, "GetFilmScenarists", ctx, filmId)
 	ret0, _ := ret[0].([]models.CrewItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFilmScenarists indicates an expected call of GetFilmScenarists.
-func (mr *MockICrewRepoMockRecorder) GetFilmScenarists(filmId interface{}) *gomock.Call {
+func (mr *MockICrewRepoMockRecorder) GetFilmScenarists(ctx, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmScenarists", reflect.TypeOf((*MockICrewRepo)(nil).GetFilmScenarists), filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmScenarists", reflect.TypeOf((*MockICrewRepo)(nil).GetFilmScenarists), ctx, filmId)
 }
 
 // RemoveFavoriteActor mocks base method.
-func (m *MockICrewRepo) RemoveFavoriteActor(userId, actorId uint64) error {
+func (m *MockICrewRepo) RemoveFavoriteActor(ctx context.Context, userId, actorId uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveFavoriteActor", userId, actorId)
+	ret := m.ctrl.Call(m, "RemoveFavoriteActor", ctx, userId, actorId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveFavoriteActor indicates an expected call of RemoveFavoriteActor.
-func (mr *MockICrewRepoMockRecorder) RemoveFavoriteActor(userId, actorId interface{}) *gomock.Call {
+func (mr *MockICrewRepoMockRecorder) RemoveFavoriteActor(ctx, userId, actorId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFavoriteActor", reflect.TypeOf((*MockICrewRepo)(nil).RemoveFavoriteActor), userId, actorId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFavoriteActor", reflect.TypeOf((*MockICrewRepo)(nil).RemoveFavoriteActor), ctx, userId, actorId)
 }
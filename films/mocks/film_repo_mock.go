@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	models "github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
@@ -35,126 +36,140 @@ func (m *MockIFilmsRepo) EXPECT() *MockIFilmsRepoMockRecorder {
 }
 
 // AddFavoriteFilm mocks base method.
-func (m *MockIFilmsRepo) AddFavoriteFilm(userId, filmId uint64) error {
+func (m *MockIFilmsRepo) AddFavoriteFilm(ctx context.Context, userId, filmId uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddFavoriteFilm", userId, filmId)
+	ret := m.ctrl.Call(m, "AddFavoriteFilm", ctx, userId, filmId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddFavoriteFilm indicates an expected call of AddFavoriteFilm.
-func (mr *MockIFilmsRepoMockRecorder) AddFavoriteFilm(userId, filmId interface{}) *gomock.Call {
+func (mr *MockIFilmsRepoMockRecorder) AddFavoriteFilm(ctx, userId, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFavoriteFilm", reflect.TypeOf((*MockIFilmsRepo)(nil).AddFavoriteFilm), userId, filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFavoriteFilm", reflect.TypeOf((*MockIFilmsRepo)(nil).AddFavoriteFilm), ctx, userId, filmId)
 }
 
 // AddFilm mocks base method.
-func (m *MockIFilmsRepo) AddFilm(film models.FilmItem) error {
+func (m *MockIFilmsRepo) AddFilm(ctx context.Context, film models.FilmItem) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddFilm", film)
+	ret := m.ctrl.Call(m, "AddFilm", ctx, film)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddFilm indicates an expected call of AddFilm.
-func (mr *MockIFilmsRepoMockRecorder) AddFilm(film interface{}) *gomock.Call {
+func (mr *MockIFilmsRepoMockRecorder) AddFilm(ctx, film interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFilm", reflect.TypeOf((*MockIFilmsRepo)(nil).AddFilm), film)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFilm", reflect.TypeOf((*MockIFilmsRepo)(nil).AddFilm), ctx, film)
 }
 
 // AddRating mocks base method.
-func (m *MockIFilmsRepo) AddRating(filmId, userId uint64, rating uint16) error {
+func (m *MockIFilmsRepo) AddRating(ctx context.Context, filmId, userId uint64, rating uint16) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddRating", filmId, userId, rating)
+	ret := m.ctrl.Call(m, "AddRating", ctx, filmId, userId, rating)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddRating indicates an expected call of AddRating.
-func (mr *MockIFilmsRepoMockRecorder) AddRating(filmId, userId, rating interface{}) *gomock.Call {
+func (mr *MockIFilmsRepoMockRecorder) AddRating(ctx, filmId, userId, rating interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRating", reflect.TypeOf((*MockIFilmsRepo)(nil).AddRating), filmId, userId, rating)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRating", reflect.TypeOf((*MockIFilmsRepo)(nil).AddRating), ctx, filmId, userId, rating)
 }
 
 // CheckFilm mocks base method.
-func (m *MockIFilmsRepo) CheckFilm(userId, filmId uint64) (bool, error) {
+func (m *MockIFilmsRepo) CheckFilm(ctx context.Context, userId, filmId uint64) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckFilm", userId, filmId)
+	ret := m.ctrl.Call(m, "CheckFilm", ctx, userId, filmId)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CheckFilm indicates an expected call of CheckFilm.
-func (mr *MockIFilmsRepoMockRecorder) CheckFilm(userId, filmId interface{}) *gomock.Call {
+func (mr *MockIFilmsRepoMockRecorder) CheckFilm(ctx, userId, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckFilm", reflect.TypeOf((*MockIFilmsRepo)(nil).CheckFilm), userId, filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckFilm", reflect.TypeOf((*MockIFilmsRepo)(nil).CheckFilm), ctx, userId, filmId)
+}
+
+// Close mocks base method.
+func (m *MockIFilmsRepo) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockIFilmsRepoMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIFilmsRepo)(nil).Close))
 }
 
 // FindFilm mocks base method.
-func (m *MockIFilmsRepo) FindFilm(title, dateFrom, dateTo string, ratingFrom, ratingTo float32, mpaa string, genres []uint32, actors []string) ([]models.FilmItem, error) {
+func (m *MockIFilmsRepo) FindFilm(ctx context.Context, title, dateFrom, dateTo string, ratingFrom, ratingTo float32, mpaa string, genres []uint32, actors []string) ([]models.FilmItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindFilm", title, dateFrom, dateTo, ratingFrom, ratingTo, mpaa, genres, actors)
+	ret := m.ctrl.Call(m, "FindFilm", ctx, title, dateFrom, dateTo, ratingFrom, ratingTo, mpaa, genres, actors)
 	ret0, _ := ret[0].([]models.FilmItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindFilm indicates an expected call of FindFilm.
-func (mr *MockIFilmsRepoMockRecorder) FindFilm(title, dateFrom, dateTo, ratingFrom, ratingTo, mpaa, genres, actors interface{}) *gomock.Call {
+func (mr *MockIFilmsRepoMockRecorder) FindFilm(ctx, title, dateFrom, dateTo, ratingFrom, ratingTo, mpaa, genres, actors interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFilm", reflect.TypeOf((*MockIFilmsRepo)(nil).FindFilm), title, dateFrom, dateTo, ratingFrom, ratingTo, mpaa, genres, actors)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindFilm", reflect.TypeOf((*MockIFilmsRepo)(nil).FindFilm), ctx, title, dateFrom, dateTo, ratingFrom, ratingTo, mpaa, genres, actors)
 }
 
 // GetFavoriteFilms mocks base method.
-func (m *MockIFilmsRepo) GetFavoriteFilms(userId, start, end uint64) ([]models.FilmItem, error) {
+func (m *MockIFilmsRepo) GetFavoriteFilms(ctx context.Context, userId, start, end uint64) ([]models.FilmItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFavoriteFilms", userId, start, end)
+	ret := m.ctrl.Call(m, "GetFavoriteFilms", ctx, userId, start, end)
 	ret0, _ := ret[0].([]models.FilmItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFavoriteFilms indicates an expected call of GetFavoriteFilms.
-func (mr *MockIFilmsRepoMockRecorder) GetFavoriteFilms(userId, start, end interface{}) *gomock.Call {
+func (mr *MockIFilmsRepoMockRecorder) GetFavoriteFilms(ctx, userId, start, end interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFavoriteFilms", reflect.TypeOf((*MockIFilmsRepo)(nil).GetFavoriteFilms), userId, start, end)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFavoriteFilms", reflect.TypeOf((*MockIFilmsRepo)(nil).GetFavoriteFilms), ctx, userId, start, end)
 }
 
 // GetFilm mocks base method.
-func (m *MockIFilmsRepo) GetFilm(filmId uint64) (*models.FilmItem, error) {
+func (m *MockIFilmsRepo) GetFilm(ctx context.Context, filmId uint64) (*models.FilmItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilm", filmId)
+	ret := m.ctrl.Call(m, "GetFilm", ctx, filmId)
 	ret0, _ := ret[0].(*models.FilmItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFilm indicates an expected call of GetFilm.
-func (mr *MockIFilmsRepoMockRecorder) GetFilm(filmId interface{}) *gomock.Call {
+func (mr *MockIFilmsRepoMockRecorder) GetFilm(ctx, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilm", reflect.TypeOf((*MockIFilmsRepo)(nil).GetFilm), filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilm", reflect.TypeOf((*MockIFilmsRepo)(nil).GetFilm), ctx, filmId)
 }
 
 // GetFilmId mocks base method.
-func (m *MockIFilmsRepo) GetFilmId(title string) (uint64, error) {
+func (m *MockIFilmsRepo) GetFilmId(ctx context.Context, title string) (uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilmId", title)
+	ret := m.ctrl.Call(m, "GetFilmId", ctx, title)
 	ret0, _ := ret[0].(uint64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFilmId indicates an expected call of GetFilmId.
-func (mr *MockIFilmsRepoMockRecorder) GetFilmId(title interface{}) *gomock.Call {
+func (mr *MockIFilmsRepoMockRecorder) GetFilmId(ctx, title interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmId", reflect.TypeOf((*MockIFilmsRepo)(nil).GetFilmId), title)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmId", reflect.TypeOf((*MockIFilmsRepo)(nil).GetFilmId), ctx, title)
 }
 
 // GetFilmRating mocks base method.
-func (m *MockIFilmsRepo) GetFilmRating(filmId uint64) (float64, uint64, error) {
+func (m *MockIFilmsRepo) GetFilmRating(ctx context.Context, filmId uint64) (float64, uint64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilmRating", filmId)
+	ret := m.ctrl.Call(m, "GetFilmRating", ctx, filmId)
 	ret0, _ := ret[0].(float64)
 	ret1, _ := ret[1].(uint64)
 	ret2, _ := ret[2].(error)
@@ -162,66 +177,66 @@ func (m *MockIFilmsRepo) GetFilmRating(filmId uint64) (float64, uint64, error) {
 }
 
 // GetFilmRating indicates an expected call of GetFilmRating.
-func (mr *MockIFilmsRepoMockRecorder) GetFilmRating(filmId interface{}) *gomock.Call {
+func (mr *MockIFilmsRepoMockRecorder) GetFilmRating(ctx, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmRating", reflect.TypeOf((*MockIFilmsRepo)(nil).GetFilmRating), filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmRating", reflect.TypeOf((*MockIFilmsRepo)(nil).GetFilmRating), ctx, filmId)
 }
 
 // GetFilms mocks base method.
-func (m *MockIFilmsRepo) GetFilms(start, end uint64) ([]models.FilmItem, error) {
+func (m *MockIFilmsRepo) GetFilms(ctx context.Context, start, end uint64) ([]models.FilmItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilms", start, end)
+	ret := m.ctrl.Call(m, "GetFilms", ctx, start, end)
 	ret0, _ := ret[0].([]models.FilmItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFilms indicates an expected call of GetFilms.
-func (mr *MockIFilmsRepoMockRecorder) GetFilms(start, end interface{}) *gomock.Call {
+func (mr *MockIFilmsRepoMockRecorder) GetFilms(ctx, start, end interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilms", reflect.TypeOf((*MockIFilmsRepo)(nil).GetFilms), start, end)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilms", reflect.TypeOf((*MockIFilmsRepo)(nil).GetFilms), ctx, start, end)
 }
 
 // GetFilmsByGenre mocks base method.
-func (m *MockIFilmsRepo) GetFilmsByGenre(genre, start, end uint64) ([]models.FilmItem, error) {
+func (m *MockIFilmsRepo) GetFilmsByGenre(ctx context.Context, genre, start, end uint64) ([]models.FilmItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilmsByGenre", genre, start, end)
+	ret := m.ctrl.Call(m, "GetFilmsByGenre", ctx, genre, start, end)
 	ret0, _ := ret[0].([]models.FilmItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFilmsByGenre indicates an expected call of GetFilmsByGenre.
-func (mr *MockIFilmsRepoMockRecorder) GetFilmsByGenre(genre, start, end interface{}) *gomock.Call {
+func (mr *MockIFilmsRepoMockRecorder) GetFilmsByGenre(ctx, genre, start, end interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmsByGenre", reflect.TypeOf((*MockIFilmsRepo)(nil).GetFilmsByGenre), genre, start, end)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmsByGenre", reflect.TypeOf((*MockIFilmsRepo)(nil).GetFilmsByGenre), ctx, genre, start, end)
 }
 
 // HasUsersRating mocks base method.
-func (m *MockIFilmsRepo) HasUsersRating(userId, filmId uint64) (bool, error) {
+func (m *MockIFilmsRepo) HasUsersRating(ctx context.Context, userId, filmId uint64) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "HasUsersRating", userId, filmId)
+	ret := m.ctrl.Call(m, "HasUsersRating", ctx, userId, filmId)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // HasUsersRating indicates an expected call of HasUsersRating.
-func (mr *MockIFilmsRepoMockRecorder) HasUsersRating(userId, filmId interface{}) *gomock.Call {
+func (mr *MockIFilmsRepoMockRecorder) HasUsersRating(ctx, userId, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasUsersRating", reflect.TypeOf((*MockIFilmsRepo)(nil).HasUsersRating), userId, filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasUsersRating", reflect.TypeOf((*MockIFilmsRepo)(nil).HasUsersRating), ctx, userId, filmId)
 }
 
 // RemoveFavoriteFilm mocks base method.
-func (m *MockIFilmsRepo) RemoveFavoriteFilm(userId, filmId uint64) error {
+func (m *MockIFilmsRepo) RemoveFavoriteFilm(ctx context.Context, userId, filmId uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveFavoriteFilm", userId, filmId)
+	ret := m.ctrl.Call(m, "RemoveFavoriteFilm", ctx, userId, filmId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveFavoriteFilm indicates an expected call of RemoveFavoriteFilm.
-func (mr *MockIFilmsRepoMockRecorder) RemoveFavoriteFilm(userId, filmId interface{}) *gomock.Call {
+func (mr *MockIFilmsRepoMockRecorder) RemoveFavoriteFilm(ctx, userId, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFavoriteFilm", reflect.TypeOf((*MockIFilmsRepo)(nil).RemoveFavoriteFilm), userId, filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFavoriteFilm", reflect.TypeOf((*MockIFilmsRepo)(nil).RemoveFavoriteFilm), ctx, userId, filmId)
 }
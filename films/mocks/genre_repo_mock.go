@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	models "github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
@@ -35,45 +36,59 @@ func (m *MockIGenreRepo) EXPECT() *MockIGenreRepoMockRecorder {
 }
 
 // AddFilm mocks base method.
-func (m *MockIGenreRepo) AddFilm(genres []uint64, filmId uint64) error {
+func (m *MockIGenreRepo) AddFilm(ctx context.Context, genres []uint64, filmId uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddFilm", genres, filmId)
+	ret := m.ctrl.Call(m, "AddFilm", ctx, genres, filmId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddFilm indicates an expected call of AddFilm.
-func (mr *MockIGenreRepoMockRecorder) AddFilm(genres, filmId interface{}) *gomock.Call {
+func (mr *MockIGenreRepoMockRecorder) AddFilm(ctx, genres, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFilm", reflect.TypeOf((*MockIGenreRepo)(nil).AddFilm), genres, filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFilm", reflect.TypeOf((*MockIGenreRepo)(nil).AddFilm), ctx, genres, filmId)
+}
+
+// Close mocks base method.
+func (m *MockIGenreRepo) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockIGenreRepoMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIGenreRepo)(nil).Close))
 }
 
 // GetFilmGenres mocks base method.
-func (m *MockIGenreRepo) GetFilmGenres(filmId uint64) ([]models.GenreItem, error) {
+func (m *MockIGenreRepo) GetFilmGenres(ctx context.Context, filmId uint64) ([]models.GenreItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilmGenres", filmId)
+	ret := m.ctrl.Call(m, "GetFilmGenres", ctx, filmId)
 	ret0, _ := ret[0].([]models.GenreItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFilmGenres indicates an expected call of GetFilmGenres.
-func (mr *MockIGenreRepoMockRecorder) GetFilmGenres(filmId interface{}) *gomock.Call {
+func (mr *MockIGenreRepoMockRecorder) GetFilmGenres(ctx, filmId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmGenres", reflect.TypeOf((*MockIGenreRepo)(nil).GetFilmGenres), filmId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmGenres", reflect.TypeOf((*MockIGenreRepo)(nil).GetFilmGenres), ctx, filmId)
 }
 
 // GetGenreById mocks base method.
-func (m *MockIGenreRepo) GetGenreById(genreId uint64) (string, error) {
+func (m *MockIGenreRepo) GetGenreById(ctx context.Context, genreId uint64) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetGenreById", genreId)
+	ret := m.ctrl.Call(m, "GetGenreById", ctx, genreId)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetGenreById indicates an expected call of GetGenreById.
-func (mr *MockIGenreRepoMockRecorder) GetGenreById(genreId interface{}) *gomock.Call {
+func (mr *MockIGenreRepoMockRecorder) GetGenreById(ctx, genreId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenreById", reflect.TypeOf((*MockIGenreRepo)(nil).GetGenreById), genreId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenreById", reflect.TypeOf((*MockIGenreRepo)(nil).GetGenreById), ctx, genreId)
 }
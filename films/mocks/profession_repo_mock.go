@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	models "github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
@@ -34,17 +35,31 @@ func (m *MockIProfessionRepo) EXPECT() *MockIProfessionRepoMockRecorder {
 	return m.recorder
 }
 
+// Close mocks base method.
+func (m *MockIProfessionRepo) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockIProfessionRepoMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIProfessionRepo)(nil).Close))
+}
+
 // GetActorsProfessions mocks base method.
-func (m *MockIProfessionRepo) GetActorsProfessions(actorId uint64) ([]models.ProfessionItem, error) {
+func (m *MockIProfessionRepo) GetActorsProfessions(ctx context.Context, actorId uint64) ([]models.ProfessionItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActorsProfessions", actorId)
+	ret := m.ctrl.Call(m, "GetActorsProfessions", ctx, actorId)
 	ret0, _ := ret[0].([]models.ProfessionItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetActorsProfessions indicates an expected call of GetActorsProfessions.
-func (mr *MockIProfessionRepoMockRecorder) GetActorsProfessions(actorId interface{}) *gomock.Call {
+func (mr *MockIProfessionRepoMockRecorder) GetActorsProfessions(ctx, actorId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorsProfessions", reflect.TypeOf((*MockIProfessionRepo)(nil).GetActorsProfessions), actorId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActorsProfessions", reflect.TypeOf((*MockIProfessionRepo)(nil).GetActorsProfessions), ctx, actorId)
 }
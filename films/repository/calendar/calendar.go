@@ -1,13 +1,14 @@
 package calendar
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log/slog"
-	"time"
 
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
 
 	_ "github.com/jackc/pgx/stdlib"
@@ -16,14 +17,16 @@ import (
 //go:generate mockgen -source=calendar.go -destination=../../mocks/calendar_repo_mock.go -package=mocks
 
 type ICalendarRepo interface {
-	GetCalendar() ([]models.DayItem, error)
+	GetCalendar(ctx context.Context) ([]models.DayItem, error)
+	Close() error
 }
 
 type RepoPostgre struct {
 	db *sql.DB
+	mt *metrics.DBMetrics
 }
 
-func GetCalendarRepo(config *configs.DbDsnCfg, lg *slog.Logger) (*RepoPostgre, error) {
+func GetCalendarRepo(config *configs.DbDsnCfg, lg *slog.Logger, mt *metrics.Metrics) (*RepoPostgre, error) {
 	dsn := fmt.Sprintf("user=%s dbname=%s password= %s host=%s port=%d sslmode=%s",
 		config.User, config.DbName, config.Password, config.Host, config.Port, config.Sslmode)
 	db, err := sql.Open("pgx", dsn)
@@ -31,38 +34,32 @@ func GetCalendarRepo(config *configs.DbDsnCfg, lg *slog.Logger) (*RepoPostgre, e
 		lg.Error("sql open error", "err", err.Error())
 		return nil, fmt.Errorf("get calendar repo: %w", err)
 	}
-	err = db.Ping()
+	db.SetMaxOpenConns(config.MaxOpenConns)
+
+	dbMetrics, err := mt.OpenDB("calendar", db, config.Timer, lg)
 	if err != nil {
 		lg.Error("sql ping error", "err", err.Error())
 		return nil, fmt.Errorf("get calendar repo: %w", err)
 	}
-	db.SetMaxOpenConns(config.MaxOpenConns)
 
-	postgreDb := RepoPostgre{db: db}
-
-	go postgreDb.pingDb(config.Timer, lg)
-	return &postgreDb, nil
+	return &RepoPostgre{db: db, mt: dbMetrics}, nil
 }
 
-func (repo *RepoPostgre) pingDb(timer uint32, lg *slog.Logger) {
-	for {
-		err := repo.db.Ping()
-		if err != nil {
-			lg.Error("Repo Crew db ping error", "err", err.Error())
-		}
-
-		time.Sleep(time.Duration(timer) * time.Second)
-	}
+func (repo *RepoPostgre) Close() error {
+	return repo.mt.Close()
 }
 
-func (repo *RepoPostgre) GetCalendar() ([]models.DayItem, error) {
+func (repo *RepoPostgre) GetCalendar(ctx context.Context) ([]models.DayItem, error) {
+	ctx, done := repo.mt.Query(ctx, "GetCalendar")
+	defer done()
+
 	calendar := []models.DayItem{}
 	lastAppendDay := uint8(0)
 	news := ""
 
-	rows, err := repo.db.Query("SELECT film.title, release_day, film.poster, film.id FROM calendar " +
-		"JOIN film ON film.id = calendar.id " +
-		"WHERE release_month = DATE_PART('MONTH', CURRENT_DATE) " +
+	rows, err := repo.db.QueryContext(ctx, "SELECT film.title, release_day, film.poster, film.id FROM calendar "+
+		"JOIN film ON film.id = calendar.id "+
+		"WHERE release_month = DATE_PART('MONTH', CURRENT_DATE) "+
 		"ORDER BY release_day")
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return nil, err
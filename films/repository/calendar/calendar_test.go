@@ -1,6 +1,7 @@
 package calendar
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -38,7 +39,7 @@ func TestGetCalendar(t *testing.T) {
 		db: db,
 	}
 
-	days, err := repo.GetCalendar()
+	days, err := repo.GetCalendar(context.Background())
 	if err != nil {
 		t.Errorf("get calendar error: %s", err)
 	}
@@ -58,7 +59,7 @@ func TestGetCalendar(t *testing.T) {
 		WithArgs().
 		WillReturnError(fmt.Errorf("db_error"))
 
-	days, err = repo.GetCalendar()
+	days, err = repo.GetCalendar(context.Background())
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
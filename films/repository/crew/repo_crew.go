@@ -1,15 +1,16 @@
 package crew
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log/slog"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
 	"github.com/lib/pq"
 
@@ -19,23 +20,25 @@ import (
 //go:generate mockgen -source=repo_crew.go -destination=../../mocks/crew_repo_mock.go -package=mocks
 
 type ICrewRepo interface {
-	GetFilmDirectors(filmId uint64) ([]models.CrewItem, error)
-	GetFilmScenarists(filmId uint64) ([]models.CrewItem, error)
-	GetFilmCharacters(filmId uint64) ([]models.Character, error)
-	GetActor(actorId uint64) (*models.CrewItem, error)
-	FindActor(name string, birthDate string, films []string, career []string, country string) ([]models.Character, error)
-	GetFavoriteActors(userId uint64, start uint64, end uint64) ([]models.Character, error)
-	CheckActor(userId uint64, actorId uint64) (bool, error)
-	AddFavoriteActor(userId uint64, actorId uint64) error
-	RemoveFavoriteActor(userId uint64, actorId uint64) error
-	AddFilm(actors []uint64, filmId uint64) error
+	GetFilmDirectors(ctx context.Context, filmId uint64) ([]models.CrewItem, error)
+	GetFilmScenarists(ctx context.Context, filmId uint64) ([]models.CrewItem, error)
+	GetFilmCharacters(ctx context.Context, filmId uint64) ([]models.Character, error)
+	GetActor(ctx context.Context, actorId uint64) (*models.CrewItem, error)
+	FindActor(ctx context.Context, name string, birthDate string, films []string, career []string, country string) ([]models.Character, error)
+	GetFavoriteActors(ctx context.Context, userId uint64, start uint64, end uint64) ([]models.Character, error)
+	CheckActor(ctx context.Context, userId uint64, actorId uint64) (bool, error)
+	AddFavoriteActor(ctx context.Context, userId uint64, actorId uint64) error
+	RemoveFavoriteActor(ctx context.Context, userId uint64, actorId uint64) error
+	AddFilm(ctx context.Context, actors []uint64, filmId uint64) error
+	Close() error
 }
 
 type RepoPostgre struct {
 	db *sql.DB
+	mt *metrics.DBMetrics
 }
 
-func GetCrewRepo(config *configs.DbDsnCfg, lg *slog.Logger) (*RepoPostgre, error) {
+func GetCrewRepo(config *configs.DbDsnCfg, lg *slog.Logger, mt *metrics.Metrics) (*RepoPostgre, error) {
 	dsn := fmt.Sprintf("user=%s dbname=%s password= %s host=%s port=%d sslmode=%s",
 		config.User, config.DbName, config.Password, config.Host, config.Port, config.Sslmode)
 	db, err := sql.Open("pgx", dsn)
@@ -43,34 +46,28 @@ func GetCrewRepo(config *configs.DbDsnCfg, lg *slog.Logger) (*RepoPostgre, error
 		lg.Error("sql open error", "err", err.Error())
 		return nil, fmt.Errorf("get crew repo: %w", err)
 	}
-	err = db.Ping()
+	db.SetMaxOpenConns(config.MaxOpenConns)
+
+	dbMetrics, err := mt.OpenDB("crew", db, config.Timer, lg)
 	if err != nil {
 		lg.Error("sql ping error", "err", err.Error())
 		return nil, fmt.Errorf("get crew repo: %w", err)
 	}
-	db.SetMaxOpenConns(config.MaxOpenConns)
 
-	postgreDb := RepoPostgre{db: db}
-
-	go postgreDb.pingDb(config.Timer, lg)
-	return &postgreDb, nil
+	return &RepoPostgre{db: db, mt: dbMetrics}, nil
 }
 
-func (repo *RepoPostgre) pingDb(timer uint32, lg *slog.Logger) {
-	for {
-		err := repo.db.Ping()
-		if err != nil {
-			lg.Error("Repo Crew db ping error", "err", err.Error())
-		}
-
-		time.Sleep(time.Duration(timer) * time.Second)
-	}
+func (repo *RepoPostgre) Close() error {
+	return repo.mt.Close()
 }
 
-func (repo *RepoPostgre) GetFilmDirectors(filmId uint64) ([]models.CrewItem, error) {
+func (repo *RepoPostgre) GetFilmDirectors(ctx context.Context, filmId uint64) ([]models.CrewItem, error) {
+	ctx, done := repo.mt.Query(ctx, "GetFilmDirectors")
+	defer done()
+
 	directors := []models.CrewItem{}
 
-	rows, err := repo.db.Query(
+	rows, err := repo.db.QueryContext(ctx,
 		"SELECT crew.id, name, photo  FROM crew "+
 			"JOIN person_in_film ON crew.id = person_in_film.id_person "+
 			"WHERE id_film = $1 AND id_profession = "+
@@ -92,10 +89,13 @@ func (repo *RepoPostgre) GetFilmDirectors(filmId uint64) ([]models.CrewItem, err
 	return directors, nil
 }
 
-func (repo *RepoPostgre) GetFilmScenarists(filmId uint64) ([]models.CrewItem, error) {
+func (repo *RepoPostgre) GetFilmScenarists(ctx context.Context, filmId uint64) ([]models.CrewItem, error) {
+	ctx, done := repo.mt.Query(ctx, "GetFilmScenarists")
+	defer done()
+
 	scenarists := []models.CrewItem{}
 
-	rows, err := repo.db.Query(
+	rows, err := repo.db.QueryContext(ctx,
 		"SELECT crew.id, name, photo  FROM crew "+
 			"JOIN person_in_film ON crew.id = person_in_film.id_person "+
 			"WHERE id_film = $1 AND id_profession = "+
@@ -117,10 +117,13 @@ func (repo *RepoPostgre) GetFilmScenarists(filmId uint64) ([]models.CrewItem, er
 	return scenarists, nil
 }
 
-func (repo *RepoPostgre) GetFilmCharacters(filmId uint64) ([]models.Character, error) {
+func (repo *RepoPostgre) GetFilmCharacters(ctx context.Context, filmId uint64) ([]models.Character, error) {
+	ctx, done := repo.mt.Query(ctx, "GetFilmCharacters")
+	defer done()
+
 	characters := []models.Character{}
 
-	rows, err := repo.db.Query(
+	rows, err := repo.db.QueryContext(ctx,
 		"SELECT crew.id, name, photo, person_in_film.character_name FROM crew "+
 			"JOIN person_in_film ON crew.id = person_in_film.id_person "+
 			"WHERE id_film = $1 AND id_profession = "+
@@ -142,10 +145,13 @@ func (repo *RepoPostgre) GetFilmCharacters(filmId uint64) ([]models.Character, e
 	return characters, nil
 }
 
-func (repo *RepoPostgre) GetActor(actorId uint64) (*models.CrewItem, error) {
+func (repo *RepoPostgre) GetActor(ctx context.Context, actorId uint64) (*models.CrewItem, error) {
+	ctx, done := repo.mt.Query(ctx, "GetActor")
+	defer done()
+
 	actor := &models.CrewItem{}
 
-	err := repo.db.QueryRow(
+	err := repo.db.QueryRowContext(ctx,
 		"SELECT id, name, birth_date, photo, info FROM crew "+
 			"WHERE id = $1", actorId).
 		Scan(&actor.Id, &actor.Name, &actor.Birthdate, &actor.Photo, &actor.Info)
@@ -159,7 +165,10 @@ func (repo *RepoPostgre) GetActor(actorId uint64) (*models.CrewItem, error) {
 	return actor, nil
 }
 
-func (repo *RepoPostgre) FindActor(name string, birthDate string, films []string, career []string, country string) ([]models.Character, error) {
+func (repo *RepoPostgre) FindActor(ctx context.Context, name string, birthDate string, films []string, career []string, country string) ([]models.Character, error) {
+	ctx, done := repo.mt.Query(ctx, "FindActor")
+	defer done()
+
 	actors := []models.Character{}
 	var hasWhere bool
 	paramNum := 1
@@ -223,7 +232,7 @@ func (repo *RepoPostgre) FindActor(name string, birthDate string, films []string
 		params = append(params, country)
 	}
 
-	rows, err := repo.db.Query(s.String(), params...)
+	rows, err := repo.db.QueryContext(ctx, s.String(), params...)
 	if err != nil {
 		return nil, fmt.Errorf("find actor err: %w", err)
 	}
@@ -241,10 +250,13 @@ func (repo *RepoPostgre) FindActor(name string, birthDate string, films []string
 	return actors, nil
 }
 
-func (repo *RepoPostgre) GetFavoriteActors(userId uint64, start uint64, end uint64) ([]models.Character, error) {
+func (repo *RepoPostgre) GetFavoriteActors(ctx context.Context, userId uint64, start uint64, end uint64) ([]models.Character, error) {
+	ctx, done := repo.mt.Query(ctx, "GetFavoriteActors")
+	defer done()
+
 	actors := []models.Character{}
 
-	rows, err := repo.db.Query(
+	rows, err := repo.db.QueryContext(ctx,
 		"SELECT crew.name, crew.id, crew.photo FROM crew "+
 			"JOIN users_favorite_actor ON crew.id = users_favorite_actor.id_actor "+
 			"WHERE id_user = $1 "+
@@ -266,9 +278,12 @@ func (repo *RepoPostgre) GetFavoriteActors(userId uint64, start uint64, end uint
 	return actors, nil
 }
 
-func (repo *RepoPostgre) CheckActor(userId uint64, actorId uint64) (bool, error) {
+func (repo *RepoPostgre) CheckActor(ctx context.Context, userId uint64, actorId uint64) (bool, error) {
+	ctx, done := repo.mt.Query(ctx, "CheckActor")
+	defer done()
+
 	actor := models.Character{}
-	err := repo.db.QueryRow("SELECT id_actor FROM users_favorite_actor WHERE id_actor = $1 AND id_user = $2", actorId, userId).Scan(&actor.IdActor)
+	err := repo.db.QueryRowContext(ctx, "SELECT id_actor FROM users_favorite_actor WHERE id_actor = $1 AND id_user = $2", actorId, userId).Scan(&actor.IdActor)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return false, nil
@@ -280,8 +295,11 @@ func (repo *RepoPostgre) CheckActor(userId uint64, actorId uint64) (bool, error)
 	return true, nil
 }
 
-func (repo *RepoPostgre) AddFavoriteActor(userId uint64, actorId uint64) error {
-	_, err := repo.db.Exec(
+func (repo *RepoPostgre) AddFavoriteActor(ctx context.Context, userId uint64, actorId uint64) error {
+	ctx, done := repo.mt.Query(ctx, "AddFavoriteActor")
+	defer done()
+
+	_, err := repo.db.ExecContext(ctx,
 		"INSERT INTO users_favorite_actor(id_user, id_actor) VALUES ($1, $2)", userId, actorId)
 	if err != nil {
 		return fmt.Errorf("add favorite actor err: %w", err)
@@ -290,8 +308,11 @@ func (repo *RepoPostgre) AddFavoriteActor(userId uint64, actorId uint64) error {
 	return nil
 }
 
-func (repo *RepoPostgre) RemoveFavoriteActor(userId uint64, actorId uint64) error {
-	_, err := repo.db.Exec(
+func (repo *RepoPostgre) RemoveFavoriteActor(ctx context.Context, userId uint64, actorId uint64) error {
+	ctx, done := repo.mt.Query(ctx, "RemoveFavoriteActor")
+	defer done()
+
+	_, err := repo.db.ExecContext(ctx,
 		"DELETE FROM users_favorite_actor "+
 			"WHERE id_user = $1 AND id_actor = $2", userId, actorId)
 	if err != nil {
@@ -301,7 +322,10 @@ func (repo *RepoPostgre) RemoveFavoriteActor(userId uint64, actorId uint64) erro
 	return nil
 }
 
-func (repo *RepoPostgre) AddFilm(actors []uint64, filmId uint64) error {
+func (repo *RepoPostgre) AddFilm(ctx context.Context, actors []uint64, filmId uint64) error {
+	ctx, done := repo.mt.Query(ctx, "AddFilm")
+	defer done()
+
 	var s strings.Builder
 	var params []interface{}
 	params = append(params, filmId)
@@ -315,7 +339,7 @@ func (repo *RepoPostgre) AddFilm(actors []uint64, filmId uint64) error {
 		params = append(params, actor)
 	}
 
-	_, err := repo.db.Exec(s.String(), params...)
+	_, err := repo.db.ExecContext(ctx, s.String(), params...)
 	if err != nil {
 		return fmt.Errorf("add films actors error: %w", err)
 	}
@@ -1,6 +1,7 @@
 package crew
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -36,7 +37,7 @@ func TestGetFilmDirectors(t *testing.T) {
 		db: db,
 	}
 
-	directors, err := repo.GetFilmDirectors(1)
+	directors, err := repo.GetFilmDirectors(context.Background(), 1)
 	if err != nil {
 		t.Errorf("GetFilm error: %s", err)
 	}
@@ -56,7 +57,7 @@ func TestGetFilmDirectors(t *testing.T) {
 		WithArgs(1).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	directors, err = repo.GetFilmDirectors(1)
+	directors, err = repo.GetFilmDirectors(context.Background(), 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -96,7 +97,7 @@ func TestGetFilmScenarists(t *testing.T) {
 		db: db,
 	}
 
-	scenarists, err := repo.GetFilmScenarists(1)
+	scenarists, err := repo.GetFilmScenarists(context.Background(), 1)
 	if err != nil {
 		t.Errorf("GetFilm error: %s", err)
 	}
@@ -116,7 +117,7 @@ func TestGetFilmScenarists(t *testing.T) {
 		WithArgs(1).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	scenarists, err = repo.GetFilmScenarists(1)
+	scenarists, err = repo.GetFilmScenarists(context.Background(), 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -156,7 +157,7 @@ func TestGetFilmCharacters(t *testing.T) {
 		db: db,
 	}
 
-	characters, err := repo.GetFilmCharacters(1)
+	characters, err := repo.GetFilmCharacters(context.Background(), 1)
 	if err != nil {
 		t.Errorf("GetFilm error: %s", err)
 	}
@@ -176,7 +177,7 @@ func TestGetFilmCharacters(t *testing.T) {
 		WithArgs(1).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	characters, err = repo.GetFilmCharacters(1)
+	characters, err = repo.GetFilmCharacters(context.Background(), 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -216,7 +217,7 @@ func TestGetActor(t *testing.T) {
 		db: db,
 	}
 
-	actor, err := repo.GetActor(1)
+	actor, err := repo.GetActor(context.Background(), 1)
 	if err != nil {
 		t.Errorf("GetFilm error: %s", err)
 	}
@@ -236,7 +237,7 @@ func TestGetActor(t *testing.T) {
 		WithArgs(1).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	actor, err = repo.GetActor(1)
+	actor, err = repo.GetActor(context.Background(), 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -277,7 +278,7 @@ func TestCheckActor(t *testing.T) {
 		db: db,
 	}
 
-	found, err := repo.CheckActor(1, 1)
+	found, err := repo.CheckActor(context.Background(), 1, 1)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err)
 	}
@@ -297,7 +298,7 @@ func TestCheckActor(t *testing.T) {
 		WithArgs(1, 1).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	found, err = repo.CheckActor(1, 1)
+	found, err = repo.CheckActor(context.Background(), 1, 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -339,7 +340,7 @@ func TestGetFavoriteActors(t *testing.T) {
 		db: db,
 	}
 
-	films, err := repo.GetFavoriteActors(1, 1, 2)
+	films, err := repo.GetFavoriteActors(context.Background(), 1, 1, 2)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err)
 	}
@@ -359,7 +360,7 @@ func TestGetFavoriteActors(t *testing.T) {
 		WithArgs(1, 1, 2).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	_, err = repo.GetFavoriteActors(1, 1, 2)
+	_, err = repo.GetFavoriteActors(context.Background(), 1, 1, 2)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -387,7 +388,7 @@ func TestAddFavoriteActor(t *testing.T) {
 		db: db,
 	}
 
-	err = repo.AddFavoriteActor(1, 1)
+	err = repo.AddFavoriteActor(context.Background(), 1, 1)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err)
 	}
@@ -401,7 +402,7 @@ func TestAddFavoriteActor(t *testing.T) {
 		regexp.QuoteMeta(selectRow)).
 		WithArgs(1, 1).WillReturnError(fmt.Errorf("repo err"))
 
-	err = repo.AddFavoriteActor(1, 1)
+	err = repo.AddFavoriteActor(context.Background(), 1, 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -429,7 +430,7 @@ func TestRemoveFavoriteActor(t *testing.T) {
 		db: db,
 	}
 
-	err = repo.RemoveFavoriteActor(1, 1)
+	err = repo.RemoveFavoriteActor(context.Background(), 1, 1)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err)
 	}
@@ -443,7 +444,7 @@ func TestRemoveFavoriteActor(t *testing.T) {
 		regexp.QuoteMeta(selectRow)).
 		WithArgs(1, 1).WillReturnError(fmt.Errorf("repo err"))
 
-	err = repo.RemoveFavoriteActor(1, 1)
+	err = repo.RemoveFavoriteActor(context.Background(), 1, 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -471,7 +472,7 @@ func TestAddFilm(t *testing.T) {
 		db: db,
 	}
 
-	err = repo.AddFilm([]uint64{1}, 1)
+	err = repo.AddFilm(context.Background(), []uint64{1}, 1)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err)
 	}
@@ -485,7 +486,7 @@ func TestAddFilm(t *testing.T) {
 		regexp.QuoteMeta(selectRow)).
 		WithArgs(1, 1).WillReturnError(fmt.Errorf("repo err"))
 
-	err = repo.AddFilm([]uint64{1}, 1)
+	err = repo.AddFilm(context.Background(), []uint64{1}, 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
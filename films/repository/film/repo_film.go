@@ -1,15 +1,16 @@
 package film
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log/slog"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
 	"github.com/lib/pq"
 
@@ -19,28 +20,30 @@ import (
 //go:generate mockgen -source=repo_film.go -destination=../../mocks/film_repo_mock.go -package=mocks
 
 type IFilmsRepo interface {
-	GetFilmsByGenre(genre uint64, start uint64, end uint64) ([]models.FilmItem, error)
-	GetFilms(start uint64, end uint64) ([]models.FilmItem, error)
-	GetFilm(filmId uint64) (*models.FilmItem, error)
-	GetFilmRating(filmId uint64) (float64, uint64, error)
-	FindFilm(title string, dateFrom string, dateTo string,
+	GetFilmsByGenre(ctx context.Context, genre uint64, start uint64, end uint64) ([]models.FilmItem, error)
+	GetFilms(ctx context.Context, start uint64, end uint64) ([]models.FilmItem, error)
+	GetFilm(ctx context.Context, filmId uint64) (*models.FilmItem, error)
+	GetFilmRating(ctx context.Context, filmId uint64) (float64, uint64, error)
+	FindFilm(ctx context.Context, title string, dateFrom string, dateTo string,
 		ratingFrom float32, ratingTo float32, mpaa string, genres []uint32, actors []string,
 	) ([]models.FilmItem, error)
-	GetFavoriteFilms(userId uint64, start uint64, end uint64) ([]models.FilmItem, error)
-	AddFavoriteFilm(userId uint64, filmId uint64) error
-	RemoveFavoriteFilm(userId uint64, filmId uint64) error
-	CheckFilm(userId uint64, filmId uint64) (bool, error)
-	AddRating(filmId uint64, userId uint64, rating uint16) error
-	HasUsersRating(userId uint64, filmId uint64) (bool, error)
-	AddFilm(film models.FilmItem) error
-	GetFilmId(title string) (uint64, error)
+	GetFavoriteFilms(ctx context.Context, userId uint64, start uint64, end uint64) ([]models.FilmItem, error)
+	AddFavoriteFilm(ctx context.Context, userId uint64, filmId uint64) error
+	RemoveFavoriteFilm(ctx context.Context, userId uint64, filmId uint64) error
+	CheckFilm(ctx context.Context, userId uint64, filmId uint64) (bool, error)
+	AddRating(ctx context.Context, filmId uint64, userId uint64, rating uint16) error
+	HasUsersRating(ctx context.Context, userId uint64, filmId uint64) (bool, error)
+	AddFilm(ctx context.Context, film models.FilmItem) error
+	GetFilmId(ctx context.Context, title string) (uint64, error)
+	Close() error
 }
 
 type RepoPostgre struct {
 	db *sql.DB
+	mt *metrics.DBMetrics
 }
 
-func GetFilmRepo(config *configs.DbDsnCfg, lg *slog.Logger) (*RepoPostgre, error) {
+func GetFilmRepo(config *configs.DbDsnCfg, lg *slog.Logger, mt *metrics.Metrics) (*RepoPostgre, error) {
 	dsn := fmt.Sprintf("user=%s dbname=%s password= %s host=%s port=%d sslmode=%s",
 		config.User, config.DbName, config.Password, config.Host, config.Port, config.Sslmode)
 	db, err := sql.Open("pgx", dsn)
@@ -48,34 +51,28 @@ func GetFilmRepo(config *configs.DbDsnCfg, lg *slog.Logger) (*RepoPostgre, error
 		lg.Error("sql open error", "err", err.Error())
 		return nil, fmt.Errorf("get film repo: %w", err)
 	}
-	err = db.Ping()
+	db.SetMaxOpenConns(config.MaxOpenConns)
+
+	dbMetrics, err := mt.OpenDB("film", db, config.Timer, lg)
 	if err != nil {
 		lg.Error("sql ping error", "err", err.Error())
 		return nil, fmt.Errorf("get film repo: %w", err)
 	}
-	db.SetMaxOpenConns(config.MaxOpenConns)
 
-	postgreDb := RepoPostgre{db: db}
-
-	go postgreDb.pingDb(config.Timer, lg)
-	return &postgreDb, nil
+	return &RepoPostgre{db: db, mt: dbMetrics}, nil
 }
 
-func (repo *RepoPostgre) pingDb(timer uint32, lg *slog.Logger) {
-	for {
-		err := repo.db.Ping()
-		if err != nil {
-			lg.Error("Repo Film db ping error", "err", err.Error())
-		}
-
-		time.Sleep(time.Duration(timer) * time.Second)
-	}
+func (repo *RepoPostgre) Close() error {
+	return repo.mt.Close()
 }
 
-func (repo *RepoPostgre) GetFilmsByGenre(genre uint64, start uint64, end uint64) ([]models.FilmItem, error) {
+func (repo *RepoPostgre) GetFilmsByGenre(ctx context.Context, genre uint64, start uint64, end uint64) ([]models.FilmItem, error) {
+	ctx, done := repo.mt.Query(ctx, "GetFilmsByGenre")
+	defer done()
+
 	films := make([]models.FilmItem, 0, end-start)
 
-	rows, err := repo.db.Query(
+	rows, err := repo.db.QueryContext(ctx,
 		"SELECT film.id, film.title, poster FROM film "+
 			"JOIN films_genre ON film.id = films_genre.id_film "+
 			"WHERE id_genre = $1 "+
@@ -99,10 +96,13 @@ func (repo *RepoPostgre) GetFilmsByGenre(genre uint64, start uint64, end uint64)
 	return films, nil
 }
 
-func (repo *RepoPostgre) GetFilms(start uint64, end uint64) ([]models.FilmItem, error) {
+func (repo *RepoPostgre) GetFilms(ctx context.Context, start uint64, end uint64) ([]models.FilmItem, error) {
+	ctx, done := repo.mt.Query(ctx, "GetFilms")
+	defer done()
+
 	films := make([]models.FilmItem, 0, end-start)
 
-	rows, err := repo.db.Query(
+	rows, err := repo.db.QueryContext(ctx,
 		"SELECT film.id, film.title, poster FROM film "+
 			"ORDER BY release_date DESC "+
 			"OFFSET $1 LIMIT $2",
@@ -124,9 +124,12 @@ func (repo *RepoPostgre) GetFilms(start uint64, end uint64) ([]models.FilmItem,
 	return films, nil
 }
 
-func (repo *RepoPostgre) GetFilm(filmId uint64) (*models.FilmItem, error) {
+func (repo *RepoPostgre) GetFilm(ctx context.Context, filmId uint64) (*models.FilmItem, error) {
+	ctx, done := repo.mt.Query(ctx, "GetFilm")
+	defer done()
+
 	film := &models.FilmItem{}
-	err := repo.db.QueryRow(
+	err := repo.db.QueryRowContext(ctx,
 		"SELECT id, title, info, poster, release_date, country, mpaa FROM film "+
 			"WHERE id = $1", filmId).
 		Scan(&film.Id, &film.Title, &film.Info, &film.Poster, &film.ReleaseDate, &film.Country, &film.Mpaa)
@@ -141,10 +144,13 @@ func (repo *RepoPostgre) GetFilm(filmId uint64) (*models.FilmItem, error) {
 	return film, nil
 }
 
-func (repo *RepoPostgre) GetFilmRating(filmId uint64) (float64, uint64, error) {
+func (repo *RepoPostgre) GetFilmRating(ctx context.Context, filmId uint64) (float64, uint64, error) {
+	ctx, done := repo.mt.Query(ctx, "GetFilmRating")
+	defer done()
+
 	var rating sql.NullFloat64
 	var number sql.NullInt64
-	err := repo.db.QueryRow(
+	err := repo.db.QueryRowContext(ctx,
 		"SELECT AVG(rating), COUNT(rating) FROM users_comment "+
 			"WHERE id_film = $1", filmId).Scan(&rating, &number)
 	if err != nil {
@@ -157,9 +163,11 @@ func (repo *RepoPostgre) GetFilmRating(filmId uint64) (float64, uint64, error) {
 	return rating.Float64, uint64(number.Int64), nil
 }
 
-func (repo *RepoPostgre) FindFilm(title string, dateFrom string, dateTo string,
+func (repo *RepoPostgre) FindFilm(ctx context.Context, title string, dateFrom string, dateTo string,
 	ratingFrom float32, ratingTo float32, mpaa string, genres []uint32, actors []string,
 ) ([]models.FilmItem, error) {
+	ctx, done := repo.mt.Query(ctx, "FindFilm")
+	defer done()
 
 	films := []models.FilmItem{}
 	var hasWhere bool
@@ -241,7 +249,7 @@ func (repo *RepoPostgre) FindFilm(title string, dateFrom string, dateTo string,
 			"ORDER BY film.title")
 
 	params = append(params, ratingFrom, ratingTo)
-	rows, err := repo.db.Query(s.String(), params...)
+	rows, err := repo.db.QueryContext(ctx, s.String(), params...)
 
 	if err != nil {
 		return nil, fmt.Errorf("find film err: %w", err)
@@ -260,10 +268,13 @@ func (repo *RepoPostgre) FindFilm(title string, dateFrom string, dateTo string,
 	return films, nil
 }
 
-func (repo *RepoPostgre) GetFavoriteFilms(userId uint64, start uint64, end uint64) ([]models.FilmItem, error) {
+func (repo *RepoPostgre) GetFavoriteFilms(ctx context.Context, userId uint64, start uint64, end uint64) ([]models.FilmItem, error) {
+	ctx, done := repo.mt.Query(ctx, "GetFavoriteFilms")
+	defer done()
+
 	films := []models.FilmItem{}
 
-	rows, err := repo.db.Query(
+	rows, err := repo.db.QueryContext(ctx,
 		"SELECT film.title, film.id, film.poster FROM film "+
 			"JOIN users_favorite_film ON film.id = users_favorite_film.id_film "+
 			"WHERE id_user = $1 "+
@@ -285,8 +296,11 @@ func (repo *RepoPostgre) GetFavoriteFilms(userId uint64, start uint64, end uint6
 	return films, nil
 }
 
-func (repo *RepoPostgre) AddFavoriteFilm(userId uint64, filmId uint64) error {
-	_, err := repo.db.Exec(
+func (repo *RepoPostgre) AddFavoriteFilm(ctx context.Context, userId uint64, filmId uint64) error {
+	ctx, done := repo.mt.Query(ctx, "AddFavoriteFilm")
+	defer done()
+
+	_, err := repo.db.ExecContext(ctx,
 		"INSERT INTO users_favorite_film(id_user, id_film) VALUES ($1, $2)", userId, filmId)
 	if err != nil {
 		return fmt.Errorf("add favorite film err: %w", err)
@@ -295,8 +309,11 @@ func (repo *RepoPostgre) AddFavoriteFilm(userId uint64, filmId uint64) error {
 	return nil
 }
 
-func (repo *RepoPostgre) RemoveFavoriteFilm(userId uint64, filmId uint64) error {
-	_, err := repo.db.Exec(
+func (repo *RepoPostgre) RemoveFavoriteFilm(ctx context.Context, userId uint64, filmId uint64) error {
+	ctx, done := repo.mt.Query(ctx, "RemoveFavoriteFilm")
+	defer done()
+
+	_, err := repo.db.ExecContext(ctx,
 		"DELETE FROM users_favorite_film "+
 			"WHERE id_user = $1 AND id_film = $2", userId, filmId)
 	if err != nil {
@@ -306,9 +323,12 @@ func (repo *RepoPostgre) RemoveFavoriteFilm(userId uint64, filmId uint64) error
 	return nil
 }
 
-func (repo *RepoPostgre) CheckFilm(userId uint64, filmId uint64) (bool, error) {
+func (repo *RepoPostgre) CheckFilm(ctx context.Context, userId uint64, filmId uint64) (bool, error) {
+	ctx, done := repo.mt.Query(ctx, "CheckFilm")
+	defer done()
+
 	film := models.FilmItem{}
-	err := repo.db.QueryRow("SELECT id_film FROM users_favorite_film WHERE id_film = $1 AND id_user = $2", filmId, userId).Scan(&film.Id)
+	err := repo.db.QueryRowContext(ctx, "SELECT id_film FROM users_favorite_film WHERE id_film = $1 AND id_user = $2", filmId, userId).Scan(&film.Id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return false, nil
@@ -320,8 +340,11 @@ func (repo *RepoPostgre) CheckFilm(userId uint64, filmId uint64) (bool, error) {
 	return true, nil
 }
 
-func (repo *RepoPostgre) AddRating(filmId uint64, userId uint64, rating uint16) error {
-	_, err := repo.db.Exec(
+func (repo *RepoPostgre) AddRating(ctx context.Context, filmId uint64, userId uint64, rating uint16) error {
+	ctx, done := repo.mt.Query(ctx, "AddRating")
+	defer done()
+
+	_, err := repo.db.ExecContext(ctx,
 		"INSERT INTO users_comment(id_film, rating, id_user) "+
 			"VALUES($1, $2, $3)", filmId, rating, userId)
 	if err != nil {
@@ -331,9 +354,12 @@ func (repo *RepoPostgre) AddRating(filmId uint64, userId uint64, rating uint16)
 	return nil
 }
 
-func (repo *RepoPostgre) HasUsersRating(userId uint64, filmId uint64) (bool, error) {
+func (repo *RepoPostgre) HasUsersRating(ctx context.Context, userId uint64, filmId uint64) (bool, error) {
+	ctx, done := repo.mt.Query(ctx, "HasUsersRating")
+	defer done()
+
 	var id uint64
-	err := repo.db.QueryRow(
+	err := repo.db.QueryRowContext(ctx,
 		"SELECT id_user FROM users_comment "+
 			"WHERE id_user = $1 AND id_film = $2", userId, filmId).Scan(&id)
 	if err != nil {
@@ -347,8 +373,11 @@ func (repo *RepoPostgre) HasUsersRating(userId uint64, filmId uint64) (bool, err
 	return true, nil
 }
 
-func (repo *RepoPostgre) AddFilm(film models.FilmItem) error {
-	_, err := repo.db.Exec("INSERT INTO film(title, info, poster, release_date, country, mpaa) "+
+func (repo *RepoPostgre) AddFilm(ctx context.Context, film models.FilmItem) error {
+	ctx, done := repo.mt.Query(ctx, "AddFilm")
+	defer done()
+
+	_, err := repo.db.ExecContext(ctx, "INSERT INTO film(title, info, poster, release_date, country, mpaa) "+
 		"VALUES($1, $2, $3, $4, $5, $6)",
 		film.Title, film.Info, film.Poster, film.ReleaseDate, film.Country, film.Mpaa)
 	if err != nil {
@@ -358,9 +387,12 @@ func (repo *RepoPostgre) AddFilm(film models.FilmItem) error {
 	return nil
 }
 
-func (repo *RepoPostgre) GetFilmId(title string) (uint64, error) {
+func (repo *RepoPostgre) GetFilmId(ctx context.Context, title string) (uint64, error) {
+	ctx, done := repo.mt.Query(ctx, "GetFilmId")
+	defer done()
+
 	var id uint64
-	err := repo.db.QueryRow("SELECT id FROM film WHERE title = $1", title).Scan(&id)
+	err := repo.db.QueryRowContext(ctx, "SELECT id FROM film WHERE title = $1", title).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("get film id err: %w", err)
 	}
@@ -1,6 +1,7 @@
 package film
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -36,7 +37,7 @@ func TestGetFilmsByGenre(t *testing.T) {
 		db: db,
 	}
 
-	films, err := repo.GetFilmsByGenre(1, 1, 2)
+	films, err := repo.GetFilmsByGenre(context.Background(), 1, 1, 2)
 	if err != nil {
 		t.Errorf("GetFilmsByGenre error: %s", err)
 	}
@@ -56,7 +57,7 @@ func TestGetFilmsByGenre(t *testing.T) {
 		WithArgs(1, 1, 2).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	_, err = repo.GetFilmsByGenre(1, 1, 2)
+	_, err = repo.GetFilmsByGenre(context.Background(), 1, 1, 2)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -90,7 +91,7 @@ func TestGetFilms(t *testing.T) {
 		db: db,
 	}
 
-	films, err := repo.GetFilms(1, 2)
+	films, err := repo.GetFilms(context.Background(), 1, 2)
 	if err != nil {
 		t.Errorf("GetFilms error: %s", err)
 	}
@@ -110,7 +111,7 @@ func TestGetFilms(t *testing.T) {
 		WithArgs(1, 2).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	_, err = repo.GetFilms(1, 2)
+	_, err = repo.GetFilms(context.Background(), 1, 2)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -147,7 +148,7 @@ func TestGetFilm(t *testing.T) {
 		db: db,
 	}
 
-	films, err := repo.GetFilm(1)
+	films, err := repo.GetFilm(context.Background(), 1)
 	if err != nil {
 		t.Errorf("GetFilm error: %s", err)
 	}
@@ -167,7 +168,7 @@ func TestGetFilm(t *testing.T) {
 		WithArgs(1).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	_, err = repo.GetFilm(1)
+	_, err = repo.GetFilm(context.Background(), 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -201,7 +202,7 @@ func TestGetFilmRating(t *testing.T) {
 		db: db,
 	}
 
-	rating, number, err := repo.GetFilmRating(1)
+	rating, number, err := repo.GetFilmRating(context.Background(), 1)
 	if err != nil {
 		t.Errorf("GetFilm error: %s", err)
 	}
@@ -224,7 +225,7 @@ func TestGetFilmRating(t *testing.T) {
 		WithArgs(1).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	rating, number, err = repo.GetFilmRating(1)
+	rating, number, err = repo.GetFilmRating(context.Background(), 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -269,7 +270,7 @@ func TestFindFilm(t *testing.T) {
 		db: db,
 	}
 
-	film, err := repo.FindFilm("", "", "", float32(0), float32(10), "", []uint32{}, []string{""})
+	film, err := repo.FindFilm(context.Background(), "", "", "", float32(0), float32(10), "", []uint32{}, []string{""})
 	if err != nil {
 		t.Errorf("GetFilm error: %s", err)
 	}
@@ -288,7 +289,7 @@ func TestFindFilm(t *testing.T) {
 		WithArgs(float32(0), float32(10)).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	film, err = repo.FindFilm("", "", "", float32(0), float32(10), "", []uint32{0}, []string{""})
+	film, err = repo.FindFilm(context.Background(), "", "", "", float32(0), float32(10), "", []uint32{0}, []string{""})
 	if err == mock.ExpectationsWereMet() {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -330,7 +331,7 @@ func TestGetFavoriteFilms(t *testing.T) {
 		db: db,
 	}
 
-	films, err := repo.GetFavoriteFilms(1, 1, 2)
+	films, err := repo.GetFavoriteFilms(context.Background(), 1, 1, 2)
 	if err != nil {
 		t.Errorf("GetFilmsByGenre error: %s", err)
 	}
@@ -350,7 +351,7 @@ func TestGetFavoriteFilms(t *testing.T) {
 		WithArgs(1, 1, 2).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	_, err = repo.GetFavoriteFilms(1, 1, 2)
+	_, err = repo.GetFavoriteFilms(context.Background(), 1, 1, 2)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -388,7 +389,7 @@ func TestCheckFilm(t *testing.T) {
 		db: db,
 	}
 
-	found, err := repo.CheckFilm(1, 1)
+	found, err := repo.CheckFilm(context.Background(), 1, 1)
 	if err != nil {
 		t.Errorf("GetFilmsByGenre error: %s", err)
 	}
@@ -408,7 +409,7 @@ func TestCheckFilm(t *testing.T) {
 		WithArgs(1, 1).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	found, err = repo.CheckFilm(1, 1)
+	found, err = repo.CheckFilm(context.Background(), 1, 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -450,7 +451,7 @@ func TestHasUsersRating(t *testing.T) {
 		db: db,
 	}
 
-	found, err := repo.HasUsersRating(1, 1)
+	found, err := repo.HasUsersRating(context.Background(), 1, 1)
 	if err != nil {
 		t.Errorf("GetFilmsByGenre error: %s", err)
 	}
@@ -470,7 +471,7 @@ func TestHasUsersRating(t *testing.T) {
 		WithArgs(1, 1).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	found, err = repo.HasUsersRating(1, 1)
+	found, err = repo.HasUsersRating(context.Background(), 1, 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -502,7 +503,7 @@ func TestAddFavoriteFilm(t *testing.T) {
 		db: db,
 	}
 
-	err = repo.AddFavoriteFilm(1, 1)
+	err = repo.AddFavoriteFilm(context.Background(), 1, 1)
 	if err != nil {
 		t.Errorf("GetFilmsByGenre error: %s", err)
 	}
@@ -516,7 +517,7 @@ func TestAddFavoriteFilm(t *testing.T) {
 		regexp.QuoteMeta(selectRow)).
 		WithArgs(1, 1).WillReturnError(fmt.Errorf("repo err"))
 
-	err = repo.AddFavoriteFilm(1, 1)
+	err = repo.AddFavoriteFilm(context.Background(), 1, 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -544,7 +545,7 @@ func TestRemoveFavoriteFilm(t *testing.T) {
 		db: db,
 	}
 
-	err = repo.RemoveFavoriteFilm(1, 1)
+	err = repo.RemoveFavoriteFilm(context.Background(), 1, 1)
 	if err != nil {
 		t.Errorf("GetFilmsByGenre error: %s", err)
 	}
@@ -558,7 +559,7 @@ func TestRemoveFavoriteFilm(t *testing.T) {
 		regexp.QuoteMeta(selectRow)).
 		WithArgs(1, 1).WillReturnError(fmt.Errorf("repo err"))
 
-	err = repo.RemoveFavoriteFilm(1, 1)
+	err = repo.RemoveFavoriteFilm(context.Background(), 1, 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -586,7 +587,7 @@ func TestAddRating(t *testing.T) {
 		db: db,
 	}
 
-	err = repo.AddRating(1, 1, 5)
+	err = repo.AddRating(context.Background(), 1, 1, 5)
 	if err != nil {
 		t.Errorf("unexpected err: %s", err)
 	}
@@ -600,7 +601,7 @@ func TestAddRating(t *testing.T) {
 		regexp.QuoteMeta(selectRow)).
 		WithArgs(1, 1, 5).WillReturnError(fmt.Errorf("repo err"))
 
-	err = repo.AddRating(1, 5, 1)
+	err = repo.AddRating(context.Background(), 1, 5, 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -636,7 +637,7 @@ func TestAddFilm(t *testing.T) {
 		db: db,
 	}
 
-	err = repo.AddFilm(filmItem)
+	err = repo.AddFilm(context.Background(), filmItem)
 	if err != nil {
 		t.Errorf("unexpected err: %s", err)
 	}
@@ -650,7 +651,7 @@ func TestAddFilm(t *testing.T) {
 		regexp.QuoteMeta(selectRow)).
 		WithArgs("t", "i", "p", "rd", "c", "m").WillReturnError(fmt.Errorf("repo err"))
 
-	err = repo.AddFilm(filmItem)
+	err = repo.AddFilm(context.Background(), filmItem)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -688,7 +689,7 @@ func TestGetFilmId(t *testing.T) {
 		db: db,
 	}
 
-	id, err := repo.GetFilmId(expect[0].Title)
+	id, err := repo.GetFilmId(context.Background(), expect[0].Title)
 	if err != nil {
 		t.Errorf("GetFilmsByGenre error: %s", err)
 	}
@@ -708,7 +709,7 @@ func TestGetFilmId(t *testing.T) {
 		WithArgs("t").
 		WillReturnError(fmt.Errorf("db_error"))
 
-	id, err = repo.GetFilmId(expect[0].Title)
+	id, err = repo.GetFilmId(context.Background(), expect[0].Title)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
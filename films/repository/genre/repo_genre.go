@@ -1,15 +1,16 @@
 package genre
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log/slog"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
 
 	_ "github.com/jackc/pgx/stdlib"
@@ -18,16 +19,18 @@ import (
 //go:generate mockgen -source=repo_genre.go -destination=../../mocks/genre_repo_mock.go -package=mocks
 
 type IGenreRepo interface {
-	GetFilmGenres(filmId uint64) ([]models.GenreItem, error)
-	GetGenreById(genreId uint64) (string, error)
-	AddFilm(genres []uint64, filmId uint64) error
+	GetFilmGenres(ctx context.Context, filmId uint64) ([]models.GenreItem, error)
+	GetGenreById(ctx context.Context, genreId uint64) (string, error)
+	AddFilm(ctx context.Context, genres []uint64, filmId uint64) error
+	Close() error
 }
 
 type RepoPostgre struct {
 	db *sql.DB
+	mt *metrics.DBMetrics
 }
 
-func GetGenreRepo(config *configs.DbDsnCfg, lg *slog.Logger) (*RepoPostgre, error) {
+func GetGenreRepo(config *configs.DbDsnCfg, lg *slog.Logger, mt *metrics.Metrics) (*RepoPostgre, error) {
 	dsn := fmt.Sprintf("user=%s dbname=%s password= %s host=%s port=%d sslmode=%s",
 		config.User, config.DbName, config.Password, config.Host, config.Port, config.Sslmode)
 	db, err := sql.Open("pgx", dsn)
@@ -35,34 +38,28 @@ func GetGenreRepo(config *configs.DbDsnCfg, lg *slog.Logger) (*RepoPostgre, erro
 		lg.Error("sql open error", "err", err.Error())
 		return nil, fmt.Errorf("get genre repo: %w", err)
 	}
-	err = db.Ping()
+	db.SetMaxOpenConns(config.MaxOpenConns)
+
+	dbMetrics, err := mt.OpenDB("genre", db, config.Timer, lg)
 	if err != nil {
 		lg.Error("sql ping error", "err", err.Error())
 		return nil, fmt.Errorf("get genre repo: %w", err)
 	}
-	db.SetMaxOpenConns(config.MaxOpenConns)
 
-	postgreDb := RepoPostgre{db: db}
-
-	go postgreDb.pingDb(config.Timer, lg)
-	return &postgreDb, nil
+	return &RepoPostgre{db: db, mt: dbMetrics}, nil
 }
 
-func (repo *RepoPostgre) pingDb(timer uint32, lg *slog.Logger) {
-	for {
-		err := repo.db.Ping()
-		if err != nil {
-			lg.Error("Repo Genre db ping error", "err", err.Error())
-		}
-
-		time.Sleep(time.Duration(timer) * time.Second)
-	}
+func (repo *RepoPostgre) Close() error {
+	return repo.mt.Close()
 }
 
-func (repo *RepoPostgre) GetFilmGenres(filmId uint64) ([]models.GenreItem, error) {
+func (repo *RepoPostgre) GetFilmGenres(ctx context.Context, filmId uint64) ([]models.GenreItem, error) {
+	ctx, done := repo.mt.Query(ctx, "GetFilmGenres")
+	defer done()
+
 	genres := []models.GenreItem{}
 
-	rows, err := repo.db.Query(
+	rows, err := repo.db.QueryContext(ctx,
 		"SELECT genre.id, genre.title FROM genre "+
 			"JOIN films_genre ON genre.id = films_genre.id_genre "+
 			"WHERE films_genre.id_film = $1", filmId)
@@ -83,10 +80,13 @@ func (repo *RepoPostgre) GetFilmGenres(filmId uint64) ([]models.GenreItem, error
 	return genres, nil
 }
 
-func (repo *RepoPostgre) GetGenreById(genreId uint64) (string, error) {
+func (repo *RepoPostgre) GetGenreById(ctx context.Context, genreId uint64) (string, error) {
+	ctx, done := repo.mt.Query(ctx, "GetGenreById")
+	defer done()
+
 	var genre string
 
-	err := repo.db.QueryRow(
+	err := repo.db.QueryRowContext(ctx,
 		"SELECT title FROM genre "+
 			"WHERE id = $1", genreId).Scan(&genre)
 	if err != nil {
@@ -99,7 +99,10 @@ func (repo *RepoPostgre) GetGenreById(genreId uint64) (string, error) {
 	return genre, nil
 }
 
-func (repo *RepoPostgre) AddFilm(genres []uint64, filmId uint64) error {
+func (repo *RepoPostgre) AddFilm(ctx context.Context, genres []uint64, filmId uint64) error {
+	ctx, done := repo.mt.Query(ctx, "AddFilm")
+	defer done()
+
 	var s strings.Builder
 	var params []interface{}
 	params = append(params, filmId)
@@ -113,7 +116,7 @@ func (repo *RepoPostgre) AddFilm(genres []uint64, filmId uint64) error {
 		params = append(params, genre)
 	}
 
-	_, err := repo.db.Exec(s.String(), params...)
+	_, err := repo.db.ExecContext(ctx, s.String(), params...)
 	if err != nil {
 		return fmt.Errorf("add films genres error: %w", err)
 	}
@@ -1,6 +1,7 @@
 package genre
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -36,7 +37,7 @@ func TestGetFilmGenres(t *testing.T) {
 		db: db,
 	}
 
-	genres, err := repo.GetFilmGenres(1)
+	genres, err := repo.GetFilmGenres(context.Background(), 1)
 	if err != nil {
 		t.Errorf("GetFilm error: %s", err)
 	}
@@ -56,7 +57,7 @@ func TestGetFilmGenres(t *testing.T) {
 		WithArgs(1).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	genres, err = repo.GetFilmGenres(1)
+	genres, err = repo.GetFilmGenres(context.Background(), 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -94,7 +95,7 @@ func TestGetGenreById(t *testing.T) {
 		db: db,
 	}
 
-	genre, err := repo.GetGenreById(1)
+	genre, err := repo.GetGenreById(context.Background(), 1)
 	if err != nil {
 		t.Errorf("GetFilm error: %s", err)
 	}
@@ -114,7 +115,7 @@ func TestGetGenreById(t *testing.T) {
 		WithArgs(1).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	genre, err = repo.GetGenreById(1)
+	genre, err = repo.GetGenreById(context.Background(), 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -145,7 +146,7 @@ func TestAddFilm(t *testing.T) {
 		db: db,
 	}
 
-	err = repo.AddFilm([]uint64{1}, 1)
+	err = repo.AddFilm(context.Background(), []uint64{1}, 1)
 	if err != nil {
 		t.Errorf("unexpected error: %s", err)
 	}
@@ -159,7 +160,7 @@ func TestAddFilm(t *testing.T) {
 		regexp.QuoteMeta(selectRow)).
 		WithArgs(1, 1).WillReturnError(fmt.Errorf("repo err"))
 
-	err = repo.AddFilm([]uint64{1}, 1)
+	err = repo.AddFilm(context.Background(), []uint64{1}, 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -1,27 +1,30 @@
 package profession
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log/slog"
-	"time"
 
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
 )
 
 //go:generate mockgen -source=repo_profession.go -destination=../../mocks/profession_repo_mock.go -package=mocks
 
 type IProfessionRepo interface {
-	GetActorsProfessions(actorId uint64) ([]models.ProfessionItem, error)
+	GetActorsProfessions(ctx context.Context, actorId uint64) ([]models.ProfessionItem, error)
+	Close() error
 }
 
 type RepoPostgre struct {
 	db *sql.DB
+	mt *metrics.DBMetrics
 }
 
-func GetProfessionRepo(config *configs.DbDsnCfg, lg *slog.Logger) (*RepoPostgre, error) {
+func GetProfessionRepo(config *configs.DbDsnCfg, lg *slog.Logger, mt *metrics.Metrics) (*RepoPostgre, error) {
 	dsn := fmt.Sprintf("user=%s dbname=%s password= %s host=%s port=%d sslmode=%s",
 		config.User, config.DbName, config.Password, config.Host, config.Port, config.Sslmode)
 	db, err := sql.Open("pgx", dsn)
@@ -29,34 +32,28 @@ func GetProfessionRepo(config *configs.DbDsnCfg, lg *slog.Logger) (*RepoPostgre,
 		lg.Error("sql open error", "err", err.Error())
 		return nil, fmt.Errorf("get prof repo: %w", err)
 	}
-	err = db.Ping()
+	db.SetMaxOpenConns(config.MaxOpenConns)
+
+	dbMetrics, err := mt.OpenDB("profession", db, config.Timer, lg)
 	if err != nil {
 		lg.Error("sql ping error", "err", err.Error())
 		return nil, fmt.Errorf("get prof repo: %w", err)
 	}
-	db.SetMaxOpenConns(config.MaxOpenConns)
 
-	postgreDb := RepoPostgre{db: db}
-
-	go postgreDb.pingDb(config.Timer, lg)
-	return &postgreDb, nil
+	return &RepoPostgre{db: db, mt: dbMetrics}, nil
 }
 
-func (repo *RepoPostgre) pingDb(timer uint32, lg *slog.Logger) {
-	for {
-		err := repo.db.Ping()
-		if err != nil {
-			lg.Error("Repo Profession db ping error", "err", err.Error())
-		}
-
-		time.Sleep(time.Duration(timer) * time.Second)
-	}
+func (repo *RepoPostgre) Close() error {
+	return repo.mt.Close()
 }
 
-func (repo *RepoPostgre) GetActorsProfessions(actorId uint64) ([]models.ProfessionItem, error) {
+func (repo *RepoPostgre) GetActorsProfessions(ctx context.Context, actorId uint64) ([]models.ProfessionItem, error) {
+	ctx, done := repo.mt.Query(ctx, "GetActorsProfessions")
+	defer done()
+
 	professions := []models.ProfessionItem{}
 
-	rows, err := repo.db.Query(
+	rows, err := repo.db.QueryContext(ctx,
 		"SELECT DISTINCT title FROM profession "+
 			"JOIN person_in_film ON profession.id = person_in_film.id_profession "+
 			"WHERE id_person = $1", actorId)
@@ -1,6 +1,7 @@
 package profession
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -36,7 +37,7 @@ func TestGetActorProfessions(t *testing.T) {
 		db: db,
 	}
 
-	career, err := repo.GetActorsProfessions(1)
+	career, err := repo.GetActorsProfessions(context.Background(), 1)
 	if err != nil {
 		t.Errorf("GetFilm error: %s", err)
 	}
@@ -56,7 +57,7 @@ func TestGetActorProfessions(t *testing.T) {
 		WithArgs(1).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	career, err = repo.GetActorsProfessions(1)
+	career, err = repo.GetActorsProfessions(context.Background(), 1)
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
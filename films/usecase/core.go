@@ -8,16 +8,15 @@ import (
 	"time"
 
 	auth "github.com/go-park-mail-ru/2023_2_Vkladyshi/authorization/proto"
-	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/films/repository/calendar"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/films/repository/crew"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/films/repository/film"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/films/repository/genre"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/films/repository/profession"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/requests"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 var (
@@ -28,25 +27,25 @@ var (
 //go:generate mockgen -source=core.go -destination=../mocks/core_mock.go -package=mocks
 
 type ICore interface {
-	GetFilmsAndGenreTitle(genreId uint64, start uint64, end uint64) ([]models.FilmItem, string, error)
-	GetFilmInfo(filmId uint64) (*requests.FilmResponse, error)
-	GetActorInfo(actorId uint64) (*requests.ActorResponse, error)
-	GetActorsCareer(actorId uint64) ([]models.ProfessionItem, error)
-	GetGenre(genreId uint64) (string, error)
-	FindFilm(title string, dateFrom string, dateTo string,
+	GetFilmsAndGenreTitle(ctx context.Context, genreId uint64, start uint64, end uint64) ([]models.FilmItem, string, error)
+	GetFilmInfo(ctx context.Context, filmId uint64) (*requests.FilmResponse, error)
+	GetActorInfo(ctx context.Context, actorId uint64) (*requests.ActorResponse, error)
+	GetActorsCareer(ctx context.Context, actorId uint64) ([]models.ProfessionItem, error)
+	GetGenre(ctx context.Context, genreId uint64) (string, error)
+	FindFilm(ctx context.Context, title string, dateFrom string, dateTo string,
 		ratingFrom float32, ratingTo float32, mpaa string, genres []uint32, actors []string,
 	) ([]models.FilmItem, error)
-	FavoriteFilms(userId uint64, start uint64, end uint64) ([]models.FilmItem, error)
-	FavoriteFilmsAdd(userId uint64, filmId uint64) error
-	FavoriteFilmsRemove(userId uint64, filmId uint64) error
-	GetCalendar() (*requests.CalendarResponse, error)
+	FavoriteFilms(ctx context.Context, userId uint64, start uint64, end uint64) ([]models.FilmItem, error)
+	FavoriteFilmsAdd(ctx context.Context, userId uint64, filmId uint64) error
+	FavoriteFilmsRemove(ctx context.Context, userId uint64, filmId uint64) error
+	GetCalendar(ctx context.Context) (*requests.CalendarResponse, error)
 	GetUserId(ctx context.Context, sid string) (uint64, error)
-	FindActor(name string, birthDate string, films []string, career []string, country string) ([]models.Character, error)
-	AddRating(filmId uint64, userId uint64, rating uint16) (bool, error)
-	AddFilm(film models.FilmItem, genres []uint64, actors []uint64) error
-	FavoriteActors(userId uint64, start uint64, end uint64) ([]models.Character, error)
-	FavoriteActorsAdd(userId uint64, filmId uint64) error
-	FavoriteActorsRemove(userId uint64, filmId uint64) error
+	FindActor(ctx context.Context, name string, birthDate string, films []string, career []string, country string) ([]models.Character, error)
+	AddRating(ctx context.Context, filmId uint64, userId uint64, rating uint16) (bool, error)
+	AddFilm(ctx context.Context, film models.FilmItem, genres []uint64, actors []uint64) error
+	FavoriteActors(ctx context.Context, userId uint64, start uint64, end uint64) ([]models.Character, error)
+	FavoriteActorsAdd(ctx context.Context, userId uint64, filmId uint64) error
+	FavoriteActorsRemove(ctx context.Context, userId uint64, filmId uint64) error
 }
 
 type Core struct {
@@ -57,27 +56,12 @@ type Core struct {
 	profession profession.IProfessionRepo
 	calendar   calendar.ICalendarRepo
 	client     auth.AuthorizationClient
+	recorder   metrics.IFilmsRecorder
 }
 
-func GetClient(port string) (auth.AuthorizationClient, error) {
-	conn, err := grpc.Dial(port, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return nil, fmt.Errorf("grpc connect err: %w", err)
-	}
-	client := auth.NewAuthorizationClient(conn)
-
-	return client, nil
-}
-
-func GetCore(cfg_sql *configs.DbDsnCfg, lg *slog.Logger,
+func GetCore(client auth.AuthorizationClient, lg *slog.Logger, mt *metrics.Metrics,
 	films film.IFilmsRepo, genres genre.IGenreRepo, actors crew.ICrewRepo, professions profession.IProfessionRepo, calendar calendar.ICalendarRepo,
 ) *Core {
-	client, err := GetClient(cfg_sql.GrpcPort)
-	if err != nil {
-		lg.Error("get client error", "err", err.Error())
-		return nil
-	}
-
 	core := Core{
 		lg:         lg.With("module", "core"),
 		films:      films,
@@ -86,70 +70,71 @@ func GetCore(cfg_sql *configs.DbDsnCfg, lg *slog.Logger,
 		profession: professions,
 		calendar:   calendar,
 		client:     client,
+		recorder:   mt.Business(),
 	}
 	return &core
 }
 
-func (core *Core) GetFilmsAndGenreTitle(genreId uint64, start uint64, end uint64) ([]models.FilmItem, string, error) {
+func (core *Core) GetFilmsAndGenreTitle(ctx context.Context, genreId uint64, start uint64, end uint64) ([]models.FilmItem, string, error) {
 	var films []models.FilmItem
 	var err error
 
 	if genreId == 0 {
-		films, err = core.films.GetFilms(start, end)
+		films, err = core.films.GetFilms(ctx, start, end)
 	} else {
-		films, err = core.films.GetFilmsByGenre(genreId, start, end)
+		films, err = core.films.GetFilmsByGenre(ctx, genreId, start, end)
 	}
 	if err != nil {
-		core.lg.Error("failed to get films from db", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("failed to get films from db", "err", err.Error())
 		return nil, "", fmt.Errorf("GetFilms err: %w", err)
 	}
 
-	genre, err := core.genres.GetGenreById(genreId)
+	genre, err := core.genres.GetGenreById(ctx, genreId)
 	if err != nil {
-		core.lg.Error("failed to get genre by id", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("failed to get genre by id", "err", err.Error())
 		return nil, "", fmt.Errorf("GetFilms err: %w", err)
 	}
 
 	return films, genre, nil
 }
 
-func (core *Core) GetFilmInfo(filmId uint64) (*requests.FilmResponse, error) {
-	film, err := core.films.GetFilm(filmId)
+func (core *Core) GetFilmInfo(ctx context.Context, filmId uint64) (*requests.FilmResponse, error) {
+	film, err := core.films.GetFilm(ctx, filmId)
 	if err != nil {
-		core.lg.Error("get film error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("get film error", "err", err.Error())
 		return nil, fmt.Errorf("get film err: %w", err)
 	}
 	if film.Title == "" {
 		return nil, ErrNotFound
 	}
 
-	genres, err := core.genres.GetFilmGenres(filmId)
+	genres, err := core.genres.GetFilmGenres(ctx, filmId)
 	if err != nil {
-		core.lg.Error("get film genres error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("get film genres error", "err", err.Error())
 		return nil, fmt.Errorf("get film genres err: %w", err)
 	}
 
-	rating, number, err := core.films.GetFilmRating(filmId)
+	rating, number, err := core.films.GetFilmRating(ctx, filmId)
 	if err != nil {
-		core.lg.Error("get film rating error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("get film rating error", "err", err.Error())
 		return nil, fmt.Errorf("get film rating err: %w", err)
 	}
 
-	directors, err := core.crew.GetFilmDirectors(filmId)
+	directors, err := core.crew.GetFilmDirectors(ctx, filmId)
 	if err != nil {
-		core.lg.Error("get film directors error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("get film directors error", "err", err.Error())
 		return nil, fmt.Errorf("get film directors err: %w", err)
 	}
 
-	scenarists, err := core.crew.GetFilmScenarists(filmId)
+	scenarists, err := core.crew.GetFilmScenarists(ctx, filmId)
 	if err != nil {
-		core.lg.Error("get film scenarists error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("get film scenarists error", "err", err.Error())
 		return nil, fmt.Errorf("get film scenarists err: %w", err)
 	}
 
-	characters, err := core.crew.GetFilmCharacters(filmId)
+	characters, err := core.crew.GetFilmCharacters(ctx, filmId)
 	if err != nil {
-		core.lg.Error("get film characters error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("get film characters error", "err", err.Error())
 		return nil, fmt.Errorf("get film scenarists err: %w", err)
 	}
 
@@ -166,19 +151,19 @@ func (core *Core) GetFilmInfo(filmId uint64) (*requests.FilmResponse, error) {
 	return &result, nil
 }
 
-func (core *Core) GetActorInfo(actorId uint64) (*requests.ActorResponse, error) {
-	actor, err := core.crew.GetActor(actorId)
+func (core *Core) GetActorInfo(ctx context.Context, actorId uint64) (*requests.ActorResponse, error) {
+	actor, err := core.crew.GetActor(ctx, actorId)
 	if err != nil {
-		core.lg.Error("get actor error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("get actor error", "err", err.Error())
 		return nil, fmt.Errorf("get actor err: %w", err)
 	}
 	if actor.Name == "" {
 		return nil, ErrNotFound
 	}
 
-	career, err := core.profession.GetActorsProfessions(actorId)
+	career, err := core.profession.GetActorsProfessions(ctx, actorId)
 	if err != nil {
-		core.lg.Error("get actor profession error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("get actor profession error", "err", err.Error())
 		return nil, fmt.Errorf("get actor profession err: %w", err)
 	}
 
@@ -193,33 +178,33 @@ func (core *Core) GetActorInfo(actorId uint64) (*requests.ActorResponse, error)
 	return &result, nil
 }
 
-func (core *Core) GetActorsCareer(actorId uint64) ([]models.ProfessionItem, error) {
-	career, err := core.profession.GetActorsProfessions(actorId)
+func (core *Core) GetActorsCareer(ctx context.Context, actorId uint64) ([]models.ProfessionItem, error) {
+	career, err := core.profession.GetActorsProfessions(ctx, actorId)
 	if err != nil {
-		core.lg.Error("Get Actors Career error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("Get Actors Career error", "err", err.Error())
 		return nil, fmt.Errorf("GetActorsCareer err: %w", err)
 	}
 
 	return career, nil
 }
 
-func (core *Core) GetGenre(genreId uint64) (string, error) {
-	genre, err := core.genres.GetGenreById(genreId)
+func (core *Core) GetGenre(ctx context.Context, genreId uint64) (string, error) {
+	genre, err := core.genres.GetGenreById(ctx, genreId)
 	if err != nil {
-		core.lg.Error("GetGenre error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("GetGenre error", "err", err.Error())
 		return "", fmt.Errorf("GetGenre err: %w", err)
 	}
 
 	return genre, nil
 }
 
-func (core *Core) FindFilm(title string, dateFrom string, dateTo string,
+func (core *Core) FindFilm(ctx context.Context, title string, dateFrom string, dateTo string,
 	ratingFrom float32, ratingTo float32, mpaa string, genres []uint32, actors []string,
 ) ([]models.FilmItem, error) {
 
-	films, err := core.films.FindFilm(title, dateFrom, dateTo, ratingFrom, ratingTo, mpaa, genres, actors)
+	films, err := core.films.FindFilm(ctx, title, dateFrom, dateTo, ratingFrom, ratingTo, mpaa, genres, actors)
 	if err != nil {
-		core.lg.Error("find film error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("find film error", "err", err.Error())
 		return nil, fmt.Errorf("find film err: %w", err)
 	}
 
@@ -230,51 +215,53 @@ func (core *Core) FindFilm(title string, dateFrom string, dateTo string,
 	return films, nil
 }
 
-func (core *Core) FavoriteFilms(userId uint64, start uint64, end uint64) ([]models.FilmItem, error) {
-	films, err := core.films.GetFavoriteFilms(userId, start, end)
+func (core *Core) FavoriteFilms(ctx context.Context, userId uint64, start uint64, end uint64) ([]models.FilmItem, error) {
+	films, err := core.films.GetFavoriteFilms(ctx, userId, start, end)
 	if err != nil {
-		core.lg.Error("favorite films error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("favorite films error", "err", err.Error())
 		return nil, fmt.Errorf("favorite films err: %w", err)
 	}
 
 	return films, nil
 }
 
-func (core *Core) FavoriteFilmsAdd(userId uint64, filmId uint64) error {
-	found, err := core.films.CheckFilm(userId, filmId)
+func (core *Core) FavoriteFilmsAdd(ctx context.Context, userId uint64, filmId uint64) error {
+	found, err := core.films.CheckFilm(ctx, userId, filmId)
 	if err != nil {
-		core.lg.Error("favorite film add error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("favorite film add error", "err", err.Error())
 		return fmt.Errorf("favorite film add err: %w", err)
 	}
 	if found {
 		return ErrFoundFavorite
 	}
 
-	err = core.films.AddFavoriteFilm(userId, filmId)
+	err = core.films.AddFavoriteFilm(ctx, userId, filmId)
 	if err != nil {
-		core.lg.Error("favorite film add error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("favorite film add error", "err", err.Error())
 		return fmt.Errorf("favorite film add err: %w", err)
 	}
+	core.recorder.FavoriteAdded(metrics.FavoriteFilm)
 
 	return nil
 }
 
-func (core *Core) FavoriteFilmsRemove(userId uint64, filmId uint64) error {
-	err := core.films.RemoveFavoriteFilm(userId, filmId)
+func (core *Core) FavoriteFilmsRemove(ctx context.Context, userId uint64, filmId uint64) error {
+	err := core.films.RemoveFavoriteFilm(ctx, userId, filmId)
 	if err != nil {
-		core.lg.Error("favorite film remove error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("favorite film remove error", "err", err.Error())
 		return fmt.Errorf("favorite film remove err: %w", err)
 	}
+	core.recorder.FavoriteRemoved(metrics.FavoriteFilm)
 
 	return nil
 }
 
-func (core *Core) GetCalendar() (*requests.CalendarResponse, error) {
+func (core *Core) GetCalendar(ctx context.Context) (*requests.CalendarResponse, error) {
 	result := &requests.CalendarResponse{}
 
-	news, err := core.calendar.GetCalendar()
+	news, err := core.calendar.GetCalendar(ctx)
 	if err != nil {
-		core.lg.Error("get calendar error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("get calendar error", "err", err.Error())
 		return nil, fmt.Errorf("get calendar err: %w", err)
 	}
 
@@ -291,16 +278,16 @@ func (core *Core) GetUserId(ctx context.Context, sid string) (uint64, error) {
 
 	response, err := core.client.GetId(ctx, &request)
 	if err != nil {
-		core.lg.Error("get user id error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("get user id error", "err", err.Error())
 		return 0, fmt.Errorf("get user id err: %w", err)
 	}
 	return uint64(response.Value), nil
 }
 
-func (core *Core) FindActor(name string, birthDate string, films []string, career []string, country string) ([]models.Character, error) {
-	actors, err := core.crew.FindActor(name, birthDate, films, career, country)
+func (core *Core) FindActor(ctx context.Context, name string, birthDate string, films []string, career []string, country string) ([]models.Character, error) {
+	actors, err := core.crew.FindActor(ctx, name, birthDate, films, career, country)
 	if err != nil {
-		core.lg.Error("find actor error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("find actor error", "err", err.Error())
 		return nil, fmt.Errorf("find actor err: %w", err)
 	}
 	if len(actors) == 0 {
@@ -310,88 +297,91 @@ func (core *Core) FindActor(name string, birthDate string, films []string, caree
 	return actors, nil
 }
 
-func (core *Core) AddRating(filmId uint64, userId uint64, rating uint16) (bool, error) {
-	found, err := core.films.HasUsersRating(userId, filmId)
+func (core *Core) AddRating(ctx context.Context, filmId uint64, userId uint64, rating uint16) (bool, error) {
+	found, err := core.films.HasUsersRating(ctx, userId, filmId)
 	if err != nil {
-		core.lg.Error("find users rating error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("find users rating error", "err", err.Error())
 		return false, fmt.Errorf("find users rating error: %w", err)
 	}
 	if found {
 		return found, nil
 	}
 
-	err = core.films.AddRating(filmId, userId, rating)
+	err = core.films.AddRating(ctx, filmId, userId, rating)
 	if err != nil {
-		core.lg.Error("add rating error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("add rating error", "err", err.Error())
 		return false, fmt.Errorf("add rating err: %w", err)
 	}
+	core.recorder.RatingAdded(rating)
 
 	return false, nil
 }
 
-func (core *Core) AddFilm(film models.FilmItem, genres []uint64, actors []uint64) error {
-	err := core.films.AddFilm(film)
+func (core *Core) AddFilm(ctx context.Context, film models.FilmItem, genres []uint64, actors []uint64) error {
+	err := core.films.AddFilm(ctx, film)
 	if err != nil {
-		core.lg.Error("add film error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("add film error", "err", err.Error())
 		return fmt.Errorf("add film err: %w", err)
 	}
 
-	id, err := core.films.GetFilmId(film.Title)
+	id, err := core.films.GetFilmId(ctx, film.Title)
 	if err != nil {
-		core.lg.Error("get film id", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("get film id", "err", err.Error())
 		return fmt.Errorf("add film err: %w", err)
 	}
 
-	err = core.genres.AddFilm(genres, id)
+	err = core.genres.AddFilm(ctx, genres, id)
 	if err != nil {
-		core.lg.Error("add films genres error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("add films genres error", "err", err.Error())
 		return fmt.Errorf("add film err: %w", err)
 	}
 
-	err = core.crew.AddFilm(actors, id)
+	err = core.crew.AddFilm(ctx, actors, id)
 	if err != nil {
-		core.lg.Error("add films actors error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("add films actors error", "err", err.Error())
 		return fmt.Errorf("add film err: %w", err)
 	}
 
 	return nil
 }
 
-func (core *Core) FavoriteActors(userId uint64, start uint64, end uint64) ([]models.Character, error) {
-	actors, err := core.crew.GetFavoriteActors(userId, start, end)
+func (core *Core) FavoriteActors(ctx context.Context, userId uint64, start uint64, end uint64) ([]models.Character, error) {
+	actors, err := core.crew.GetFavoriteActors(ctx, userId, start, end)
 	if err != nil {
-		core.lg.Error("favorite actors error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("favorite actors error", "err", err.Error())
 		return nil, fmt.Errorf("favorite actors err: %w", err)
 	}
 
 	return actors, nil
 }
 
-func (core *Core) FavoriteActorsAdd(userId uint64, actorId uint64) error {
-	found, err := core.crew.CheckActor(userId, actorId)
+func (core *Core) FavoriteActorsAdd(ctx context.Context, userId uint64, actorId uint64) error {
+	found, err := core.crew.CheckActor(ctx, userId, actorId)
 	if err != nil {
-		core.lg.Error("favorite actors add error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("favorite actors add error", "err", err.Error())
 		return fmt.Errorf("favorite actors add err: %w", err)
 	}
 	if found {
 		return ErrFoundFavorite
 	}
 
-	err = core.crew.AddFavoriteActor(userId, actorId)
+	err = core.crew.AddFavoriteActor(ctx, userId, actorId)
 	if err != nil {
-		core.lg.Error("favorite actors add error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("favorite actors add error", "err", err.Error())
 		return fmt.Errorf("favorite actors add err: %w", err)
 	}
+	core.recorder.FavoriteAdded(metrics.FavoriteActor)
 
 	return nil
 }
 
-func (core *Core) FavoriteActorsRemove(userId uint64, actorId uint64) error {
-	err := core.crew.RemoveFavoriteActor(userId, actorId)
+func (core *Core) FavoriteActorsRemove(ctx context.Context, userId uint64, actorId uint64) error {
+	err := core.crew.RemoveFavoriteActor(ctx, userId, actorId)
 	if err != nil {
-		core.lg.Error("favorite actors remove error", "err", err.Error())
+		logging.FromContext(ctx, core.lg).Error("favorite actors remove error", "err", err.Error())
 		return fmt.Errorf("favorite actors remove err: %w", err)
 	}
+	core.recorder.FavoriteRemoved(metrics.FavoriteActor)
 
 	return nil
 }
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -10,6 +11,8 @@ import (
 	"time"
 
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/films/mocks"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
+	metricsMocks "github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics/mocks"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/requests"
 	"github.com/golang/mock/gomock"
@@ -24,14 +27,14 @@ func TestGetCalendar(t *testing.T) {
 	expected := &requests.CalendarResponse{MonthName: time.Now().Month().String(), MonthText: "Новинки этого месяца", CurrentDay: uint8(time.Now().Day()), Days: expectedDays}
 
 	mockObj := mocks.NewMockICalendarRepo(mockCtrl)
-	firstCall := mockObj.EXPECT().GetCalendar().Return(expectedDays, nil)
-	mockObj.EXPECT().GetCalendar().After(firstCall).Return(nil, fmt.Errorf("repo_error"))
+	firstCall := mockObj.EXPECT().GetCalendar(gomock.Any()).Return(expectedDays, nil)
+	mockObj.EXPECT().GetCalendar(gomock.Any()).After(firstCall).Return(nil, fmt.Errorf("repo_error"))
 
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 	core := Core{calendar: mockObj, lg: logger}
 
-	result, err := core.GetCalendar()
+	result, err := core.GetCalendar(context.Background())
 	if err != nil {
 		t.Errorf("unexpected error %s", err)
 		return
@@ -41,7 +44,7 @@ func TestGetCalendar(t *testing.T) {
 		return
 	}
 
-	result, err = core.GetCalendar()
+	result, err = core.GetCalendar(context.Background())
 	if err == nil {
 		t.Errorf("wanted error")
 		return
@@ -60,14 +63,14 @@ func TestGetActorsCareer(t *testing.T) {
 	expected := []models.ProfessionItem{expectedProf}
 
 	mockObj := mocks.NewMockIProfessionRepo(mockCtrl)
-	firstCall := mockObj.EXPECT().GetActorsProfessions(uint64(1)).Return(expected, nil)
-	mockObj.EXPECT().GetActorsProfessions(uint64(1)).After(firstCall).Return(nil, fmt.Errorf("repo_error"))
+	firstCall := mockObj.EXPECT().GetActorsProfessions(gomock.Any(), uint64(1)).Return(expected, nil)
+	mockObj.EXPECT().GetActorsProfessions(gomock.Any(), uint64(1)).After(firstCall).Return(nil, fmt.Errorf("repo_error"))
 
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 	core := Core{profession: mockObj, lg: logger}
 
-	result, err := core.GetActorsCareer(1)
+	result, err := core.GetActorsCareer(context.Background(), 1)
 	if err != nil {
 		t.Errorf("unexpected error %s", err)
 		return
@@ -77,7 +80,7 @@ func TestGetActorsCareer(t *testing.T) {
 		return
 	}
 
-	result, err = core.GetActorsCareer(1)
+	result, err = core.GetActorsCareer(context.Background(), 1)
 	if err == nil {
 		t.Errorf("wanted error")
 		return
@@ -95,14 +98,14 @@ func TestGetGenre(t *testing.T) {
 	expected := "g1"
 
 	mockObj := mocks.NewMockIGenreRepo(mockCtrl)
-	firstCall := mockObj.EXPECT().GetGenreById(uint64(1)).Return(expected, nil)
-	mockObj.EXPECT().GetGenreById(uint64(1)).After(firstCall).Return("", fmt.Errorf("repo_error"))
+	firstCall := mockObj.EXPECT().GetGenreById(gomock.Any(), uint64(1)).Return(expected, nil)
+	mockObj.EXPECT().GetGenreById(gomock.Any(), uint64(1)).After(firstCall).Return("", fmt.Errorf("repo_error"))
 
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 	core := Core{genres: mockObj, lg: logger}
 
-	result, err := core.GetGenre(1)
+	result, err := core.GetGenre(context.Background(), 1)
 	if err != nil {
 		t.Errorf("unexpected error %s", err)
 		return
@@ -112,7 +115,7 @@ func TestGetGenre(t *testing.T) {
 		return
 	}
 
-	result, err = core.GetGenre(1)
+	result, err = core.GetGenre(context.Background(), 1)
 	if err == nil {
 		t.Errorf("wanted error")
 		return
@@ -131,15 +134,15 @@ func TestFindFilm(t *testing.T) {
 	expected := []models.FilmItem{expectedFilm}
 
 	mockObj := mocks.NewMockIFilmsRepo(mockCtrl)
-	firstCall := mockObj.EXPECT().FindFilm(string("t"), string("df"), string("dt"), float32(0), float32(10), string(""), nil, nil).Return(expected, nil)
-	mockObj.EXPECT().FindFilm(string("t0"), string("df"), string("dt"), float32(0), float32(10), string(""), nil, nil).After(firstCall).Return(nil, fmt.Errorf("repo_error"))
-	mockObj.EXPECT().FindFilm(string("t10"), string("df"), string("dt"), float32(0), float32(10), string(""), nil, nil).Return([]models.FilmItem{}, nil)
+	firstCall := mockObj.EXPECT().FindFilm(gomock.Any(), string("t"), string("df"), string("dt"), float32(0), float32(10), string(""), nil, nil).Return(expected, nil)
+	mockObj.EXPECT().FindFilm(gomock.Any(), string("t0"), string("df"), string("dt"), float32(0), float32(10), string(""), nil, nil).After(firstCall).Return(nil, fmt.Errorf("repo_error"))
+	mockObj.EXPECT().FindFilm(gomock.Any(), string("t10"), string("df"), string("dt"), float32(0), float32(10), string(""), nil, nil).Return([]models.FilmItem{}, nil)
 
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 	core := Core{films: mockObj, lg: logger}
 
-	result, err := core.FindFilm("t", "df", "dt", 0, 10, "", nil, nil)
+	result, err := core.FindFilm(context.Background(), "t", "df", "dt", 0, 10, "", nil, nil)
 	if err != nil {
 		t.Errorf("unexpected error %s", err)
 		return
@@ -149,7 +152,7 @@ func TestFindFilm(t *testing.T) {
 		return
 	}
 
-	result, err = core.FindFilm("t0", "df", "dt", 0, 10, "", nil, nil)
+	result, err = core.FindFilm(context.Background(), "t0", "df", "dt", 0, 10, "", nil, nil)
 	if err == nil {
 		t.Errorf("wanted error")
 		return
@@ -159,7 +162,7 @@ func TestFindFilm(t *testing.T) {
 		return
 	}
 
-	result, err = core.FindFilm("t10", "df", "dt", 0, 10, "", nil, nil)
+	result, err = core.FindFilm(context.Background(), "t10", "df", "dt", 0, 10, "", nil, nil)
 	if !errors.Is(err, ErrNotFound) {
 		t.Errorf("expected not found")
 		return
@@ -178,15 +181,15 @@ func TestFindActor(t *testing.T) {
 	expected := []models.Character{expectedFilm}
 
 	mockObj := mocks.NewMockICrewRepo(mockCtrl)
-	firstCall := mockObj.EXPECT().FindActor(string("t"), string("bd"), nil, nil, string("")).Return(expected, nil)
-	mockObj.EXPECT().FindActor(string("t"), string("bd"), nil, nil, string("")).After(firstCall).Return(nil, fmt.Errorf("repo_error"))
-	mockObj.EXPECT().FindActor(string("t"), string("bd"), nil, nil, string("")).Return([]models.Character{}, nil)
+	firstCall := mockObj.EXPECT().FindActor(gomock.Any(), string("t"), string("bd"), nil, nil, string("")).Return(expected, nil)
+	mockObj.EXPECT().FindActor(gomock.Any(), string("t"), string("bd"), nil, nil, string("")).After(firstCall).Return(nil, fmt.Errorf("repo_error"))
+	mockObj.EXPECT().FindActor(gomock.Any(), string("t"), string("bd"), nil, nil, string("")).Return([]models.Character{}, nil)
 
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 	core := Core{crew: mockObj, lg: logger}
 
-	result, err := core.FindActor("t", "bd", nil, nil, "")
+	result, err := core.FindActor(context.Background(), "t", "bd", nil, nil, "")
 	if err != nil {
 		t.Errorf("unexpected error %s", err)
 		return
@@ -196,7 +199,7 @@ func TestFindActor(t *testing.T) {
 		return
 	}
 
-	result, err = core.FindActor("t", "bd", nil, nil, "")
+	result, err = core.FindActor(context.Background(), "t", "bd", nil, nil, "")
 	if err == nil {
 		t.Errorf("wanted error")
 		return
@@ -206,7 +209,7 @@ func TestFindActor(t *testing.T) {
 		return
 	}
 
-	result, err = core.FindActor("t", "bd", nil, nil, "")
+	result, err = core.FindActor(context.Background(), "t", "bd", nil, nil, "")
 	if !errors.Is(err, ErrNotFound) {
 		t.Errorf("expected not found")
 		return
@@ -227,19 +230,19 @@ func TestGetFilmsAndGenreTitle(t *testing.T) {
 	expectedGenre := "g1"
 
 	mockObj := mocks.NewMockIFilmsRepo(mockCtrl)
-	mockObj.EXPECT().GetFilms(uint64(1), uint64(1)).Return(expectedFilms, nil)
-	mockObj.EXPECT().GetFilms(uint64(1), uint64(0)).Return(nil, fmt.Errorf("repo_error"))
-	mockObj.EXPECT().GetFilmsByGenre(uint64(10), uint64(1), uint64(1)).Return(expectedFilms, nil)
+	mockObj.EXPECT().GetFilms(gomock.Any(), uint64(1), uint64(1)).Return(expectedFilms, nil)
+	mockObj.EXPECT().GetFilms(gomock.Any(), uint64(1), uint64(0)).Return(nil, fmt.Errorf("repo_error"))
+	mockObj.EXPECT().GetFilmsByGenre(gomock.Any(), uint64(10), uint64(1), uint64(1)).Return(expectedFilms, nil)
 
 	mockGenres := mocks.NewMockIGenreRepo(mockCtrl)
-	mockGenres.EXPECT().GetGenreById(uint64(0)).Return(expectedGenre, nil)
-	mockGenres.EXPECT().GetGenreById(uint64(10)).Return("", fmt.Errorf("repo_error"))
+	mockGenres.EXPECT().GetGenreById(gomock.Any(), uint64(0)).Return(expectedGenre, nil)
+	mockGenres.EXPECT().GetGenreById(gomock.Any(), uint64(10)).Return("", fmt.Errorf("repo_error"))
 
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 	core := Core{films: mockObj, genres: mockGenres, lg: logger}
 
-	films, genre, err := core.GetFilmsAndGenreTitle(0, 1, 1)
+	films, genre, err := core.GetFilmsAndGenreTitle(context.Background(), 0, 1, 1)
 	if err != nil {
 		t.Errorf("unexpected error %s", err)
 		return
@@ -253,7 +256,7 @@ func TestGetFilmsAndGenreTitle(t *testing.T) {
 		return
 	}
 
-	films, genre, err = core.GetFilmsAndGenreTitle(0, 1, 0)
+	films, genre, err = core.GetFilmsAndGenreTitle(context.Background(), 0, 1, 0)
 	if err == nil {
 		t.Errorf("wanted error")
 		return
@@ -267,7 +270,7 @@ func TestGetFilmsAndGenreTitle(t *testing.T) {
 		return
 	}
 
-	films, genre, err = core.GetFilmsAndGenreTitle(10, 1, 1)
+	films, genre, err = core.GetFilmsAndGenreTitle(context.Background(), 10, 1, 1)
 	if err == nil {
 		t.Errorf("wanted error")
 		return
@@ -292,20 +295,20 @@ func TestGetActorInfo(t *testing.T) {
 	expected := &requests.ActorResponse{Name: expectedActor.Name, Career: expectedCareer}
 
 	mockObj := mocks.NewMockICrewRepo(mockCtrl)
-	firstCall := mockObj.EXPECT().GetActor(uint64(1)).Return(expectedActor, nil)
-	mockObj.EXPECT().GetActor(uint64(2)).After(firstCall).Return(nil, fmt.Errorf("repo_error"))
-	mockObj.EXPECT().GetActor(uint64(3)).Return(&models.CrewItem{}, nil)
-	mockObj.EXPECT().GetActor(uint64(4)).Return(expectedActor, nil)
+	firstCall := mockObj.EXPECT().GetActor(gomock.Any(), uint64(1)).Return(expectedActor, nil)
+	mockObj.EXPECT().GetActor(gomock.Any(), uint64(2)).After(firstCall).Return(nil, fmt.Errorf("repo_error"))
+	mockObj.EXPECT().GetActor(gomock.Any(), uint64(3)).Return(&models.CrewItem{}, nil)
+	mockObj.EXPECT().GetActor(gomock.Any(), uint64(4)).Return(expectedActor, nil)
 
 	mockProf := mocks.NewMockIProfessionRepo(mockCtrl)
-	mockProf.EXPECT().GetActorsProfessions(uint64(1)).Return(expectedCareer, nil)
-	mockProf.EXPECT().GetActorsProfessions(uint64(4)).Return(nil, fmt.Errorf("repo_error"))
+	mockProf.EXPECT().GetActorsProfessions(gomock.Any(), uint64(1)).Return(expectedCareer, nil)
+	mockProf.EXPECT().GetActorsProfessions(gomock.Any(), uint64(4)).Return(nil, fmt.Errorf("repo_error"))
 
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 	core := Core{crew: mockObj, profession: mockProf, lg: logger}
 
-	result, err := core.GetActorInfo(1)
+	result, err := core.GetActorInfo(context.Background(), 1)
 	if err != nil {
 		t.Errorf("unexpected error %s", err)
 		return
@@ -315,7 +318,7 @@ func TestGetActorInfo(t *testing.T) {
 		return
 	}
 
-	result, err = core.GetActorInfo(2)
+	result, err = core.GetActorInfo(context.Background(), 2)
 	if err == nil {
 		t.Errorf("wanted error")
 		return
@@ -325,7 +328,7 @@ func TestGetActorInfo(t *testing.T) {
 		return
 	}
 
-	result, err = core.GetActorInfo(3)
+	result, err = core.GetActorInfo(context.Background(), 3)
 	if !errors.Is(err, ErrNotFound) {
 		t.Errorf("expected not found")
 		return
@@ -335,7 +338,7 @@ func TestGetActorInfo(t *testing.T) {
 		return
 	}
 
-	result, err = core.GetActorInfo(4)
+	result, err = core.GetActorInfo(context.Background(), 4)
 	if err == nil {
 		t.Errorf("wanted error")
 		return
@@ -372,32 +375,32 @@ func TestGetFilmInfo(t *testing.T) {
 		Number:     expectedNumber}
 
 	mockFilm := mocks.NewMockIFilmsRepo(mockCtrl)
-	notFound := mockFilm.EXPECT().GetFilm(uint64(1)).Return(&models.FilmItem{}, nil).Times(1)
-	withErr := mockFilm.EXPECT().GetFilm(uint64(1)).Return(nil, fmt.Errorf("repo_error")).Times(1).After(notFound)
-	mockFilm.EXPECT().GetFilm(uint64(1)).Return(expectedFilm, nil).After(withErr).AnyTimes()
+	notFound := mockFilm.EXPECT().GetFilm(gomock.Any(), uint64(1)).Return(&models.FilmItem{}, nil).Times(1)
+	withErr := mockFilm.EXPECT().GetFilm(gomock.Any(), uint64(1)).Return(nil, fmt.Errorf("repo_error")).Times(1).After(notFound)
+	mockFilm.EXPECT().GetFilm(gomock.Any(), uint64(1)).Return(expectedFilm, nil).After(withErr).AnyTimes()
 
 	mockGenres := mocks.NewMockIGenreRepo(mockCtrl)
-	withErr = mockGenres.EXPECT().GetFilmGenres(uint64(1)).Return(nil, fmt.Errorf("repo_error")).Times(1)
-	mockGenres.EXPECT().GetFilmGenres(uint64(1)).Return(expectedGenres, nil).AnyTimes().After(withErr)
+	withErr = mockGenres.EXPECT().GetFilmGenres(gomock.Any(), uint64(1)).Return(nil, fmt.Errorf("repo_error")).Times(1)
+	mockGenres.EXPECT().GetFilmGenres(gomock.Any(), uint64(1)).Return(expectedGenres, nil).AnyTimes().After(withErr)
 
-	withErr = mockFilm.EXPECT().GetFilmRating(uint64(1)).Return(float64(0), uint64(0), fmt.Errorf("repo_error")).Times(1)
-	mockFilm.EXPECT().GetFilmRating(uint64(1)).Return(expectedRating, expectedNumber, nil).AnyTimes().After(withErr)
+	withErr = mockFilm.EXPECT().GetFilmRating(gomock.Any(), uint64(1)).Return(float64(0), uint64(0), fmt.Errorf("repo_error")).Times(1)
+	mockFilm.EXPECT().GetFilmRating(gomock.Any(), uint64(1)).Return(expectedRating, expectedNumber, nil).AnyTimes().After(withErr)
 
 	mockCrew := mocks.NewMockICrewRepo(mockCtrl)
-	withErr = mockCrew.EXPECT().GetFilmDirectors(uint64(1)).Return(nil, fmt.Errorf("repo_error")).Times(1)
-	mockCrew.EXPECT().GetFilmDirectors(uint64(1)).Return(expectedCrew, nil).AnyTimes().After(withErr)
+	withErr = mockCrew.EXPECT().GetFilmDirectors(gomock.Any(), uint64(1)).Return(nil, fmt.Errorf("repo_error")).Times(1)
+	mockCrew.EXPECT().GetFilmDirectors(gomock.Any(), uint64(1)).Return(expectedCrew, nil).AnyTimes().After(withErr)
 
-	withErr = mockCrew.EXPECT().GetFilmScenarists(uint64(1)).Return(nil, fmt.Errorf("repo_error")).Times(1)
-	mockCrew.EXPECT().GetFilmScenarists(uint64(1)).Return(expectedCrew, nil).AnyTimes().After(withErr)
+	withErr = mockCrew.EXPECT().GetFilmScenarists(gomock.Any(), uint64(1)).Return(nil, fmt.Errorf("repo_error")).Times(1)
+	mockCrew.EXPECT().GetFilmScenarists(gomock.Any(), uint64(1)).Return(expectedCrew, nil).AnyTimes().After(withErr)
 
-	withErr = mockCrew.EXPECT().GetFilmCharacters(uint64(1)).Return(nil, fmt.Errorf("repo_error")).Times(1)
-	mockCrew.EXPECT().GetFilmCharacters(uint64(1)).Return(expectedCharacters, nil).AnyTimes().After(withErr)
+	withErr = mockCrew.EXPECT().GetFilmCharacters(gomock.Any(), uint64(1)).Return(nil, fmt.Errorf("repo_error")).Times(1)
+	mockCrew.EXPECT().GetFilmCharacters(gomock.Any(), uint64(1)).Return(expectedCharacters, nil).AnyTimes().After(withErr)
 
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 	core := Core{films: mockFilm, genres: mockGenres, crew: mockCrew, lg: logger}
 
-	result, err := core.GetFilmInfo(1)
+	result, err := core.GetFilmInfo(context.Background(), 1)
 	if !errors.Is(err, ErrNotFound) {
 		t.Errorf("wanted not found error")
 		return
@@ -408,7 +411,7 @@ func TestGetFilmInfo(t *testing.T) {
 	}
 
 	for i := 0; i < 6; i++ {
-		result, err = core.GetFilmInfo(1)
+		result, err = core.GetFilmInfo(context.Background(), 1)
 		if err == nil {
 			t.Errorf("wanted error")
 			return
@@ -419,7 +422,7 @@ func TestGetFilmInfo(t *testing.T) {
 		}
 	}
 
-	result, err = core.GetFilmInfo(1)
+	result, err = core.GetFilmInfo(context.Background(), 1)
 	if err != nil {
 		t.Errorf("wanted no errors")
 		return
@@ -438,14 +441,14 @@ func TestFavoriteFilms(t *testing.T) {
 	expected := []models.FilmItem{expectedFilm}
 
 	mockObj := mocks.NewMockIFilmsRepo(mockCtrl)
-	firstCall := mockObj.EXPECT().GetFavoriteFilms(uint64(1), uint64(1), uint64(1)).Return(expected, nil)
-	mockObj.EXPECT().GetFavoriteFilms(uint64(1), uint64(1), uint64(1)).After(firstCall).Return(nil, fmt.Errorf("repo_error"))
+	firstCall := mockObj.EXPECT().GetFavoriteFilms(gomock.Any(), uint64(1), uint64(1), uint64(1)).Return(expected, nil)
+	mockObj.EXPECT().GetFavoriteFilms(gomock.Any(), uint64(1), uint64(1), uint64(1)).After(firstCall).Return(nil, fmt.Errorf("repo_error"))
 
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 	core := Core{films: mockObj, lg: logger}
 
-	result, err := core.FavoriteFilms(1, 1, 1)
+	result, err := core.FavoriteFilms(context.Background(), 1, 1, 1)
 	if err != nil {
 		t.Errorf("unexpected error %s", err)
 		return
@@ -455,7 +458,7 @@ func TestFavoriteFilms(t *testing.T) {
 		return
 	}
 
-	result, err = core.FavoriteFilms(1, 1, 1)
+	result, err = core.FavoriteFilms(context.Background(), 1, 1, 1)
 	if err == nil {
 		t.Errorf("wanted error")
 		return
@@ -471,20 +474,23 @@ func TestFavoriteFilmsRemove(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockObj := mocks.NewMockIFilmsRepo(mockCtrl)
-	firstCall := mockObj.EXPECT().RemoveFavoriteFilm(uint64(1), uint64(1)).Return(nil)
-	mockObj.EXPECT().RemoveFavoriteFilm(uint64(1), uint64(1)).After(firstCall).Return(fmt.Errorf("repo_error"))
+	firstCall := mockObj.EXPECT().RemoveFavoriteFilm(gomock.Any(), uint64(1), uint64(1)).Return(nil)
+	mockObj.EXPECT().RemoveFavoriteFilm(gomock.Any(), uint64(1), uint64(1)).After(firstCall).Return(fmt.Errorf("repo_error"))
 
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
-	core := Core{films: mockObj, lg: logger}
+	recorder := metricsMocks.NewMockIFilmsRecorder(mockCtrl)
+	recorder.EXPECT().FavoriteRemoved(metrics.FavoriteFilm).Times(1)
+
+	core := Core{films: mockObj, lg: logger, recorder: recorder}
 
-	err := core.FavoriteFilmsRemove(1, 1)
+	err := core.FavoriteFilmsRemove(context.Background(), 1, 1)
 	if err != nil {
 		t.Errorf("unexpected error %s", err)
 		return
 	}
 
-	err = core.FavoriteFilmsRemove(1, 1)
+	err = core.FavoriteFilmsRemove(context.Background(), 1, 1)
 	if err == nil {
 		t.Errorf("wanted error")
 		return
@@ -496,32 +502,35 @@ func TestFavoriteFilmsAdd(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockObj := mocks.NewMockIFilmsRepo(mockCtrl)
-	mockObj.EXPECT().CheckFilm(uint64(1), uint64(1)).Return(true, nil).Times(1).Times(1)
-	mockObj.EXPECT().CheckFilm(uint64(1), uint64(1)).Return(false, fmt.Errorf("repo_err")).Times(1)
-	mockObj.EXPECT().CheckFilm(uint64(1), uint64(1)).Return(false, nil).Times(2)
+	mockObj.EXPECT().CheckFilm(gomock.Any(), uint64(1), uint64(1)).Return(true, nil).Times(1).Times(1)
+	mockObj.EXPECT().CheckFilm(gomock.Any(), uint64(1), uint64(1)).Return(false, fmt.Errorf("repo_err")).Times(1)
+	mockObj.EXPECT().CheckFilm(gomock.Any(), uint64(1), uint64(1)).Return(false, nil).Times(2)
 
-	mockObj.EXPECT().AddFavoriteFilm(uint64(1), uint64(1)).Return(fmt.Errorf("repo_error")).Times(1)
-	mockObj.EXPECT().AddFavoriteFilm(uint64(1), uint64(1)).Return(nil).Times(1)
+	mockObj.EXPECT().AddFavoriteFilm(gomock.Any(), uint64(1), uint64(1)).Return(fmt.Errorf("repo_error")).Times(1)
+	mockObj.EXPECT().AddFavoriteFilm(gomock.Any(), uint64(1), uint64(1)).Return(nil).Times(1)
 
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
-	core := Core{films: mockObj, lg: logger}
+	recorder := metricsMocks.NewMockIFilmsRecorder(mockCtrl)
+	recorder.EXPECT().FavoriteAdded(metrics.FavoriteFilm).Times(1)
 
-	err := core.FavoriteFilmsAdd(1, 1)
+	core := Core{films: mockObj, lg: logger, recorder: recorder}
+
+	err := core.FavoriteFilmsAdd(context.Background(), 1, 1)
 	if !errors.Is(err, ErrFoundFavorite) {
 		t.Errorf("expected found error, got %s", err)
 		return
 	}
 
 	for i := 0; i < 2; i++ {
-		err = core.FavoriteFilmsAdd(1, 1)
+		err = core.FavoriteFilmsAdd(context.Background(), 1, 1)
 		if err == nil {
 			t.Errorf("wanted error")
 			return
 		}
 	}
 
-	err = core.FavoriteFilmsAdd(1, 1)
+	err = core.FavoriteFilmsAdd(context.Background(), 1, 1)
 	if err != nil {
 		t.Errorf("unexpected error")
 		return
@@ -533,16 +542,19 @@ func TestAddRating(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockObj := mocks.NewMockIFilmsRepo(mockCtrl)
-	mockObj.EXPECT().HasUsersRating(uint64(10), uint64(1)).Return(true, nil).Times(1).Times(1)
-	mockObj.EXPECT().HasUsersRating(uint64(0), uint64(0)).Return(false, fmt.Errorf("repo_error")).Times(1)
-	mockObj.EXPECT().HasUsersRating(uint64(1), uint64(1)).Return(false, nil).Times(2)
+	mockObj.EXPECT().HasUsersRating(gomock.Any(), uint64(10), uint64(1)).Return(true, nil).Times(1).Times(1)
+	mockObj.EXPECT().HasUsersRating(gomock.Any(), uint64(0), uint64(0)).Return(false, fmt.Errorf("repo_error")).Times(1)
+	mockObj.EXPECT().HasUsersRating(gomock.Any(), uint64(1), uint64(1)).Return(false, nil).Times(2)
 
-	mockObj.EXPECT().AddRating(uint64(1), uint64(1), uint16(0)).Return(fmt.Errorf("repo_error")).Times(1)
-	mockObj.EXPECT().AddRating(uint64(1), uint64(1), uint16(5)).Return(nil).Times(1)
+	mockObj.EXPECT().AddRating(gomock.Any(), uint64(1), uint64(1), uint16(0)).Return(fmt.Errorf("repo_error")).Times(1)
+	mockObj.EXPECT().AddRating(gomock.Any(), uint64(1), uint64(1), uint16(5)).Return(nil).Times(1)
 
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
-	core := Core{films: mockObj, lg: logger}
+	recorder := metricsMocks.NewMockIFilmsRecorder(mockCtrl)
+	recorder.EXPECT().RatingAdded(uint16(5)).Times(1)
+
+	core := Core{films: mockObj, lg: logger, recorder: recorder}
 
 	testCases := map[string]struct {
 		filmId uint64
@@ -580,7 +592,7 @@ func TestAddRating(t *testing.T) {
 	}
 
 	for _, curr := range testCases {
-		result, err := core.AddRating(curr.filmId, curr.userId, curr.rating)
+		result, err := core.AddRating(context.Background(), curr.filmId, curr.userId, curr.rating)
 		if curr.hasErr && err == nil {
 			t.Errorf("unexpected err result")
 			return
@@ -609,18 +621,18 @@ func TestAddFilm(t *testing.T) {
 	mockCrew := mocks.NewMockICrewRepo(mockCtrl)
 	mockGenres := mocks.NewMockIGenreRepo(mockCtrl)
 
-	mockFilm.EXPECT().AddFilm(models.FilmItem{}).Return(fmt.Errorf("repo_err")).Times(1)
-	mockFilm.EXPECT().AddFilm(badFilm).Return(nil).Times(1)
-	mockFilm.EXPECT().AddFilm(film).Return(nil).Times(3)
+	mockFilm.EXPECT().AddFilm(gomock.Any(), models.FilmItem{}).Return(fmt.Errorf("repo_err")).Times(1)
+	mockFilm.EXPECT().AddFilm(gomock.Any(), badFilm).Return(nil).Times(1)
+	mockFilm.EXPECT().AddFilm(gomock.Any(), film).Return(nil).Times(3)
 
-	mockFilm.EXPECT().GetFilmId(badFilm.Title).Return(uint64(0), fmt.Errorf("repo_err")).Times(1)
-	mockFilm.EXPECT().GetFilmId(film.Title).Return(uint64(1), nil).Times(3)
+	mockFilm.EXPECT().GetFilmId(gomock.Any(), badFilm.Title).Return(uint64(0), fmt.Errorf("repo_err")).Times(1)
+	mockFilm.EXPECT().GetFilmId(gomock.Any(), film.Title).Return(uint64(1), nil).Times(3)
 
-	mockGenres.EXPECT().AddFilm(nil, uint64(1)).Return(fmt.Errorf("repo_err")).Times(1)
-	mockGenres.EXPECT().AddFilm(genres, uint64(1)).Return(nil).Times(2)
+	mockGenres.EXPECT().AddFilm(gomock.Any(), nil, uint64(1)).Return(fmt.Errorf("repo_err")).Times(1)
+	mockGenres.EXPECT().AddFilm(gomock.Any(), genres, uint64(1)).Return(nil).Times(2)
 
-	mockCrew.EXPECT().AddFilm(nil, uint64(1)).Return(fmt.Errorf("repo_err")).Times(1)
-	mockCrew.EXPECT().AddFilm(actors, uint64(1)).Return(nil).Times(1)
+	mockCrew.EXPECT().AddFilm(gomock.Any(), nil, uint64(1)).Return(fmt.Errorf("repo_err")).Times(1)
+	mockCrew.EXPECT().AddFilm(gomock.Any(), actors, uint64(1)).Return(nil).Times(1)
 
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
@@ -659,7 +671,7 @@ func TestAddFilm(t *testing.T) {
 	}
 
 	for _, curr := range testCases {
-		err := core.AddFilm(curr.film, curr.genres, curr.actors)
+		err := core.AddFilm(context.Background(), curr.film, curr.genres, curr.actors)
 		if curr.hasErr && err == nil {
 			t.Errorf("unexpected err result")
 			return
@@ -675,14 +687,14 @@ func TestFavoriteActors(t *testing.T) {
 	expected := []models.Character{expectedFilm}
 
 	mockObj := mocks.NewMockICrewRepo(mockCtrl)
-	firstCall := mockObj.EXPECT().GetFavoriteActors(uint64(1), uint64(1), uint64(1)).Return(expected, nil)
-	mockObj.EXPECT().GetFavoriteActors(uint64(1), uint64(1), uint64(1)).After(firstCall).Return(nil, fmt.Errorf("repo_error"))
+	firstCall := mockObj.EXPECT().GetFavoriteActors(gomock.Any(), uint64(1), uint64(1), uint64(1)).Return(expected, nil)
+	mockObj.EXPECT().GetFavoriteActors(gomock.Any(), uint64(1), uint64(1), uint64(1)).After(firstCall).Return(nil, fmt.Errorf("repo_error"))
 
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
 	core := Core{crew: mockObj, lg: logger}
 
-	result, err := core.FavoriteActors(1, 1, 1)
+	result, err := core.FavoriteActors(context.Background(), 1, 1, 1)
 	if err != nil {
 		t.Errorf("unexpected error %s", err)
 		return
@@ -692,7 +704,7 @@ func TestFavoriteActors(t *testing.T) {
 		return
 	}
 
-	result, err = core.FavoriteActors(1, 1, 1)
+	result, err = core.FavoriteActors(context.Background(), 1, 1, 1)
 	if err == nil {
 		t.Errorf("wanted error")
 		return
@@ -708,20 +720,23 @@ func TestFavoriteActorsRemove(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockObj := mocks.NewMockICrewRepo(mockCtrl)
-	firstCall := mockObj.EXPECT().RemoveFavoriteActor(uint64(1), uint64(1)).Return(nil)
-	mockObj.EXPECT().RemoveFavoriteActor(uint64(1), uint64(1)).After(firstCall).Return(fmt.Errorf("repo_error"))
+	firstCall := mockObj.EXPECT().RemoveFavoriteActor(gomock.Any(), uint64(1), uint64(1)).Return(nil)
+	mockObj.EXPECT().RemoveFavoriteActor(gomock.Any(), uint64(1), uint64(1)).After(firstCall).Return(fmt.Errorf("repo_error"))
 
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
-	core := Core{crew: mockObj, lg: logger}
+	recorder := metricsMocks.NewMockIFilmsRecorder(mockCtrl)
+	recorder.EXPECT().FavoriteRemoved(metrics.FavoriteActor).Times(1)
+
+	core := Core{crew: mockObj, lg: logger, recorder: recorder}
 
-	err := core.FavoriteActorsRemove(1, 1)
+	err := core.FavoriteActorsRemove(context.Background(), 1, 1)
 	if err != nil {
 		t.Errorf("unexpected error %s", err)
 		return
 	}
 
-	err = core.FavoriteActorsRemove(1, 1)
+	err = core.FavoriteActorsRemove(context.Background(), 1, 1)
 	if err == nil {
 		t.Errorf("wanted error")
 		return
@@ -733,32 +748,35 @@ func TestFavoriteActorsAdd(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	mockObj := mocks.NewMockICrewRepo(mockCtrl)
-	mockObj.EXPECT().CheckActor(uint64(1), uint64(1)).Return(true, nil).Times(1).Times(1)
-	mockObj.EXPECT().CheckActor(uint64(1), uint64(1)).Return(false, fmt.Errorf("repo_err")).Times(1)
-	mockObj.EXPECT().CheckActor(uint64(1), uint64(1)).Return(false, nil).Times(2)
+	mockObj.EXPECT().CheckActor(gomock.Any(), uint64(1), uint64(1)).Return(true, nil).Times(1).Times(1)
+	mockObj.EXPECT().CheckActor(gomock.Any(), uint64(1), uint64(1)).Return(false, fmt.Errorf("repo_err")).Times(1)
+	mockObj.EXPECT().CheckActor(gomock.Any(), uint64(1), uint64(1)).Return(false, nil).Times(2)
 
-	mockObj.EXPECT().AddFavoriteActor(uint64(1), uint64(1)).Return(fmt.Errorf("repo_error")).Times(1)
-	mockObj.EXPECT().AddFavoriteActor(uint64(1), uint64(1)).Return(nil).Times(1)
+	mockObj.EXPECT().AddFavoriteActor(gomock.Any(), uint64(1), uint64(1)).Return(fmt.Errorf("repo_error")).Times(1)
+	mockObj.EXPECT().AddFavoriteActor(gomock.Any(), uint64(1), uint64(1)).Return(nil).Times(1)
 
 	var buff bytes.Buffer
 	logger := slog.New(slog.NewJSONHandler(&buff, nil))
-	core := Core{crew: mockObj, lg: logger}
+	recorder := metricsMocks.NewMockIFilmsRecorder(mockCtrl)
+	recorder.EXPECT().FavoriteAdded(metrics.FavoriteActor).Times(1)
+
+	core := Core{crew: mockObj, lg: logger, recorder: recorder}
 
-	err := core.FavoriteActorsAdd(1, 1)
+	err := core.FavoriteActorsAdd(context.Background(), 1, 1)
 	if !errors.Is(err, ErrFoundFavorite) {
 		t.Errorf("expected found error, got %s", err)
 		return
 	}
 
 	for i := 0; i < 2; i++ {
-		err = core.FavoriteActorsAdd(1, 1)
+		err = core.FavoriteActorsAdd(context.Background(), 1, 1)
 		if err == nil {
 			t.Errorf("wanted error")
 			return
 		}
 	}
 
-	err = core.FavoriteActorsAdd(1, 1)
+	err = core.FavoriteActorsAdd(context.Background(), 1, 1)
 	if err != nil {
 		t.Errorf("unexpected error")
 		return
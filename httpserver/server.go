@@ -0,0 +1,96 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/middleware"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/tracing"
+)
+
+// Server serves the API of a service behind the shared middleware, and the
+// admin endpoints apart on their own, by default loopback, address.
+type Server struct {
+	lg     *slog.Logger
+	server *http.Server
+	admin  *http.Server
+	limit  *middleware.BodyLimit
+	al     *middleware.AccessLog
+	levels *logging.Levels
+}
+
+// GetServer adds the metrics, SLO and health endpoints to mx. The bodies of
+// the multipart routes are limited by cfg.MultipartLimit.
+func GetServer(mx *http.ServeMux, address string, adminAddress string, cfg configs.HttpCfg, lg *slog.Logger, mt *metrics.Metrics, al *middleware.AccessLog, levels *logging.Levels, multipart ...string) *Server {
+	mx.Handle("/metrics", mt.Handler())
+	mx.Handle("/slo", mt.SLO().Handler())
+	mx.Handle("/slo/rules", mt.SLO().RulesHandler())
+	mx.Handle("/healthz", mt.Health().LiveHandler())
+	mx.Handle("/readyz", mt.Health().ReadyHandler())
+
+	s := &Server{
+		lg:     lg.With("module", "server"),
+		limit:  middleware.GetBodyLimit(cfg, lg, multipart...),
+		al:     al,
+		levels: levels,
+	}
+	s.server = &http.Server{
+		Addr:              address,
+		Handler:           logging.Middleware(tracing.Middleware(mx, al.Middleware(mx, mt.Middleware(mx, s.limit.Middleware(mx, mx))))),
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+
+	admin := http.NewServeMux()
+	admin.Handle("/admin/log/level", levels.Handler())
+	s.admin = &http.Server{
+		Addr:              adminAddress,
+		Handler:           logging.Middleware(admin),
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+	}
+	return s
+}
+
+func (s *Server) ListenAndServe() error {
+	err := s.server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.lg.Error("listen and serve error", "err", err.Error())
+		return fmt.Errorf("listen and serve error: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+func (s *Server) Admin() *http.Server {
+	return s.admin
+}
+
+// Limit is the body limit of route.
+func (s *Server) Limit(route string) int64 {
+	return s.limit.Limit(route)
+}
+
+// Reconfigure applies the reloadable settings. They are all checked first, a
+// bad one leaves every setting as it was.
+func (s *Server) Reconfigure(cfg configs.Shared) error {
+	if err := errors.Join(s.al.Check(cfg.AccessLog), s.levels.Check(cfg.Log)); err != nil {
+		return err
+	}
+	s.limit.Reconfigure(cfg.Http)
+	if err := s.al.Reconfigure(cfg.AccessLog); err != nil {
+		return err
+	}
+	return s.levels.Reconfigure(cfg.Log)
+}
@@ -0,0 +1,80 @@
+package httpserver
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestReconfigure(t *testing.T) {
+	mt, err := metrics.NewMetrics("test", prometheus.NewRegistry(), configs.MetricsCfg{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	al, err := middleware.GetAccessLog(configs.AccessLogCfg{Enabled: true, Output: "stderr"}, configs.RedactCfg{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer al.Close()
+	levels, err := logging.GetLevels(configs.LogCfg{Level: "info"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lg := slog.New(slog.NewTextHandler(io.Discard, nil))
+	s := GetServer(http.NewServeMux(), ":0", ":0", configs.HttpCfg{BodyLimit: 10}, lg, mt, al, levels)
+
+	testCases := map[string]struct {
+		cfg   configs.Shared
+		err   bool
+		limit int64
+	}{
+		"Bad access log": {
+			cfg: configs.Shared{
+				Http:      configs.HttpCfg{BodyLimit: 20},
+				AccessLog: configs.AccessLogCfg{Sampling: map[string]float64{"2xx": 2}},
+				Log:       configs.LogCfg{Level: "debug"},
+			},
+			err:   true,
+			limit: 10,
+		},
+		"Bad level": {
+			cfg: configs.Shared{
+				Http: configs.HttpCfg{BodyLimit: 20},
+				Log:  configs.LogCfg{Level: "loud"},
+			},
+			err:   true,
+			limit: 10,
+		},
+		"Ok": {
+			cfg: configs.Shared{
+				Http: configs.HttpCfg{BodyLimit: 20},
+				Log:  configs.LogCfg{Level: "debug"},
+			},
+			limit: 20,
+		},
+	}
+
+	for _, name := range []string{"Bad access log", "Bad level", "Ok"} {
+		tc := testCases[name]
+		t.Run(name, func(t *testing.T) {
+			err := s.Reconfigure(tc.cfg)
+			if (err != nil) != tc.err {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if limit := s.Limit("/api/v1/find"); limit != tc.limit {
+				t.Errorf("limit: got %d, want %d", limit, tc.limit)
+			}
+		})
+	}
+	if levels.Level() != slog.LevelDebug {
+		t.Errorf("level: got %v, want debug", levels.Level())
+	}
+}
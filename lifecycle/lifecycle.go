@@ -0,0 +1,161 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Server is drained by Shutdown, after which ListenAndServe returns nil or
+// http.ErrServerClosed.
+type Server interface {
+	ListenAndServe() error
+	Shutdown(ctx context.Context) error
+}
+
+type server struct {
+	Server
+	name string
+}
+
+type closer struct {
+	name  string
+	close func() error
+}
+
+// Manager runs the servers of a service until SIGINT, SIGTERM or the first
+// failure, drains them within the shutdown timeout and then closes the
+// resources in the reverse order they were registered in.
+type Manager struct {
+	lg      *slog.Logger
+	timeout atomic.Int64
+	servers []server
+	closers []closer
+
+	stop      chan struct{}
+	stopOnce  sync.Once
+	closeOnce sync.Once
+}
+
+func GetManager(lg *slog.Logger, timeout time.Duration) *Manager {
+	m := &Manager{
+		lg:   lg.With("module", "lifecycle"),
+		stop: make(chan struct{}),
+	}
+	m.SetTimeout(timeout)
+	return m
+}
+
+// SetTimeout changes the shutdown timeout, it is read when the drain starts.
+func (m *Manager) SetTimeout(timeout time.Duration) {
+	m.timeout.Store(int64(timeout))
+}
+
+func (m *Manager) Serve(name string, s Server) {
+	m.servers = append(m.servers, server{Server: s, name: name})
+}
+
+// OnClose registers a resource to close once the servers are drained, such
+// as a sql.DB or a Redis client whose checks have to stop first.
+func (m *Manager) OnClose(name string, close func() error) {
+	m.closers = append(m.closers, closer{name: name, close: close})
+}
+
+// Stop shuts the service down as a signal would.
+func (m *Manager) Stop() {
+	m.stopOnce.Do(func() { close(m.stop) })
+}
+
+// Run blocks until the service is shut down and returns the error of the
+// server that failed first, if any.
+func (m *Manager) Run() error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	errs := make(chan error, len(m.servers))
+	for _, s := range m.servers {
+		go func(s server) {
+			err := s.ListenAndServe()
+			if errors.Is(err, http.ErrServerClosed) {
+				err = nil
+			}
+			if err != nil {
+				err = fmt.Errorf("%s: %w", s.name, err)
+			}
+			errs <- err
+		}(s)
+	}
+
+	var err error
+	running := len(m.servers)
+	select {
+	case sig := <-signals:
+		m.lg.Info("shutting down", "signal", sig.String())
+	case <-m.stop:
+		m.lg.Info("shutting down")
+	case err = <-errs:
+		running--
+		if err != nil {
+			m.lg.Error("server failed, shutting down", "err", err.Error())
+		} else {
+			m.lg.Warn("server stopped, shutting down")
+		}
+	}
+	// A second signal kills the service without waiting for the drain.
+	signal.Stop(signals)
+
+	timeout := time.Duration(m.timeout.Load())
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, s := range m.servers {
+		wg.Add(1)
+		go func(s server) {
+			defer wg.Done()
+			if err := s.Shutdown(ctx); err != nil {
+				m.lg.Error("server shutdown error", "server", s.name, "err", err.Error())
+			}
+		}(s)
+	}
+	wg.Wait()
+
+wait:
+	for ; running > 0; running-- {
+		select {
+		case serveErr := <-errs:
+			if err == nil {
+				err = serveErr
+			}
+		case <-ctx.Done():
+			m.lg.Error("servers did not stop in time", "timeout", timeout.String())
+			break wait
+		}
+	}
+
+	m.Close()
+	m.lg.Info("shut down")
+	return err
+}
+
+// Close closes the registered resources in reverse order, only the first
+// call does. Run closes them after the drain, a service failing to start
+// defers Close to release what it opened so far.
+func (m *Manager) Close() {
+	m.closeOnce.Do(func() {
+		for i := len(m.closers) - 1; i >= 0; i-- {
+			if err := m.closers[i].close(); err != nil {
+				m.lg.Error("close error", "resource", m.closers[i].name, "err", err.Error())
+			}
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// blockingServer serves until it is shut down, or fails with err right away.
+type blockingServer struct {
+	err     error
+	stopped chan struct{}
+	drain   time.Duration
+	events  *[]string
+	name    string
+}
+
+func (s *blockingServer) ListenAndServe() error {
+	if s.err != nil {
+		return s.err
+	}
+	<-s.stopped
+	return http.ErrServerClosed
+}
+
+func (s *blockingServer) Shutdown(ctx context.Context) error {
+	defer close(s.stopped)
+	select {
+	case <-time.After(s.drain):
+		*s.events = append(*s.events, "shutdown "+s.name)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestManager(t *testing.T) {
+	testCases := map[string]struct {
+		stop   func(m *Manager)
+		err    error
+		drain  time.Duration
+		events []string
+	}{
+		"Stop": {
+			stop:   func(m *Manager) { m.Stop() },
+			events: []string{"shutdown http", "close redis", "close db"},
+		},
+		"Signal": {
+			stop: func(m *Manager) {
+				// Keeps the test binary alive should Run not be listening yet.
+				signal.Notify(make(chan os.Signal, 1), syscall.SIGTERM)
+				syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+			},
+			events: []string{"shutdown http", "close redis", "close db"},
+		},
+		"Server fails": {
+			stop:   func(m *Manager) {},
+			err:    errors.New("address already in use"),
+			events: []string{"shutdown http", "close redis", "close db"},
+		},
+		"Drain deadline": {
+			stop:   func(m *Manager) { m.Stop() },
+			drain:  time.Second,
+			events: []string{"close redis", "close db"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var events []string
+			m := GetManager(slog.New(slog.NewTextHandler(io.Discard, nil)), 50*time.Millisecond)
+			m.Serve("http", &blockingServer{err: tc.err, stopped: make(chan struct{}), drain: tc.drain, events: &events, name: "http"})
+			m.OnClose("db", func() error {
+				events = append(events, "close db")
+				return nil
+			})
+			m.OnClose("redis", func() error {
+				events = append(events, "close redis")
+				return errors.New("already closed")
+			})
+
+			done := make(chan error)
+			go func() { done <- m.Run() }()
+			time.Sleep(10 * time.Millisecond)
+			tc.stop(m)
+
+			select {
+			case err := <-done:
+				if (err != nil) != (tc.err != nil) || (err != nil && !strings.Contains(err.Error(), "http: address already in use")) {
+					t.Errorf("unexpected error: %v", err)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("manager did not stop")
+			}
+			if strings.Join(events, ", ") != strings.Join(tc.events, ", ") {
+				t.Errorf("events %v, expected %v", events, tc.events)
+			}
+		})
+	}
+}
+
+func TestManagerClose(t *testing.T) {
+	var events []string
+	m := GetManager(slog.New(slog.NewTextHandler(io.Discard, nil)), 50*time.Millisecond)
+	m.OnClose("db", func() error {
+		events = append(events, "close db")
+		return nil
+	})
+	m.OnClose("redis", func() error {
+		events = append(events, "close redis")
+		return nil
+	})
+
+	m.Close()
+	m.Close()
+	if strings.Join(events, ", ") != "close redis, close db" {
+		t.Errorf("events %v, expected each resource closed once in reverse order", events)
+	}
+}
@@ -0,0 +1,358 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+)
+
+const (
+	defaultLevelTTL = 10 * time.Minute
+	// maxLevelTTL bounds the overrides set over HTTP, they always expire.
+	maxLevelTTL = 24 * time.Hour
+)
+
+type override struct {
+	level   slog.Level
+	expires *time.Time
+	timer   *time.Timer
+}
+
+// Levels decides which records reach the sink. The service level lives in a
+// slog.LevelVar; loggers scoped with lg.With("module", ...) may be overridden
+// per module. Runtime changes revert to the configured levels after a TTL.
+type Levels struct {
+	level      slog.LevelVar
+	configured slog.Level
+	modules    map[string]slog.Level
+	ttl        time.Duration
+
+	mutex     sync.RWMutex
+	global    *override
+	overrides map[string]*override
+}
+
+func GetLevels(cfg configs.LogCfg) (*Levels, error) {
+	levels := &Levels{overrides: map[string]*override{}}
+	if err := levels.Reconfigure(cfg); err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+func parseLevels(cfg configs.LogCfg) (slog.Level, map[string]slog.Level, time.Duration, error) {
+	configured, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+
+	modules := make(map[string]slog.Level, len(cfg.Modules))
+	for module, level := range cfg.Modules {
+		modules[module], err = ParseLevel(level)
+		if err != nil {
+			return 0, nil, 0, fmt.Errorf("module %s: %w", module, err)
+		}
+	}
+
+	ttl := cfg.LevelTTL
+	if ttl <= 0 {
+		ttl = defaultLevelTTL
+	}
+	return configured, modules, ttl, nil
+}
+
+func (l *Levels) Check(cfg configs.LogCfg) error {
+	_, _, _, err := parseLevels(cfg)
+	return err
+}
+
+// Reconfigure replaces the configured levels and TTL. Runtime overrides are
+// kept until they expire or are reset.
+func (l *Levels) Reconfigure(cfg configs.LogCfg) error {
+	configured, modules, ttl, err := parseLevels(cfg)
+	if err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.configured = configured
+	l.modules = modules
+	l.ttl = ttl
+	if l.global == nil {
+		l.level.Set(configured)
+	}
+	return nil
+}
+
+func (l *Levels) levelTTL() time.Duration {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.ttl
+}
+
+func (l *Levels) Level() slog.Level {
+	return l.level.Level()
+}
+
+// enabled prefers a runtime override of the module, then one of the service,
+// then the configured module level.
+func (l *Levels) enabled(module string, level slog.Level) bool {
+	if module == "" {
+		return level >= l.level.Level()
+	}
+
+	l.mutex.RLock()
+	o, found := l.overrides[module]
+	global := l.global
+	configured, configuredFound := l.modules[module]
+	l.mutex.RUnlock()
+
+	switch {
+	case found:
+		return level >= o.level
+	case global != nil:
+		return level >= global.level
+	case configuredFound:
+		return level >= configured
+	}
+	return level >= l.level.Level()
+}
+
+// Set changes the level of module, or of the whole service when module is
+// empty, until ttl passes. A non-positive ttl keeps it until Reset.
+func (l *Levels) Set(module string, level slog.Level, ttl time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	o := &override{level: level}
+	if ttl > 0 {
+		expires := time.Now().Add(ttl)
+		o.expires = &expires
+		o.timer = time.AfterFunc(ttl, func() { l.expire(module, o) })
+	}
+
+	if module == "" {
+		l.global.stop()
+		l.global = o
+		l.level.Set(level)
+		return
+	}
+	l.overrides[module].stop()
+	l.overrides[module] = o
+}
+
+func (o *override) stop() {
+	if o != nil && o.timer != nil {
+		o.timer.Stop()
+	}
+}
+
+func (l *Levels) expire(module string, o *override) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if module == "" {
+		if l.global == o {
+			l.global = nil
+			l.level.Set(l.configured)
+		}
+		return
+	}
+	if l.overrides[module] == o {
+		delete(l.overrides, module)
+	}
+}
+
+// Reset drops the runtime override of module, or every override when module
+// is empty.
+func (l *Levels) Reset(module string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if module != "" {
+		l.overrides[module].stop()
+		delete(l.overrides, module)
+		return
+	}
+
+	l.resetGlobal()
+	for module, o := range l.overrides {
+		o.stop()
+		delete(l.overrides, module)
+	}
+}
+
+func (l *Levels) resetGlobal() {
+	l.global.stop()
+	l.global = nil
+	l.level.Set(l.configured)
+}
+
+type LevelState struct {
+	Level   string     `json:"level"`
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+type LevelsReport struct {
+	LevelState
+	Modules map[string]LevelState `json:"modules,omitempty"`
+}
+
+func (l *Levels) Report() LevelsReport {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	report := LevelsReport{
+		LevelState: LevelState{Level: l.level.Level().String()},
+		Modules:    make(map[string]LevelState, len(l.modules)+len(l.overrides)),
+	}
+	if l.global != nil {
+		report.Expires = l.global.expires
+	}
+	for module, level := range l.modules {
+		report.Modules[module] = LevelState{Level: level.String()}
+	}
+	for module, o := range l.overrides {
+		report.Modules[module] = LevelState{Level: o.level.String(), Expires: o.expires}
+	}
+	return report
+}
+
+type levelRequest struct {
+	Level  string `json:"level"`
+	Module string `json:"module"`
+	TTL    string `json:"ttl"`
+}
+
+// Handler reports the levels on GET, sets one on PUT or POST with a
+// {"level", "module", "ttl"} body and drops overrides on DELETE, optionally
+// limited to ?module=. Without a ttl the configured one applies, a given one
+// must be positive and at most maxLevelTTL.
+func (l *Levels) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			var req levelRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			level, err := ParseLevel(req.Level)
+			if err != nil || req.Level == "" {
+				http.Error(w, fmt.Sprintf("bad level %q", req.Level), http.StatusBadRequest)
+				return
+			}
+
+			ttl := l.levelTTL()
+			if req.TTL != "" {
+				ttl, err = time.ParseDuration(req.TTL)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				if ttl <= 0 || ttl > maxLevelTTL {
+					http.Error(w, fmt.Sprintf("ttl %s is not in (0, %s]", ttl, maxLevelTTL), http.StatusBadRequest)
+					return
+				}
+			}
+			l.Set(req.Module, level, ttl)
+		case http.MethodDelete:
+			l.Reset(r.URL.Query().Get("module"))
+		default:
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := json.Marshal(l.Report())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	})
+}
+
+// Toggle switches the service to debug for the configured TTL, or back to the
+// configured level if the service level was already changed.
+func (l *Levels) Toggle() {
+	l.mutex.Lock()
+	if l.global != nil {
+		l.resetGlobal()
+		l.mutex.Unlock()
+		return
+	}
+	l.mutex.Unlock()
+
+	l.Set("", slog.LevelDebug, l.levelTTL())
+}
+
+// WatchSignal toggles debug logging on SIGUSR1 until the returned func is
+// called.
+func (l *Levels) WatchSignal(lg *slog.Logger) func() {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGUSR1)
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				l.Toggle()
+				lg.Warn("log level changed by signal", "level", l.Level().String())
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
+
+// levelHandler filters records with Levels before they reach the sink and
+// remembers the module its logger was scoped to.
+type levelHandler struct {
+	slog.Handler
+	levels  *Levels
+	module  string
+	grouped bool
+}
+
+func (h *levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.levels.enabled(h.module, level)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	module := h.module
+	if !h.grouped {
+		for _, attr := range attrs {
+			if attr.Key == "module" {
+				module = attr.Value.String()
+			}
+		}
+	}
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), levels: h.levels, module: module, grouped: h.grouped}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), levels: h.levels, module: h.module, grouped: true}
+}
@@ -0,0 +1,159 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+)
+
+func TestLevels(t *testing.T) {
+	levels, err := GetLevels(configs.LogCfg{Level: "info", Modules: map[string]string{"core": "warn"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var buf bytes.Buffer
+	lg := slog.New(&levelHandler{Handler: slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), levels: levels})
+	api := lg.With("module", "api")
+	core := lg.With("module", "core")
+
+	testCases := []struct {
+		name   string
+		change func()
+		logger *slog.Logger
+		level  slog.Level
+		logged bool
+	}{
+		{"Service level", func() {}, api, slog.LevelInfo, true},
+		{"Below service level", func() {}, api, slog.LevelDebug, false},
+		{"Configured module", func() {}, core, slog.LevelInfo, false},
+		{"Module override", func() { levels.Set("api", slog.LevelDebug, 0) }, api, slog.LevelDebug, true},
+		{"Other module untouched", func() {}, lg, slog.LevelDebug, false},
+		{"Service override beats configured module", func() { levels.Set("", slog.LevelDebug, 0) }, core, slog.LevelDebug, true},
+		{"Reset", func() { levels.Reset("") }, api, slog.LevelDebug, false},
+		{"Reconfigured module", func() {
+			levels.Reconfigure(configs.LogCfg{Level: "debug", Modules: map[string]string{"core": "error"}})
+		}, core, slog.LevelWarn, false},
+		{"Reconfigured service", func() {}, api, slog.LevelDebug, true},
+		{"Reconfigure keeps overrides", func() {
+			levels.Set("", slog.LevelError, 0)
+			levels.Reconfigure(configs.LogCfg{Level: "info"})
+		}, api, slog.LevelWarn, false},
+		{"Bad module level is only checked", func() {
+			levels.Reset("")
+			if levels.Check(configs.LogCfg{Level: "debug", Modules: map[string]string{"api": "loud"}}) == nil {
+				t.Errorf("bad module level passed the check")
+			}
+		}, api, slog.LevelDebug, false},
+	}
+
+	for _, tc := range testCases {
+		tc.change()
+		buf.Reset()
+		tc.logger.Log(context.Background(), tc.level, tc.name)
+		if (buf.Len() > 0) != tc.logged {
+			t.Errorf("%s: logged %q, wanted logged=%v", tc.name, buf.String(), tc.logged)
+		}
+	}
+}
+
+func TestLevelsTTL(t *testing.T) {
+	levels, err := GetLevels(configs.LogCfg{Level: "warn"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	levels.Set("", slog.LevelDebug, 20*time.Millisecond)
+	levels.Set("core", slog.LevelDebug, 20*time.Millisecond)
+	if !levels.enabled("", slog.LevelDebug) || !levels.enabled("core", slog.LevelDebug) {
+		t.Fatalf("override was not applied")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for levels.enabled("", slog.LevelDebug) || levels.enabled("core", slog.LevelDebug) {
+		if time.Now().After(deadline) {
+			t.Fatalf("override did not expire: %+v", levels.Report())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if levels.Level() != slog.LevelWarn {
+		t.Errorf("level reverted to %s, wanted WARN", levels.Level())
+	}
+}
+
+func TestToggle(t *testing.T) {
+	levels, err := GetLevels(configs.LogCfg{Level: "info"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	levels.Toggle()
+	if levels.Level() != slog.LevelDebug {
+		t.Errorf("first toggle left %s, wanted DEBUG", levels.Level())
+	}
+	levels.Toggle()
+	if levels.Level() != slog.LevelInfo {
+		t.Errorf("second toggle left %s, wanted INFO", levels.Level())
+	}
+}
+
+func TestLevelsHandler(t *testing.T) {
+	levels, err := GetLevels(configs.LogCfg{Level: "info"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	handler := levels.Handler()
+
+	testCases := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+		level  string
+		module string
+	}{
+		{"Report", http.MethodGet, "/admin/log/level", "", http.StatusOK, "INFO", ""},
+		{"Set service", http.MethodPut, "/admin/log/level", `{"level":"debug","ttl":"1m"}`, http.StatusOK, "DEBUG", ""},
+		{"Set module", http.MethodPost, "/admin/log/level", `{"level":"error","module":"core"}`, http.StatusOK, "DEBUG", "ERROR"},
+		{"Bad level", http.MethodPut, "/admin/log/level", `{"level":"loud"}`, http.StatusBadRequest, "", ""},
+		{"Bad ttl", http.MethodPut, "/admin/log/level", `{"level":"warn","ttl":"soon"}`, http.StatusBadRequest, "", ""},
+		{"Endless ttl", http.MethodPut, "/admin/log/level", `{"level":"warn","ttl":"0"}`, http.StatusBadRequest, "", ""},
+		{"Long ttl", http.MethodPut, "/admin/log/level", `{"level":"warn","ttl":"48h"}`, http.StatusBadRequest, "", ""},
+		{"Reset module", http.MethodDelete, "/admin/log/level?module=core", "", http.StatusOK, "DEBUG", ""},
+		{"Reset", http.MethodDelete, "/admin/log/level", "", http.StatusOK, "INFO", ""},
+		{"Bad method", http.MethodPatch, "/admin/log/level", "", http.StatusMethodNotAllowed, "", ""},
+	}
+
+	for _, tc := range testCases {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body)))
+
+		if w.Code != tc.status {
+			t.Errorf("%s: status %d, wanted %d", tc.name, w.Code, tc.status)
+			continue
+		}
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var report LevelsReport
+		if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+			t.Fatalf("%s: bad report: %s", tc.name, err)
+		}
+		if report.Level != tc.level {
+			t.Errorf("%s: level %s, wanted %s", tc.name, report.Level, tc.level)
+		}
+		if report.Modules["core"].Level != tc.module {
+			t.Errorf("%s: core level %q, wanted %q", tc.name, report.Modules["core"].Level, tc.module)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestMiddleware(t *testing.T) {
+	testCases := map[string]struct {
+		header    string
+		generated bool
+	}{
+		"Accepted":  {header: "abc-123", generated: false},
+		"Missing":   {header: "", generated: true},
+		"Malformed": {header: "bad id\n", generated: true},
+	}
+
+	for name, curr := range testCases {
+		var got string
+		handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = RequestID(r.Context())
+		}))
+
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/film", nil)
+		if curr.header != "" {
+			r.Header.Set(RequestIDHeader, curr.header)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Header().Get(RequestIDHeader) != got {
+			t.Errorf("%s: response id %q differs from context id %q", name, w.Header().Get(RequestIDHeader), got)
+		}
+		if curr.generated && (got == curr.header || len(got) != 32) {
+			t.Errorf("%s: wanted a generated id, got %q", name, got)
+		}
+		if !curr.generated && got != curr.header {
+			t.Errorf("%s: wanted %q, got %q", name, curr.header, got)
+		}
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	var buff bytes.Buffer
+	lg := slog.New(slog.NewJSONHandler(&buff, nil))
+
+	FromContext(WithRequestID(context.Background(), "abc"), lg).Info("msg")
+	if !strings.Contains(buff.String(), `"request_id":"abc"`) {
+		t.Errorf("wanted request id in %s", buff.String())
+	}
+
+	buff.Reset()
+	FromContext(context.Background(), lg).Info("msg")
+	if strings.Contains(buff.String(), "request_id") {
+		t.Errorf("unexpected request id in %s", buff.String())
+	}
+}
+
+func TestInterceptors(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "abc")
+
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+	_ = UnaryClientInterceptor()(ctx, "/m", nil, nil, nil, invoker)
+
+	var got string
+	handler := func(ctx context.Context, req any) (any, error) {
+		got = RequestID(ctx)
+		return nil, nil
+	}
+	incoming := metadata.NewIncomingContext(context.Background(), outgoing)
+	_, _ = UnaryServerInterceptor()(incoming, nil, &grpc.UnaryServerInfo{FullMethod: "/m"}, handler)
+
+	if got != "abc" {
+		t.Errorf("wanted request id forwarded, got %q", got)
+	}
+}
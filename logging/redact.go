@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+)
+
+const Redacted = "[REDACTED]"
+
+// Keys and patterns redacted in every service on top of the configured ones.
+var (
+	defaultRedactKeys = []string{
+		"password", "sid", "session_id", "csrf", "csrf_token", "x-csrf-token",
+		"token", "authorization", "cookie", "set-cookie", "email",
+	}
+	defaultRedactPatterns = []string{
+		`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`,
+	}
+)
+
+// HashToken identifies a session or CSRF token in logs without revealing it:
+// the first 12 hex digits of its SHA-256.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+type redactor struct {
+	keys     map[string]struct{}
+	patterns []*regexp.Regexp
+}
+
+func newRedactor(cfg configs.RedactCfg) (*redactor, error) {
+	r := &redactor{keys: map[string]struct{}{}}
+	for _, key := range append(defaultRedactKeys, cfg.Keys...) {
+		r.keys[strings.ToLower(key)] = struct{}{}
+	}
+	for _, pattern := range append(defaultRedactPatterns, cfg.Patterns...) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("redact pattern %q err: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+func (r *redactor) text(s string) string {
+	for _, re := range r.patterns {
+		s = re.ReplaceAllString(s, Redacted)
+	}
+	return s
+}
+
+func (r *redactor) attr(a slog.Attr) slog.Attr {
+	if _, found := r.keys[strings.ToLower(a.Key)]; found {
+		return slog.String(a.Key, Redacted)
+	}
+
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, r.text(v.String()))
+	case slog.KindGroup:
+		attrs := v.Group()
+		redacted := make([]slog.Attr, len(attrs))
+		for i, attr := range attrs {
+			redacted[i] = r.attr(attr)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindAny:
+		s := fmt.Sprint(v.Any())
+		if redacted := r.text(s); redacted != s {
+			return slog.String(a.Key, redacted)
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
+
+// redactHandler masks attributes with a sensitive key and every match of the
+// patterns in messages and string values before they reach the sink.
+type redactHandler struct {
+	slog.Handler
+	redactor *redactor
+}
+
+func Redact(handler slog.Handler, cfg configs.RedactCfg) (slog.Handler, error) {
+	r, err := newRedactor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &redactHandler{Handler: handler, redactor: r}, nil
+}
+
+func (h *redactHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, h.redactor.text(record.Message), record.PC)
+	record.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redactor.attr(a))
+		return true
+	})
+	return h.Handler.Handle(ctx, redacted)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = h.redactor.attr(attr)
+	}
+	return &redactHandler{Handler: h.Handler.WithAttrs(redacted), redactor: h.redactor}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{Handler: h.Handler.WithGroup(name), redactor: h.redactor}
+}
@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+)
+
+func TestRedact(t *testing.T) {
+	const sid = "AbCdEfGhIjKlMnOpQrStUvWxYzAbCdEf"
+
+	testCases := map[string]struct {
+		log    func(lg *slog.Logger)
+		hidden []string
+		kept   []string
+	}{
+		"Key": {
+			log:    func(lg *slog.Logger) { lg.Info("signin", "password", "hunter2", "Session_ID", "abc") },
+			hidden: []string{"hunter2", "abc"},
+			kept:   []string{"signin"},
+		},
+		"Configured key": {
+			log:    func(lg *slog.Logger) { lg.Info("signin", "login", "boss") },
+			hidden: []string{"boss"},
+		},
+		"Message pattern": {
+			log:    func(lg *slog.Logger) { lg.Error("Key " + sid + " not found") },
+			hidden: []string{sid},
+			kept:   []string{"Key", "not found"},
+		},
+		"Email": {
+			log:    func(lg *slog.Logger) { lg.Info("profile", "err", "duplicate user@mail.ru") },
+			hidden: []string{"user@mail.ru"},
+			kept:   []string{"duplicate"},
+		},
+		"Error value": {
+			log:    func(lg *slog.Logger) { lg.Error("auth check error", "err", errors.New("bad session "+sid)) },
+			hidden: []string{sid},
+			kept:   []string{"bad session"},
+		},
+		"Logger attrs and groups": {
+			log: func(lg *slog.Logger) {
+				lg.With("csrf_token", "secret").WithGroup("request").Info("done", slog.Group("auth", "token", "t0ken"))
+			},
+			hidden: []string{"secret", "t0ken"},
+			kept:   []string{"done"},
+		},
+		"Hash": {
+			log:  func(lg *slog.Logger) { lg.Error("Session key not found", "sid_hash", HashToken(sid)) },
+			kept: []string{HashToken(sid)},
+		},
+	}
+
+	for name, tc := range testCases {
+		var buf bytes.Buffer
+		handler, err := Redact(slog.NewJSONHandler(&buf, nil), configs.RedactCfg{
+			Keys:     []string{"login"},
+			Patterns: []string{`\b[A-Za-z]{32}\b`},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		tc.log(slog.New(handler))
+		for _, hidden := range tc.hidden {
+			if strings.Contains(buf.String(), hidden) {
+				t.Errorf("%s: %q leaked: %s", name, hidden, buf.String())
+			}
+		}
+		for _, kept := range tc.kept {
+			if !strings.Contains(buf.String(), kept) {
+				t.Errorf("%s: %q lost: %s", name, kept, buf.String())
+			}
+		}
+	}
+}
+
+func TestRedactBadPattern(t *testing.T) {
+	_, err := Redact(slog.NewJSONHandler(&bytes.Buffer{}, nil), configs.RedactCfg{Patterns: []string{"("}})
+	if err == nil {
+		t.Errorf("expected an error for a broken pattern")
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	if HashToken("a") == HashToken("b") || len(HashToken("a")) != 12 {
+		t.Errorf("bad hashes: %s %s", HashToken("a"), HashToken("b"))
+	}
+}
@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+	OutputFile   = "file"
+
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// Writer opens a log sink. Files are appended to and rotated once they grow
+// past rotation.MaxSize megabytes; rotated files older than MaxAge days or
+// beyond MaxBackups are removed. Zero values keep lumberjack's defaults:
+// 100 megabytes and no removal.
+func Writer(output string, file string, rotation configs.RotationCfg) (io.WriteCloser, error) {
+	switch output {
+	case "", OutputStdout:
+		return nopCloser{os.Stdout}, nil
+	case OutputStderr:
+		return nopCloser{os.Stderr}, nil
+	case OutputFile:
+		if file == "" {
+			return nil, fmt.Errorf("log sink err: file output without a file")
+		}
+		return &lumberjack.Logger{
+			Filename:   file,
+			MaxSize:    rotation.MaxSize,
+			MaxAge:     rotation.MaxAge,
+			MaxBackups: rotation.MaxBackups,
+			Compress:   rotation.Compress,
+		}, nil
+	}
+	return nil, fmt.Errorf("log sink err: unknown output %q", output)
+}
+
+func Handler(w io.Writer, format string, opts *slog.HandlerOptions) (slog.Handler, error) {
+	switch format {
+	case "", FormatJSON:
+		return slog.NewJSONHandler(w, opts), nil
+	case FormatText:
+		return slog.NewTextHandler(w, opts), nil
+	}
+	return nil, fmt.Errorf("log handler err: unknown format %q", format)
+}
+
+func ParseLevel(level string) (slog.Level, error) {
+	var l slog.Level
+	if level == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return l, fmt.Errorf("log level err: %w", err)
+	}
+	return l, nil
+}
+
+// New builds the service logger from cfg, filtered by levels and redacted.
+// The returned closer flushes and closes the sink.
+func New(cfg configs.LogCfg, levels *Levels) (*slog.Logger, io.Closer, error) {
+	w, err := Writer(cfg.Output, cfg.File, cfg.Rotation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	handler, err := Handler(w, cfg.Format, &slog.HandlerOptions{Level: slog.Level(math.MinInt)})
+	if err == nil {
+		handler, err = Redact(handler, cfg.Redact)
+	}
+	if err != nil {
+		w.Close()
+		return nil, nil, err
+	}
+
+	return slog.New(&levelHandler{Handler: handler, levels: levels}), w, nil
+}
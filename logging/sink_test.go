@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+)
+
+func TestNew(t *testing.T) {
+	testCases := map[string]struct {
+		cfg configs.LogCfg
+		err bool
+	}{
+		"Defaults":     {cfg: configs.LogCfg{}},
+		"Text stderr":  {cfg: configs.LogCfg{Level: "debug", Format: FormatText, Output: OutputStderr}},
+		"Bad level":    {cfg: configs.LogCfg{Level: "loud"}, err: true},
+		"Bad format":   {cfg: configs.LogCfg{Format: "xml"}, err: true},
+		"Bad output":   {cfg: configs.LogCfg{Output: "syslog"}, err: true},
+		"Missing file": {cfg: configs.LogCfg{Output: OutputFile}, err: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			levels, err := GetLevels(tc.cfg)
+			if err == nil {
+				var closer io.Closer
+				_, closer, err = New(tc.cfg, levels)
+				if err == nil {
+					defer closer.Close()
+				}
+			}
+			if (err != nil) != tc.err {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewFileAppends(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "service.log")
+	cfg := configs.LogCfg{Level: "warn", Format: FormatText, Output: OutputFile, File: file}
+
+	levels, err := GetLevels(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, msg := range []string{"first", "second"} {
+		lg, closer, err := New(cfg, levels)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		lg.Info("filtered")
+		lg.Warn(msg)
+		closer.Close()
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(string(content), "msg=first") || !strings.Contains(string(content), "msg=second") {
+		t.Errorf("restart truncated the log: %s", content)
+	}
+	if strings.Contains(string(content), "filtered") {
+		t.Errorf("record below the level was written: %s", content)
+	}
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	BucketsExponential = "exponential"
+	BucketsExplicit    = "explicit"
+	BucketsNative      = "native"
+)
+
+const (
+	defaultNativeBucketFactor = 1.1
+	defaultNativeMaxBuckets   = 160
+)
+
+// defaultLatencyBuckets cover 5ms to ~10s, which fits our API latencies.
+var defaultLatencyBuckets = prometheus.ExponentialBuckets(0.005, 2, 12)
+
+// latencyOpts sets the buckets of opts. Without a type the default buckets are
+// used, a malformed config is an error rather than falling back to them.
+func latencyOpts(opts prometheus.HistogramOpts, cfg configs.HistogramCfg) (prometheus.HistogramOpts, error) {
+	if err := cfg.Validate(); err != nil {
+		return opts, err
+	}
+
+	switch cfg.Type {
+	case "":
+		opts.Buckets = defaultLatencyBuckets
+	case BucketsExponential:
+		opts.Buckets = prometheus.ExponentialBuckets(cfg.Start, cfg.Factor, cfg.Count)
+	case BucketsExplicit:
+		opts.Buckets = cfg.Buckets
+	case BucketsNative:
+		opts.NativeHistogramBucketFactor = cfg.NativeBucketFactor
+		if opts.NativeHistogramBucketFactor == 0 {
+			opts.NativeHistogramBucketFactor = defaultNativeBucketFactor
+		}
+		opts.NativeHistogramMaxBucketNumber = cfg.NativeMaxBuckets
+		if opts.NativeHistogramMaxBucketNumber == 0 {
+			opts.NativeHistogramMaxBucketNumber = defaultNativeMaxBuckets
+		}
+		opts.NativeHistogramMinResetDuration = time.Hour
+	}
+	return opts, nil
+}
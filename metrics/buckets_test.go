@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestLatencyOpts(t *testing.T) {
+	testCases := map[string]struct {
+		cfg     configs.HistogramCfg
+		buckets []float64
+		factor  float64
+		err     bool
+	}{
+		"Default": {
+			cfg:     configs.HistogramCfg{},
+			buckets: defaultLatencyBuckets,
+		},
+		"Exponential": {
+			cfg:     configs.HistogramCfg{Type: BucketsExponential, Start: 0.01, Factor: 10, Count: 3},
+			buckets: []float64{0.01, 0.1, 1},
+		},
+		"Bad exponential": {
+			cfg: configs.HistogramCfg{Type: BucketsExponential, Start: 0.01, Factor: 1, Count: 3},
+			err: true,
+		},
+		"Explicit": {
+			cfg:     configs.HistogramCfg{Type: BucketsExplicit, Buckets: []float64{0.1, 0.5}},
+			buckets: []float64{0.1, 0.5},
+		},
+		"Unsorted explicit": {
+			cfg: configs.HistogramCfg{Type: BucketsExplicit, Buckets: []float64{0.5, 0.1}},
+			err: true,
+		},
+		"Duplicate explicit": {
+			cfg: configs.HistogramCfg{Type: BucketsExplicit, Buckets: []float64{0.1, 0.1}},
+			err: true,
+		},
+		"Unknown type": {
+			cfg: configs.HistogramCfg{Type: "linear"},
+			err: true,
+		},
+		"Native": {
+			cfg:    configs.HistogramCfg{Type: BucketsNative},
+			factor: defaultNativeBucketFactor,
+		},
+	}
+
+	for name, curr := range testCases {
+		opts, err := latencyOpts(prometheus.HistogramOpts{}, curr.cfg)
+		if (err != nil) != curr.err {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if !reflect.DeepEqual(opts.Buckets, curr.buckets) {
+			t.Errorf("%s: wanted buckets %v, got %v", name, curr.buckets, opts.Buckets)
+		}
+		if opts.NativeHistogramBucketFactor != curr.factor {
+			t.Errorf("%s: wanted native factor %v, got %v", name, curr.factor, opts.NativeHistogramBucketFactor)
+		}
+	}
+}
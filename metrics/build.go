@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"runtime"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Commit is set together with Version:
+//
+//	go build -ldflags "-X github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics.Version=v1.2.0 \
+//		-X github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics.Commit=$(git rev-parse --short HEAD)"
+var Commit = "unknown"
+
+func newBuildInfo(service string) prometheus.Gauge {
+	buildInfo := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "build_info",
+		Help:      "Always 1, labeled with the build of the running binary.",
+		ConstLabels: prometheus.Labels{
+			"version":    Version,
+			"commit":     Commit,
+			"go_version": runtime.Version(),
+			"service":    service,
+		},
+	})
+	buildInfo.Set(1)
+
+	return buildInfo
+}
+
+// registerRuntime adds the build info and the Go runtime and process collectors
+// to registry. They skip the service's wrapping registerer: build_info carries
+// its own labels and go_info already has a version label.
+func registerRuntime(service string, registry *prometheus.Registry) {
+	reg := prometheus.WrapRegistererWith(prometheus.Labels{"service": service}, registry)
+
+	register(registry, newBuildInfo(service))
+	register(reg, collectors.NewGoCollector())
+	register(reg, collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+}
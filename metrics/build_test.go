@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestBuildInfo(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	_, err := NewMetrics("films", registry, configs.MetricsCfg{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, name := range []string{"moviehub_build_info", "go_goroutines"} {
+		count, err := testutil.GatherAndCount(registry, name)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if count != 1 {
+			t.Errorf("wanted one %s series, got %d", name, count)
+		}
+	}
+}
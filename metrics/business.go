@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//go:generate mockgen -source=business.go -destination=mocks/business_mock.go -package=mocks
+
+const (
+	SigninSuccess     = "success"
+	SigninBadPassword = "bad_password"
+	SigninRedisDown   = "redis_down"
+	SigninError       = "error"
+)
+
+const (
+	FavoriteFilm  = "film"
+	FavoriteActor = "actor"
+)
+
+type IAuthRecorder interface {
+	Signup()
+	Signin(result string)
+}
+
+type IFilmsRecorder interface {
+	RatingAdded(rating uint16)
+	FavoriteAdded(item string)
+	FavoriteRemoved(item string)
+}
+
+type ICommentsRecorder interface {
+	CommentAdded()
+}
+
+// Business records domain events from the usecase layers.
+type Business struct {
+	signups   prometheus.Counter
+	signins   *prometheus.CounterVec
+	ratings   prometheus.Histogram
+	favorites *prometheus.CounterVec
+	comments  prometheus.Counter
+}
+
+func (m *Metrics) Business() *Business {
+	return &Business{
+		signups: register(m.reg, prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "signups_total",
+			Help:      "Accounts created.",
+		})),
+
+		signins: register(m.reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "signins_total",
+			Help:      "Signin attempts, by result or failure reason.",
+		}, []string{"result"})),
+
+		ratings: register(m.reg, prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "film_rating_value",
+			Help:      "Submitted film ratings.",
+			Buckets:   prometheus.LinearBuckets(1, 1, 10),
+		})),
+
+		favorites: register(m.reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "favorites_total",
+			Help:      "Favorites added or removed, by item kind.",
+		}, []string{"item", "action"})),
+
+		comments: register(m.reg, prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "comments_total",
+			Help:      "Comments created.",
+		})),
+	}
+}
+
+func (b *Business) Signup() {
+	b.signups.Inc()
+}
+
+func (b *Business) Signin(result string) {
+	b.signins.WithLabelValues(result).Inc()
+}
+
+func (b *Business) RatingAdded(rating uint16) {
+	b.ratings.Observe(float64(rating))
+}
+
+func (b *Business) FavoriteAdded(item string) {
+	b.favorites.WithLabelValues(item, "add").Inc()
+}
+
+func (b *Business) FavoriteRemoved(item string) {
+	b.favorites.WithLabelValues(item, "remove").Inc()
+}
+
+func (b *Business) CommentAdded() {
+	b.comments.Inc()
+}
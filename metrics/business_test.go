@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestBusiness(t *testing.T) {
+	mt, err := NewMetrics("test", prometheus.NewRegistry(), configs.MetricsCfg{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	b := mt.Business()
+
+	b.Signin(SigninSuccess)
+	b.Signin(SigninBadPassword)
+	b.Signin(SigninBadPassword)
+	b.FavoriteAdded(FavoriteFilm)
+	b.FavoriteRemoved(FavoriteActor)
+	b.RatingAdded(7)
+
+	if count := testutil.ToFloat64(b.signins.WithLabelValues(SigninBadPassword)); count != 2 {
+		t.Errorf("wanted 2 failed signins, got %v", count)
+	}
+	if count := testutil.ToFloat64(b.favorites.WithLabelValues(FavoriteActor, "remove")); count != 1 {
+		t.Errorf("wanted 1 removed actor, got %v", count)
+	}
+	if count := testutil.CollectAndCount(b.ratings); count != 1 {
+		t.Errorf("wanted rating histogram, got %d series", count)
+	}
+
+	if mt.Business().signups != b.signups {
+		t.Errorf("wanted recorders to share registered collectors")
+	}
+}
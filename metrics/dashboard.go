@@ -0,0 +1,241 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Components a service is built from. Dashboards and alert rules get one row or
+// rule group per component; routes, repositories, stores and RPC methods are
+// grouped by label, so new ones show up without regenerating.
+const (
+	ComponentHTTP        = "http"
+	ComponentDB          = "db"
+	ComponentRedis       = "redis"
+	ComponentGrpcServer  = "grpc_server"
+	ComponentGrpcClient  = "grpc_client"
+	ComponentAuthKPI     = "kpi_auth"
+	ComponentFilmsKPI    = "kpi_films"
+	ComponentCommentsKPI = "kpi_comments"
+)
+
+// Service names a service and the components it wires up. Every service
+// exports its own, the service and obsgen both read it.
+type Service struct {
+	Name       string
+	Components []string
+}
+
+type panel struct {
+	title   string
+	unit    string
+	legend  string
+	expr    string
+	metrics []string
+}
+
+type row struct {
+	title  string
+	panels []panel
+	alerts []rule
+}
+
+func (m *Metrics) selector() string {
+	return fmt.Sprintf("service=%q", m.service)
+}
+
+func (m *Metrics) quantile(q float64, name string, by string, native bool) string {
+	if native {
+		return fmt.Sprintf("histogram_quantile(%v, sum by (%s) (rate(%s{%s}[5m])))", q, by, name, m.selector())
+	}
+	return fmt.Sprintf("histogram_quantile(%v, sum by (%s, le) (rate(%s_bucket{%s}[5m])))", q, by, name, m.selector())
+}
+
+func (m *Metrics) rate(name string, by string, matchers string) string {
+	return fmt.Sprintf("sum by (%s) (rate(%s{%s%s}[5m]))", by, name, m.selector(), matchers)
+}
+
+func (m *Metrics) row(component string) (row, error) {
+	s := m.selector()
+	alertLabels := func(severity string) map[string]string {
+		return map[string]string{"service": m.service, "severity": severity}
+	}
+
+	switch component {
+	case ComponentHTTP:
+		hits := namespace + "_http_requests_total"
+		duration := namespace + "_http_request_duration_seconds"
+		errorRatio := fmt.Sprintf("%s / %s", m.rate(hits, "route", `,status=~"5.."`), m.rate(hits, "route", ""))
+
+		return row{title: "HTTP", panels: []panel{
+			{"Requests by route", "reqps", "{{route}}", m.rate(hits, "route", ""), []string{hits}},
+			{"Error ratio by route", "percentunit", "{{route}}", errorRatio, []string{hits}},
+			{"p95 latency by route", "s", "{{route}}", m.quantile(0.95, duration, "route", m.nativeLatency), []string{duration}},
+			{"Requests in flight", "short", "in flight", fmt.Sprintf("sum(%s_http_requests_in_flight{%s})", namespace, s),
+				[]string{namespace + "_http_requests_in_flight"}},
+		}, alerts: []rule{{
+			Alert:       "HTTPHighErrorRatio",
+			Expr:        errorRatio + " > 0.05",
+			For:         "10m",
+			Labels:      alertLabels("ticket"),
+			Annotations: map[string]string{"summary": "{{ $labels.route }} answers 5xx to more than 5% of requests"},
+		}}}, nil
+
+	case ComponentDB:
+		duration := namespace + "_db_query_duration_seconds"
+		up := namespace + "_db_up"
+
+		return row{title: "Postgres", panels: []panel{
+			{"p95 query latency by repo", "s", "{{repo}}", m.quantile(0.95, duration, "repo", false), []string{duration}},
+			{"Connections in use by repo", "short", "{{repo}}", fmt.Sprintf("sum by (repo) (%s_db_in_use_connections{%s})", namespace, s),
+				[]string{namespace + "_db_in_use_connections"}},
+			{"Pool waits by repo", "ops", "{{repo}}", m.rate(namespace+"_db_wait_count_total", "repo", ""),
+				[]string{namespace + "_db_wait_count_total"}},
+			{"Database up by repo", "short", "{{repo}}", fmt.Sprintf("min by (repo) (%s{%s})", up, s), []string{up}},
+		}, alerts: []rule{{
+			Alert:       "DBDown",
+			Expr:        fmt.Sprintf("max by (repo) (%s{%s}) == 0", up, s),
+			For:         "1m",
+			Labels:      alertLabels("page"),
+			Annotations: map[string]string{"summary": "{{ $labels.repo }} repository cannot reach Postgres"},
+		}}}, nil
+
+	case ComponentRedis:
+		duration := namespace + "_redis_command_duration_seconds"
+		up := namespace + "_redis_up"
+
+		return row{title: "Redis", panels: []panel{
+			{"Store up", "short", "{{store}}", fmt.Sprintf("min by (store) (%s{%s})", up, s), []string{up}},
+			{"p95 command latency by store", "s", "{{store}}", m.quantile(0.95, duration, "store", false), []string{duration}},
+			{"Command errors by store", "ops", "{{store}}", m.rate(namespace+"_redis_command_errors_total", "store", ""),
+				[]string{namespace + "_redis_command_errors_total"}},
+		}, alerts: []rule{{
+			Alert:       "RedisDown",
+			Expr:        fmt.Sprintf("max by (store) (%s{%s}) == 0", up, s),
+			For:         "1m",
+			Labels:      alertLabels("page"),
+			Annotations: map[string]string{"summary": "{{ $labels.store }} store is unreachable"},
+		}}}, nil
+
+	case ComponentGrpcServer, ComponentGrpcClient:
+		prefix := namespace + "_" + component
+		handled := prefix + "_handled_total"
+		duration := prefix + "_handling_seconds"
+		errorRatio := fmt.Sprintf("%s / %s", m.rate(handled, "method", `,code!="OK"`), m.rate(handled, "method", ""))
+
+		return row{title: "gRPC " + component[len("grpc_"):], panels: []panel{
+			{"RPCs by method and code", "reqps", "{{method}} {{code}}", m.rate(handled, "method, code", ""), []string{handled}},
+			{"p95 latency by method", "s", "{{method}}", m.quantile(0.95, duration, "method", false), []string{duration}},
+		}, alerts: []rule{{
+			Alert:       "GRPCHighErrorRatio",
+			Expr:        errorRatio + " > 0.05",
+			For:         "10m",
+			Labels:      alertLabels("ticket"),
+			Annotations: map[string]string{"summary": "{{ $labels.method }} fails more than 5% of calls"},
+		}}}, nil
+
+	case ComponentAuthKPI:
+		return row{title: "Accounts", panels: []panel{
+			{"Signups", "ops", "signups", m.rate(namespace+"_signups_total", "service", ""), []string{namespace + "_signups_total"}},
+			{"Signins by result", "ops", "{{result}}", m.rate(namespace+"_signins_total", "result", ""), []string{namespace + "_signins_total"}},
+		}}, nil
+
+	case ComponentFilmsKPI:
+		ratings := namespace + "_film_rating_value"
+		return row{title: "Ratings and favorites", panels: []panel{
+			{"Ratings submitted", "ops", "ratings", fmt.Sprintf("sum(rate(%s_count{%s}[5m]))", ratings, s), []string{ratings}},
+			{"Average rating", "short", "rating", fmt.Sprintf("sum(rate(%s_sum{%s}[1h])) / sum(rate(%s_count{%s}[1h]))", ratings, s, ratings, s),
+				[]string{ratings}},
+			{"Favorites by item and action", "ops", "{{item}} {{action}}", m.rate(namespace+"_favorites_total", "item, action", ""),
+				[]string{namespace + "_favorites_total"}},
+		}}, nil
+
+	case ComponentCommentsKPI:
+		return row{title: "Comments", panels: []panel{
+			{"Comments created", "ops", "comments", m.rate(namespace+"_comments_total", "service", ""), []string{namespace + "_comments_total"}},
+		}}, nil
+	}
+
+	return row{}, fmt.Errorf("unknown component %q", component)
+}
+
+type gridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type dashboardPanel struct {
+	ID          int               `json:"id"`
+	Type        string            `json:"type"`
+	Title       string            `json:"title"`
+	GridPos     gridPos           `json:"gridPos"`
+	Datasource  map[string]string `json:"datasource,omitempty"`
+	FieldConfig map[string]any    `json:"fieldConfig,omitempty"`
+	Targets     []map[string]any  `json:"targets,omitempty"`
+}
+
+// Dashboard renders a Grafana dashboard with a row of panels per component.
+func (m *Metrics) Dashboard(components ...string) ([]byte, error) {
+	datasource := map[string]string{"type": "prometheus", "uid": "${datasource}"}
+
+	var panels []dashboardPanel
+	y := 0
+	for _, component := range components {
+		r, err := m.row(component)
+		if err != nil {
+			return nil, fmt.Errorf("dashboard err: %w", err)
+		}
+
+		panels = append(panels, dashboardPanel{ID: len(panels) + 1, Type: "row", Title: r.title, GridPos: gridPos{H: 1, W: 24, Y: y}})
+		y++
+		for i, p := range r.panels {
+			panels = append(panels, dashboardPanel{
+				ID:          len(panels) + 1,
+				Type:        "timeseries",
+				Title:       p.title,
+				GridPos:     gridPos{H: 8, W: 12, X: 12 * (i % 2), Y: y + 8*(i/2)},
+				Datasource:  datasource,
+				FieldConfig: map[string]any{"defaults": map[string]string{"unit": p.unit}},
+				Targets:     []map[string]any{{"expr": p.expr, "legendFormat": p.legend, "refId": "A", "datasource": datasource}},
+			})
+		}
+		y += 8 * ((len(r.panels) + 1) / 2)
+	}
+
+	return json.MarshalIndent(map[string]any{
+		"uid":           fmt.Sprintf("%s-%s", namespace, m.service),
+		"title":         fmt.Sprintf("%s / %s", namespace, m.service),
+		"tags":          []string{namespace, m.service},
+		"schemaVersion": 38,
+		"refresh":       "30s",
+		"time":          map[string]string{"from": "now-6h", "to": "now"},
+		"templating": map[string]any{"list": []map[string]any{{
+			"name":  "datasource",
+			"type":  "datasource",
+			"query": "prometheus",
+		}}},
+		"panels": panels,
+	}, "", "  ")
+}
+
+// AlertRules renders the component alerts followed by the SLO rules.
+func (m *Metrics) AlertRules(components ...string) ([]byte, error) {
+	group := ruleGroup{Name: fmt.Sprintf("%s-%s", namespace, m.service)}
+	for _, component := range components {
+		r, err := m.row(component)
+		if err != nil {
+			return nil, fmt.Errorf("alert rules err: %w", err)
+		}
+		group.Rules = append(group.Rules, r.alerts...)
+	}
+
+	groups := ruleGroups{Groups: []ruleGroup{group}}
+	if slo := m.slo.ruleGroup(); len(slo.Rules) > 0 {
+		groups.Groups = append(groups.Groups, slo)
+	}
+	return yaml.Marshal(groups)
+}
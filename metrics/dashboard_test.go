@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"gopkg.in/yaml.v2"
+)
+
+var allComponents = []string{
+	ComponentHTTP, ComponentDB, ComponentRedis, ComponentGrpcServer, ComponentGrpcClient,
+	ComponentAuthKPI, ComponentFilmsKPI, ComponentCommentsKPI,
+}
+
+// TestDashboardMetrics checks that every panel queries a metric the service
+// actually exports.
+func TestDashboardMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	mt, err := NewMetrics("test", registry, configs.MetricsCfg{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	mx := http.NewServeMux()
+	mx.HandleFunc("/api/v1/film", func(w http.ResponseWriter, r *http.Request) {})
+	mt.Middleware(mx, mx).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/film", nil))
+
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	defer db.Close()
+	dbMetrics := mt.DB("film", db)
+	_, done := dbMetrics.Query(context.Background(), "GetFilm")
+	done()
+	dbMetrics.Ping(nil)
+
+	rm := mt.Redis("session")
+	cmd := redis.NewStringCmd(context.Background(), "get", "sid")
+	ctx, _ := rm.BeforeProcess(context.Background(), cmd)
+	cmd.SetErr(redis.ErrClosed)
+	_ = rm.AfterProcess(ctx, cmd)
+	rm.Ping(time.Now(), nil)
+
+	_, _ = mt.UnaryServerInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/m"},
+		func(ctx context.Context, req any) (any, error) { return nil, nil })
+	_ = mt.UnaryClientInterceptor()(context.Background(), "/m", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return nil
+		})
+
+	b := mt.Business()
+	b.Signup()
+	b.Signin(SigninSuccess)
+	b.RatingAdded(5)
+	b.FavoriteAdded(FavoriteFilm)
+	b.CommentAdded()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	exported := map[string]bool{}
+	for _, family := range families {
+		exported[family.GetName()] = true
+	}
+
+	for _, component := range allComponents {
+		r, err := mt.row(component)
+		if err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		for _, p := range r.panels {
+			for _, name := range p.metrics {
+				if !exported[name] {
+					t.Errorf("%s panel %q queries %s, which is not exported", component, p.title, name)
+				}
+			}
+		}
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	mt, err := NewMetrics("films", prometheus.NewRegistry(), configs.MetricsCfg{
+		Slo: []configs.SloCfg{{Route: "/api/v1/film", Availability: 0.99}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	dashboard, err := mt.Dashboard(allComponents...)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	var parsed struct {
+		UID    string `json:"uid"`
+		Panels []struct {
+			Type string `json:"type"`
+		} `json:"panels"`
+	}
+	if err := json.Unmarshal(dashboard, &parsed); err != nil {
+		t.Fatalf("dashboard is not valid json: %s", err)
+	}
+	if parsed.UID != "moviehub-films" || len(parsed.Panels) == 0 {
+		t.Errorf("unexpected dashboard %s", dashboard)
+	}
+
+	rules, err := mt.AlertRules(ComponentHTTP, ComponentDB)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	var groups ruleGroups
+	if err := yaml.Unmarshal(rules, &groups); err != nil {
+		t.Fatalf("rules are not valid yaml: %s", err)
+	}
+	if len(groups.Groups) != 2 || len(groups.Groups[0].Rules) != 2 {
+		t.Errorf("wanted component and slo groups, got %+v", groups)
+	}
+
+	if _, err := mt.Dashboard("unknown"); err == nil {
+		t.Errorf("wanted error for an unknown component")
+	}
+}
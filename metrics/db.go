@@ -0,0 +1,208 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type DBMetrics struct {
+	repo     string
+	db       *sql.DB
+	stats    *dbStatsCollector
+	duration *prometheus.HistogramVec
+	health   *Health
+	stop     context.CancelFunc
+	done     chan struct{}
+}
+
+// DB exports the pool stats of db and returns a query latency recorder for the
+// repository. A nil *DBMetrics only traces, so repositories built in tests
+// don't need metrics.
+func (m *Metrics) DB(repo string, db *sql.DB) *DBMetrics {
+	m.dbStats.add(repo, db)
+	m.health.watch(m.health.repos, repo)
+
+	return &DBMetrics{
+		repo:   repo,
+		db:     db,
+		stats:  m.dbStats,
+		health: m.health,
+		duration: register(m.reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Repository query latency.",
+			Buckets:   defaultLatencyBuckets,
+		}, []string{"repo", "method"})),
+	}
+}
+
+// OpenDB pings db and then watches it for the repository as DB does, checking
+// the connection every timer seconds until Close. db is closed when the first
+// ping fails.
+func (m *Metrics) OpenDB(repo string, db *sql.DB, timer uint32, lg *slog.Logger) (*DBMetrics, error) {
+	if err := db.Ping(); err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+
+	d := m.DB(repo, db)
+	ctx, stop := context.WithCancel(context.Background())
+	d.stop, d.done = stop, make(chan struct{})
+	go d.pingDb(ctx, time.Duration(timer)*time.Second, lg)
+	return d, nil
+}
+
+func (d *DBMetrics) pingDb(ctx context.Context, interval time.Duration, lg *slog.Logger) {
+	defer close(d.done)
+	for {
+		err := d.db.PingContext(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		d.Ping(err)
+		if err != nil {
+			lg.Error("db ping error", "repo", d.repo, "err", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Query starts a span for a repository query. The returned func ends it and
+// records the query latency.
+func (d *DBMetrics) Query(ctx context.Context, method string) (context.Context, func()) {
+	repo := "unknown"
+	if d != nil {
+		repo = d.repo
+	}
+
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, repo+"."+method, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system", "postgresql"), attribute.String("db.operation", method)))
+
+	return ctx, func() {
+		span.End()
+		if d != nil {
+			observe(ctx, d.duration.WithLabelValues(d.repo, method), time.Since(start).Seconds())
+		}
+	}
+}
+
+// Ping reports the result of the repository's connection check.
+func (d *DBMetrics) Ping(err error) {
+	if d == nil {
+		return
+	}
+	d.health.Report(d.repo, err)
+}
+
+// Close stops the connection checks and the pool stats and closes the pool.
+func (d *DBMetrics) Close() error {
+	if d == nil {
+		return nil
+	}
+	if d.stop != nil {
+		d.stop()
+		<-d.done
+	}
+	d.stats.remove(d.db)
+	return d.db.Close()
+}
+
+type dbPool struct {
+	repo string
+	db   *sql.DB
+}
+
+// dbStatsCollector exports sql.DBStats per repository. Pools opened twice for
+// the same repository are summed.
+type dbStatsCollector struct {
+	mutex sync.RWMutex
+	pools []dbPool
+
+	open         *prometheus.Desc
+	inUse        *prometheus.Desc
+	idle         *prometheus.Desc
+	waitCount    *prometheus.Desc
+	waitDuration *prometheus.Desc
+}
+
+func newDBStatsCollector() *dbStatsCollector {
+	labels := []string{"repo"}
+
+	return &dbStatsCollector{
+		open: prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", "open_connections"),
+			"Established connections, both in use and idle.", labels, nil),
+		inUse: prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", "in_use_connections"),
+			"Connections currently in use.", labels, nil),
+		idle: prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", "idle_connections"),
+			"Idle connections.", labels, nil),
+		waitCount: prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", "wait_count_total"),
+			"Connections waited for because the pool was exhausted.", labels, nil),
+		waitDuration: prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", "wait_duration_seconds_total"),
+			"Time blocked waiting for a new connection.", labels, nil),
+	}
+}
+
+func (c *dbStatsCollector) add(repo string, db *sql.DB) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.pools = append(c.pools, dbPool{repo: repo, db: db})
+}
+
+func (c *dbStatsCollector) remove(db *sql.DB) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for i, pool := range c.pools {
+		if pool.db == db {
+			c.pools = append(c.pools[:i], c.pools[i+1:]...)
+			return
+		}
+	}
+}
+
+func (c *dbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.open
+	ch <- c.inUse
+	ch <- c.idle
+	ch <- c.waitCount
+	ch <- c.waitDuration
+}
+
+func (c *dbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	stats := make(map[string]sql.DBStats, len(c.pools))
+	for _, pool := range c.pools {
+		current := pool.db.Stats()
+		total := stats[pool.repo]
+		total.OpenConnections += current.OpenConnections
+		total.InUse += current.InUse
+		total.Idle += current.Idle
+		total.WaitCount += current.WaitCount
+		total.WaitDuration += current.WaitDuration
+		stats[pool.repo] = total
+	}
+
+	for repo, total := range stats {
+		ch <- prometheus.MustNewConstMetric(c.open, prometheus.GaugeValue, float64(total.OpenConnections), repo)
+		ch <- prometheus.MustNewConstMetric(c.inUse, prometheus.GaugeValue, float64(total.InUse), repo)
+		ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(total.Idle), repo)
+		ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(total.WaitCount), repo)
+		ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, total.WaitDuration.Seconds(), repo)
+	}
+}
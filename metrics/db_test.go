@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestDB(t *testing.T) {
+	mt, err := NewMetrics("test", prometheus.NewRegistry(), configs.MetricsCfg{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	repos := make([]*DBMetrics, 0, 3)
+	for _, repo := range []string{"film", "film", "genre"} {
+		db, _, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("cant create mock: %s", err)
+		}
+
+		repos = append(repos, mt.DB(repo, db))
+		_, done := repos[len(repos)-1].Query(context.Background(), "GetFilm")
+		done()
+	}
+
+	if count := testutil.CollectAndCount(mt.dbStats); count != 10 {
+		t.Errorf("wanted 10 pool stats, 5 for each of the two repos, got %d", count)
+	}
+
+	repos[2].Close()
+	if count := testutil.CollectAndCount(mt.dbStats); count != 5 {
+		t.Errorf("wanted the closed genre pool dropped, got %d stats", count)
+	}
+	repos[0].Close()
+	if count := testutil.CollectAndCount(mt.dbStats); count != 5 {
+		t.Errorf("wanted the other film pool kept, got %d stats", count)
+	}
+	repos[1].Close()
+	if count := testutil.CollectAndCount(mt.dbStats); count != 0 {
+		t.Errorf("wanted no stats after closing every pool, got %d", count)
+	}
+
+	var nilMetrics *DBMetrics
+	_, done := nilMetrics.Query(context.Background(), "GetFilm")
+	done()
+	nilMetrics.Close()
+}
+
+func TestOpenDB(t *testing.T) {
+	mt, err := NewMetrics("test", prometheus.NewRegistry(), configs.MetricsCfg{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	lg := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	mock.ExpectClose()
+
+	if _, err := mt.OpenDB("film", db, 1, lg); err == nil {
+		t.Errorf("expected the failed ping error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("pool not closed after the failed ping: %s", err)
+	}
+
+	db, mock, err = sqlmock.New(sqlmock.MonitorPingsOption(true))
+	if err != nil {
+		t.Fatalf("cant create mock: %s", err)
+	}
+	mock.ExpectPing()
+	mock.ExpectClose()
+
+	repo, err := mt.OpenDB("film", db, 1, lg)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := repo.Close(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("pool not closed: %s", err)
+	}
+	if count := testutil.CollectAndCount(mt.dbStats); count != 0 {
+		t.Errorf("wanted no stats after closing the pool, got %d", count)
+	}
+}
@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// observe records v with the trace ID of the sampled span in ctx as exemplar,
+// so a latency bucket links to an example trace.
+func observe(ctx context.Context, o prometheus.Observer, v float64) {
+	span := trace.SpanContextFromContext(ctx)
+	if eo, ok := o.(prometheus.ExemplarObserver); ok && span.IsSampled() {
+		eo.ObserveWithExemplar(v, prometheus.Labels{"trace_id": span.TraceID().String()})
+		return
+	}
+	o.Observe(v)
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+type grpcMetrics struct {
+	duration *prometheus.HistogramVec
+	handled  *prometheus.CounterVec
+	batch    *prometheus.HistogramVec
+}
+
+type batchRequest interface {
+	GetIds() []int32
+}
+
+func (m *Metrics) grpcMetrics(side string) *grpcMetrics {
+	return &grpcMetrics{
+		duration: register(m.reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "grpc_" + side,
+			Name:      "handling_seconds",
+			Help:      "Unary RPC latency.",
+			Buckets:   defaultLatencyBuckets,
+		}, []string{"method"})),
+
+		handled: register(m.reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "grpc_" + side,
+			Name:      "handled_total",
+			Help:      "Unary RPCs completed, by status code.",
+		}, []string{"method", "code"})),
+
+		batch: register(m.reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "grpc_" + side,
+			Name:      "request_batch_size",
+			Help:      "Number of ids sent in batched RPC requests.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 8),
+		}, []string{"method"})),
+	}
+}
+
+func (gm *grpcMetrics) observe(ctx context.Context, method string, req any, start time.Time, err error) {
+	observe(ctx, gm.duration.WithLabelValues(method), time.Since(start).Seconds())
+	gm.handled.WithLabelValues(method, status.Code(err).String()).Inc()
+	if batch, ok := req.(batchRequest); ok {
+		gm.batch.WithLabelValues(method).Observe(float64(len(batch.GetIds())))
+	}
+}
+
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	gm := m.grpcMetrics("server")
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		gm.observe(ctx, info.FullMethod, req, start, err)
+
+		return resp, err
+	}
+}
+
+func (m *Metrics) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	gm := m.grpcMetrics("client")
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		gm.observe(ctx, method, req, start, err)
+
+		return err
+	}
+}
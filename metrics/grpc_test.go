@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/go-park-mail-ru/2023_2_Vkladyshi/authorization/proto"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	mt, err := NewMetrics("test", prometheus.NewRegistry(), configs.MetricsCfg{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	interceptor := mt.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: pb.Authorization_GetIdsAndPaths_FullMethodName}
+
+	ok := func(ctx context.Context, req any) (any, error) {
+		return &pb.NamesAndPathsResponse{}, nil
+	}
+	fail := func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.Unavailable, "db down")
+	}
+
+	_, _ = interceptor(context.Background(), &pb.NamesAndPathsListRequest{Ids: []int32{1, 2, 3}}, info, ok)
+	_, _ = interceptor(context.Background(), &pb.NamesAndPathsListRequest{}, info, fail)
+
+	gm := mt.grpcMetrics("server")
+	if handled := testutil.ToFloat64(gm.handled.WithLabelValues(info.FullMethod, codes.OK.String())); handled != 1 {
+		t.Errorf("wanted 1 OK call, got %v", handled)
+	}
+	if handled := testutil.ToFloat64(gm.handled.WithLabelValues(info.FullMethod, codes.Unavailable.String())); handled != 1 {
+		t.Errorf("wanted 1 Unavailable call, got %v", handled)
+	}
+	if count := testutil.CollectAndCount(gm.batch); count != 1 {
+		t.Errorf("wanted batch sizes for 1 method, got %d", count)
+	}
+}
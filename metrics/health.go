@@ -0,0 +1,258 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// readyTimeout bounds the checks run by a readiness probe.
+const readyTimeout = 2 * time.Second
+
+type RepoHealth struct {
+	Up          bool
+	LastSuccess time.Time
+	Failures    int
+}
+
+// Health collects the results of the repositories' ping loops and the Redis
+// connection checks. It is shared by all repositories of a service and answers
+// whether they can reach their databases and the services it calls.
+type Health struct {
+	mutex  sync.RWMutex
+	repos  map[string]*RepoHealth
+	stores map[string]*RepoHealth
+	checks map[string]func(ctx context.Context) error
+
+	up          *prometheus.GaugeVec
+	lastSuccess *prometheus.GaugeVec
+	failures    *prometheus.GaugeVec
+}
+
+func newHealth(reg prometheus.Registerer) *Health {
+	return &Health{
+		repos:  map[string]*RepoHealth{},
+		stores: map[string]*RepoHealth{},
+		checks: map[string]func(ctx context.Context) error{},
+
+		up: register(reg, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "db_up",
+			Help:      "Whether the last ping to the repository database succeeded.",
+		}, []string{"repo"})),
+
+		lastSuccess: register(reg, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "db_last_successful_ping_timestamp_seconds",
+			Help:      "Unix time of the last successful ping to the repository database.",
+		}, []string{"repo"})),
+
+		failures: register(reg, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "db_ping_consecutive_failures",
+			Help:      "Pings failed in a row since the last successful one.",
+		}, []string{"repo"})),
+	}
+}
+
+func (m *Metrics) Health() *Health {
+	return m.health
+}
+
+func update(states map[string]*RepoHealth, name string, err error) *RepoHealth {
+	state, found := states[name]
+	if !found {
+		state = &RepoHealth{}
+		states[name] = state
+	}
+
+	if err != nil {
+		state.Up = false
+		state.Failures++
+		return state
+	}
+	state.Up = true
+	state.Failures = 0
+	state.LastSuccess = time.Now()
+	return state
+}
+
+func (h *Health) Report(repo string, err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	state := update(h.repos, repo, err)
+	if err != nil {
+		h.up.WithLabelValues(repo).Set(0)
+		h.failures.WithLabelValues(repo).Set(float64(state.Failures))
+		return
+	}
+	h.up.WithLabelValues(repo).Set(1)
+	h.failures.WithLabelValues(repo).Set(0)
+	h.lastSuccess.WithLabelValues(repo).Set(float64(state.LastSuccess.Unix()))
+}
+
+// watch adds a repository or store before its first ping, the service is not
+// ready until it has been pinged.
+func (h *Health) watch(states map[string]*RepoHealth, name string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if _, found := states[name]; !found {
+		states[name] = &RepoHealth{}
+	}
+}
+
+// reportStore records a Redis connection check, its gauges are kept by
+// RedisMetrics.
+func (h *Health) reportStore(store string, err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	update(h.stores, store, err)
+}
+
+// AddCheck registers a dependency without a ping loop, such as a gRPC service,
+// which is checked on every readiness probe.
+func (h *Health) AddCheck(name string, check func(ctx context.Context) error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.checks[name] = check
+}
+
+func (h *Health) Repos() map[string]RepoHealth {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	repos := make(map[string]RepoHealth, len(h.repos))
+	for repo, state := range h.repos {
+		repos[repo] = *state
+	}
+	return repos
+}
+
+func down(kind string, states map[string]RepoHealth) []error {
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		switch state := states[name]; {
+		case state.Up:
+		case state.Failures == 0:
+			errs = append(errs, fmt.Errorf("%s %s has not been pinged yet", kind, name))
+		default:
+			errs = append(errs, fmt.Errorf("%s %s is down after %d failed pings", kind, name, state.Failures))
+		}
+	}
+	return errs
+}
+
+// problems checks the named dependencies, or all of them when none are named.
+func (h *Health) problems(ctx context.Context, deps ...string) []error {
+	wanted := make(map[string]bool, len(deps))
+	for _, dep := range deps {
+		wanted[dep] = true
+	}
+	known := make(map[string]bool, len(deps))
+	pick := func(name string) bool {
+		known[name] = true
+		return len(deps) == 0 || wanted[name]
+	}
+
+	h.mutex.RLock()
+	repos := make(map[string]RepoHealth, len(h.repos))
+	for repo, state := range h.repos {
+		if pick(repo) {
+			repos[repo] = *state
+		}
+	}
+	stores := make(map[string]RepoHealth, len(h.stores))
+	for store, state := range h.stores {
+		if pick(store) {
+			stores[store] = *state
+		}
+	}
+	names := make([]string, 0, len(h.checks))
+	checks := make(map[string]func(ctx context.Context) error, len(h.checks))
+	for name, check := range h.checks {
+		if pick(name) {
+			names = append(names, name)
+			checks[name] = check
+		}
+	}
+	h.mutex.RUnlock()
+	sort.Strings(names)
+
+	errs := append(down("repo", repos), down("redis", stores)...)
+	for _, name := range names {
+		if err := checks[name](ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s is unreachable: %w", name, err))
+		}
+	}
+	for _, dep := range deps {
+		if !known[dep] {
+			errs = append(errs, fmt.Errorf("%s has not been pinged yet", dep))
+		}
+	}
+	return errs
+}
+
+// Ready returns an error naming every repository or Redis store that has not
+// been pinged yet or whose last ping failed, and every failing check. Only the
+// named dependencies are considered when deps are given.
+func (h *Health) Ready(ctx context.Context, deps ...string) error {
+	return errors.Join(h.problems(ctx, deps...)...)
+}
+
+type readiness struct {
+	Status string   `json:"status"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// LiveHandler answers /healthz: the process runs and serves HTTP.
+func (h *Health) LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeReadiness(w, http.StatusOK, readiness{Status: "ok"})
+	})
+}
+
+// ReadyHandler answers /readyz with 503 and the failing dependencies while
+// the service cannot handle requests.
+func (h *Health) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+		defer cancel()
+
+		errs := h.problems(ctx)
+		if len(errs) == 0 {
+			writeReadiness(w, http.StatusOK, readiness{Status: "ok"})
+			return
+		}
+
+		report := readiness{Status: "unavailable"}
+		for _, err := range errs {
+			report.Errors = append(report.Errors, err.Error())
+		}
+		writeReadiness(w, http.StatusServiceUnavailable, report)
+	})
+}
+
+func writeReadiness(w http.ResponseWriter, status int, report readiness) {
+	body, err := json.Marshal(report)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestHealth(t *testing.T) {
+	mt, err := NewMetrics("test", prometheus.NewRegistry(), configs.MetricsCfg{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	health := mt.Health()
+
+	health.Report("film", nil)
+	health.Report("genre", errors.New("connection refused"))
+	health.Report("genre", errors.New("connection refused"))
+
+	if err := health.Ready(context.Background()); err == nil {
+		t.Errorf("wanted genre to make the service unready")
+	}
+	if failures := testutil.ToFloat64(health.failures.WithLabelValues("genre")); failures != 2 {
+		t.Errorf("wanted 2 consecutive failures, got %v", failures)
+	}
+	if up := testutil.ToFloat64(health.up.WithLabelValues("film")); up != 1 {
+		t.Errorf("wanted film up, got %v", up)
+	}
+
+	health.Report("genre", nil)
+	if err := health.Ready(context.Background()); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+	if state := health.Repos()["genre"]; state.Failures != 0 || state.LastSuccess.IsZero() {
+		t.Errorf("wanted genre recovered, got %+v", state)
+	}
+}
+
+func TestReadyDeps(t *testing.T) {
+	mt, err := NewMetrics("test", prometheus.NewRegistry(), configs.MetricsCfg{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	health := mt.Health()
+	session := mt.Redis("session")
+	csrf := mt.Redis("csrf")
+	health.watch(health.repos, "profile")
+
+	testCases := []struct {
+		name   string
+		change func()
+		err    string
+	}{
+		{"Nothing pinged", func() {}, "repo profile has not been pinged yet\nredis session has not been pinged yet"},
+		{"Pinged", func() {
+			health.Report("profile", nil)
+			session.Ping(time.Now(), nil)
+		}, ""},
+		{"Other store down", func() { csrf.Ping(time.Now(), errors.New("connection refused")) }, ""},
+		{"Store down", func() { session.Ping(time.Now(), errors.New("connection refused")) }, "redis session is down after 1 failed pings"},
+	}
+	for _, tc := range testCases {
+		tc.change()
+		err := health.Ready(context.Background(), "session", "profile")
+		if got := fmt.Sprint(err); (err == nil) != (tc.err == "") || err != nil && got != tc.err {
+			t.Errorf("%s: error %v, wanted %q", tc.name, err, tc.err)
+		}
+	}
+
+	if err := health.Ready(context.Background(), "user"); err == nil || err.Error() != "user has not been pinged yet" {
+		t.Errorf("unknown dependency: error %v", err)
+	}
+	if err := health.Ready(context.Background()); err == nil {
+		t.Errorf("wanted csrf to make the whole service unready")
+	}
+}
+
+func TestReadyHandler(t *testing.T) {
+	mt, err := NewMetrics("test", prometheus.NewRegistry(), configs.MetricsCfg{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	health := mt.Health()
+	session := mt.Redis("session")
+	var authErr error
+	health.AddCheck("auth", func(ctx context.Context) error { return authErr })
+
+	testCases := []struct {
+		name   string
+		change func()
+		status int
+		errors []string
+	}{
+		{"Store not pinged", func() {}, http.StatusServiceUnavailable, []string{"redis session has not been pinged yet"}},
+		{"Repo down", func() {
+			session.Ping(time.Now(), nil)
+			health.Report("profile", errors.New("connection refused"))
+		}, http.StatusServiceUnavailable, []string{"repo profile is down after 1 failed pings"}},
+		{"Store down", func() {
+			health.Report("profile", nil)
+			session.Ping(time.Now(), errors.New("connection refused"))
+		}, http.StatusServiceUnavailable, []string{"redis session is down after 1 failed pings"}},
+		{"Check fails", func() {
+			session.Ping(time.Now(), nil)
+			authErr = errors.New("connection refused")
+		}, http.StatusServiceUnavailable, []string{"auth is unreachable: connection refused"}},
+		{"Recovered", func() { authErr = nil }, http.StatusOK, nil},
+	}
+
+	for _, tc := range testCases {
+		tc.change()
+		w := httptest.NewRecorder()
+		health.ReadyHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		if w.Code != tc.status {
+			t.Errorf("%s: status %d, wanted %d", tc.name, w.Code, tc.status)
+		}
+		var report readiness
+		if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+			t.Fatalf("%s: bad report %q: %s", tc.name, w.Body.String(), err)
+		}
+		if strings.Join(report.Errors, "; ") != strings.Join(tc.errors, "; ") {
+			t.Errorf("%s: errors %v, wanted %v", tc.name, report.Errors, tc.errors)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	health.Report("profile", errors.New("connection refused"))
+	health.LiveHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("liveness depends on the database: %d", w.Code)
+	}
+}
@@ -1,31 +1,155 @@
 package metrics
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
 )
 
+const namespace = "moviehub"
+
+var tracer = otel.Tracer("github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics")
+
+// Version is overridden at build time with -ldflags "-X .../metrics.Version=...".
+var Version = "dev"
+
 type Metrics struct {
-	Time *prometheus.HistogramVec
-	Hits *prometheus.CounterVec
+	Time         *prometheus.HistogramVec
+	Hits         *prometheus.CounterVec
+	InFlight     prometheus.Gauge
+	RequestSize  *prometheus.HistogramVec
+	ResponseSize *prometheus.HistogramVec
+
+	legacyTime *prometheus.HistogramVec
+	legacyHits *prometheus.CounterVec
+
+	service       string
+	nativeLatency bool
+
+	routes   *routes
+	dbStats  *dbStatsCollector
+	health   *Health
+	slo      *SLO
+	registry *prometheus.Registry
+	reg      prometheus.Registerer
 }
 
-func GetMetrics() *Metrics {
-	description := []string{"status", "path"}
+func NewMetrics(service string, registry *prometheus.Registry, cfg configs.MetricsCfg) (*Metrics, error) {
+	instance, err := os.Hostname()
+	if err != nil {
+		instance = "unknown"
+	}
+
+	// Prometheus sets instance to the scraped target and would rename ours to
+	// exported_instance, the host gets a label of its own.
+	reg := prometheus.WrapRegistererWith(prometheus.Labels{
+		"service":          service,
+		"service_instance": instance,
+		"version":          Version,
+	}, registry)
+
+	description := []string{"method", "status", "route"}
+
+	latency, err := latencyOpts(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Request work time.",
+	}, cfg.Latency)
+	if err != nil {
+		return nil, fmt.Errorf("metrics latency err: %w", err)
+	}
 
 	metrics := &Metrics{
-		Time: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Time: prometheus.NewHistogramVec(latency, description),
+
+		Hits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Requests served.",
+		}, description),
+
+		InFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "Requests currently being served.",
+		}),
+
+		RequestSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_size_bytes",
+			Help:      "Request body size.",
+			Buckets:   prometheus.ExponentialBuckets(100, 10, 6),
+		}, description),
+
+		ResponseSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_response_size_bytes",
+			Help:      "Response body size.",
+			Buckets:   prometheus.ExponentialBuckets(100, 10, 6),
+		}, description),
+
+		service:       service,
+		nativeLatency: len(latency.Buckets) == 0,
+
+		routes:   newRoutes(cfg.RouteLimit),
+		dbStats:  newDBStatsCollector(),
+		health:   newHealth(reg),
+		registry: registry,
+		reg:      reg,
+	}
+
+	metrics.slo, err = newSLO(service, latency, cfg.Slo, metrics.Hits, metrics.Time)
+	if err != nil {
+		return nil, fmt.Errorf("metrics err: %w", err)
+	}
+
+	registerRuntime(service, registry)
+	reg.MustRegister(metrics.Time, metrics.Hits, metrics.InFlight, metrics.RequestSize, metrics.ResponseSize, metrics.routes.dropped, metrics.dbStats)
+
+	// The old series had no namespace, no service labels and the status and
+	// path labels, dashboards written for them keep working. The path is the
+	// route, so unknown paths no longer make series of their own.
+	if cfg.LegacyNames {
+		legacy := []string{"status", "path"}
+		metrics.legacyTime = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Name:    "Time_Req",
-			Help:    "Request work time.",
+			Help:    "Deprecated: use moviehub_http_request_duration_seconds.",
 			Buckets: prometheus.LinearBuckets(0, 100, 6),
-		}, description),
- 
-		Hits: prometheus.NewCounterVec(prometheus.CounterOpts{
+		}, legacy)
+		metrics.legacyHits = prometheus.NewCounterVec(prometheus.CounterOpts{
 			Name: "Hits_Req",
-			Help: "Step",
-		}, description),
+			Help: "Deprecated: use moviehub_http_requests_total.",
+		}, legacy)
+
+		registry.MustRegister(metrics.legacyTime, metrics.legacyHits)
 	}
 
-	prometheus.MustRegister(metrics.Time, metrics.Hits)
+	return metrics, nil
+}
 
-	return metrics
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
+
+// register adds c to reg or returns the collector already registered with the
+// same descriptors, so components constructed twice share their series.
+func register[T prometheus.Collector](reg prometheus.Registerer, c T) T {
+	err := reg.Register(c)
+	if err == nil {
+		return c
+	}
+
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(T); ok {
+			return existing
+		}
+	}
+	panic(err)
 }
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestServiceLabels(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if _, err := NewMetrics("films", registry, configs.MetricsCfg{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	var labels map[string]string
+	for _, family := range families {
+		if family.GetName() != "moviehub_http_requests_in_flight" {
+			continue
+		}
+		labels = map[string]string{}
+		for _, label := range family.GetMetric()[0].GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+	}
+	if labels == nil {
+		t.Fatalf("moviehub_http_requests_in_flight was not exported")
+	}
+
+	if _, found := labels["instance"]; found {
+		t.Errorf("the host is in instance, the scraped target's label: %v", labels)
+	}
+	if labels["service"] != "films" || labels["service_instance"] == "" {
+		t.Errorf("wanted the service and its host labels, got %v", labels)
+	}
+}
@@ -0,0 +1,162 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ResponseWriter records the status and size of a response. The outermost
+// instrumenting middleware wraps the writer and the ones it calls share the
+// wrapper through CaptureResponse.
+type ResponseWriter struct {
+	http.ResponseWriter
+	status    int
+	appStatus int
+	written   int
+}
+
+func CaptureResponse(w http.ResponseWriter) *ResponseWriter {
+	if rw, ok := w.(*ResponseWriter); ok {
+		return rw
+	}
+	return &ResponseWriter{ResponseWriter: w}
+}
+
+func (w *ResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *ResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.written += n
+	return n, err
+}
+
+func (w *ResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Status is the status of the JSON response envelope if one was reported,
+// the HTTP status otherwise.
+func (w *ResponseWriter) Status() int {
+	switch {
+	case w.appStatus != 0:
+		return w.appStatus
+	case w.status != 0:
+		return w.status
+	default:
+		return http.StatusOK
+	}
+}
+
+func (w *ResponseWriter) Written() int {
+	return w.written
+}
+
+// Panicked records a handler panic as a 500.
+func (w *ResponseWriter) Panicked() {
+	w.appStatus = 0
+	w.status = http.StatusInternalServerError
+}
+
+type countingBody struct {
+	io.ReadCloser
+	read int
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += n
+	return n, err
+}
+
+// StatusReporter is implemented by response writer wrappers that need the
+// status from the JSON response envelope rather than the HTTP one.
+type StatusReporter interface {
+	ReportStatus(status int)
+}
+
+func (w *ResponseWriter) ReportStatus(status int) {
+	w.appStatus = status
+}
+
+// ReportStatus passes the status from the JSON response envelope to every
+// instrumenting wrapper, since handlers answer with HTTP 200 unless a body
+// is refused.
+func ReportStatus(w http.ResponseWriter, status int) {
+	for {
+		if reporter, ok := w.(StatusReporter); ok {
+			reporter.ReportStatus(status)
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return
+		}
+		w = unwrapper.Unwrap()
+	}
+}
+
+func (m *Metrics) Middleware(mx *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		route := m.routes.route(mx, r)
+		rw := CaptureResponse(w)
+		body := &countingBody{ReadCloser: r.Body}
+		if r.Body != nil {
+			r.Body = body
+		}
+
+		m.InFlight.Inc()
+		defer func() {
+			m.InFlight.Dec()
+
+			rec := recover()
+			if rec != nil {
+				rw.Panicked()
+			}
+
+			requestSize := int64(body.read)
+			if r.ContentLength > requestSize {
+				requestSize = r.ContentLength
+			}
+
+			status := rw.Status()
+			code := strconv.Itoa(status)
+			labels := []string{r.Method, code, route}
+			duration := time.Since(start).Seconds()
+			m.slo.tick()
+
+			span := trace.SpanFromContext(r.Context())
+			span.SetAttributes(attribute.Int("http.status_code", status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			observe(r.Context(), m.Time.WithLabelValues(labels...), duration)
+			m.Hits.WithLabelValues(labels...).Inc()
+			if m.legacyTime != nil {
+				m.legacyTime.WithLabelValues(code, route).Observe(duration)
+				m.legacyHits.WithLabelValues(code, route).Inc()
+			}
+			m.RequestSize.WithLabelValues(labels...).Observe(float64(requestSize))
+			m.ResponseSize.WithLabelValues(labels...).Observe(float64(rw.Written()))
+
+			if rec != nil {
+				panic(rec)
+			}
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMiddleware(t *testing.T) {
+	testCases := map[string]struct {
+		handler http.HandlerFunc
+		status  string
+		panics  bool
+	}{
+		"Envelope status": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				ReportStatus(w, http.StatusNotFound)
+				w.Write([]byte("{}"))
+			},
+			status: "404",
+		},
+		"Header status": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			status: "500",
+		},
+		"No write": {
+			handler: func(w http.ResponseWriter, r *http.Request) {},
+			status:  "200",
+		},
+		"Panic": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				panic("handler panic")
+			},
+			status: "500",
+			panics: true,
+		},
+	}
+
+	for name, curr := range testCases {
+		mt, err := NewMetrics("test", prometheus.NewRegistry(), configs.MetricsCfg{})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		mx := http.NewServeMux()
+		mx.HandleFunc("/api/v1/test", curr.handler)
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/test", strings.NewReader("body"))
+		w := httptest.NewRecorder()
+
+		func() {
+			defer func() {
+				if rec := recover(); (rec != nil) != curr.panics {
+					t.Errorf("%s: unexpected panic state: %v", name, rec)
+				}
+			}()
+			mt.Middleware(mx, mx).ServeHTTP(w, r)
+		}()
+
+		hits := testutil.ToFloat64(mt.Hits.WithLabelValues(http.MethodPost, curr.status, "/api/v1/test"))
+		if hits != 1 {
+			t.Errorf("%s: wanted 1 hit with status %s, got %v", name, curr.status, hits)
+		}
+		if inFlight := testutil.ToFloat64(mt.InFlight); inFlight != 0 {
+			t.Errorf("%s: wanted no requests in flight, got %v", name, inFlight)
+		}
+	}
+}
+
+func TestMiddlewareRoutes(t *testing.T) {
+	mt, err := NewMetrics("test", prometheus.NewRegistry(), configs.MetricsCfg{RouteLimit: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	mx := http.NewServeMux()
+	mx.HandleFunc("/api/v1/films", func(w http.ResponseWriter, r *http.Request) {})
+	mx.HandleFunc("/api/v1/film", func(w http.ResponseWriter, r *http.Request) {})
+
+	paths := []string{"/api/v1/films?page=2", "/wp-login.php", "/api/v1/film", "/api/v1/films"}
+	for _, path := range paths {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		mt.Middleware(mx, mx).ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	if hits := testutil.ToFloat64(mt.Hits.WithLabelValues(http.MethodGet, "200", "/api/v1/films")); hits != 2 {
+		t.Errorf("wanted 2 hits for /api/v1/films, got %v", hits)
+	}
+	if hits := testutil.ToFloat64(mt.Hits.WithLabelValues(http.MethodGet, "404", otherRoute)); hits != 1 {
+		t.Errorf("wanted 1 unmatched hit, got %v", hits)
+	}
+	if hits := testutil.ToFloat64(mt.Hits.WithLabelValues(http.MethodGet, "200", otherRoute)); hits != 1 {
+		t.Errorf("wanted 1 hit over budget, got %v", hits)
+	}
+	if dropped := testutil.ToFloat64(mt.routes.dropped); dropped != 1 {
+		t.Errorf("wanted 1 dropped label, got %v", dropped)
+	}
+}
+
+func TestLegacyNames(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	mt, err := NewMetrics("test", registry, configs.MetricsCfg{LegacyNames: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	mx := http.NewServeMux()
+	mx.HandleFunc("/api/v1/films", func(w http.ResponseWriter, r *http.Request) {})
+	mt.Middleware(mx, mx).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/films?page=2", nil))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	found := map[string]bool{}
+	for _, family := range families {
+		if family.GetName() != "Time_Req" && family.GetName() != "Hits_Req" {
+			continue
+		}
+		found[family.GetName()] = true
+		var labels []string
+		for _, label := range family.GetMetric()[0].GetLabel() {
+			labels = append(labels, label.GetName()+"="+label.GetValue())
+		}
+		if got := strings.Join(labels, ","); got != "path=/api/v1/films,status=200" {
+			t.Errorf("%s: labels %s, wanted the old path and status only", family.GetName(), got)
+		}
+	}
+	if !found["Time_Req"] || !found["Hits_Req"] {
+		t.Errorf("wanted the old series, got %v", found)
+	}
+}
+
+func TestCaptureResponse(t *testing.T) {
+	rw := CaptureResponse(httptest.NewRecorder())
+	if CaptureResponse(rw) != rw {
+		t.Errorf("an instrumented writer was wrapped again")
+	}
+
+	ReportStatus(rw, http.StatusNotFound)
+	rw.Write([]byte("{}"))
+	if rw.Status() != http.StatusNotFound || rw.Written() != 2 {
+		t.Errorf("status %d and %d bytes, expected 404 and 2", rw.Status(), rw.Written())
+	}
+	rw.Panicked()
+	if rw.Status() != http.StatusInternalServerError {
+		t.Errorf("status %d after a panic, expected 500", rw.Status())
+	}
+}
@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type redisStartKey struct{}
+
+// RedisMetrics is a go-redis hook recording per-command latency and errors for
+// one store, plus the store health reported by its connection check loop.
+type RedisMetrics struct {
+	store    string
+	health   *Health
+	up       *prometheus.GaugeVec
+	ping     *prometheus.GaugeVec
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+func (m *Metrics) Redis(store string) *RedisMetrics {
+	m.health.watch(m.health.stores, store)
+	return &RedisMetrics{
+		store:  store,
+		health: m.health,
+		up: register(m.reg, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "redis_up",
+			Help:      "Whether the last ping to the store succeeded.",
+		}, []string{"store"})),
+
+		ping: register(m.reg, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "redis_ping_duration_seconds",
+			Help:      "Latency of the last ping to the store.",
+		}, []string{"store"})),
+
+		duration: register(m.reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "redis_command_duration_seconds",
+			Help:      "Redis command latency.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 12),
+		}, []string{"store", "command"})),
+
+		errors: register(m.reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "redis_command_errors_total",
+			Help:      "Redis commands that failed, not counting missing keys.",
+		}, []string{"store", "command"})),
+	}
+}
+
+func (r *RedisMetrics) Ping(start time.Time, err error) {
+	up := 0.0
+	if err == nil {
+		up = 1
+	}
+	r.up.WithLabelValues(r.store).Set(up)
+	r.ping.WithLabelValues(r.store).Set(time.Since(start).Seconds())
+	r.health.reportStore(r.store, err)
+}
+
+func (r *RedisMetrics) observe(ctx context.Context, command string, err error) {
+	if start, ok := ctx.Value(redisStartKey{}).(time.Time); ok {
+		observe(ctx, r.duration.WithLabelValues(r.store, command), time.Since(start).Seconds())
+	}
+	if err != nil && !errors.Is(err, redis.Nil) {
+		r.errors.WithLabelValues(r.store, command).Inc()
+	}
+}
+
+func (r *RedisMetrics) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, redisStartKey{}, time.Now()), nil
+}
+
+func (r *RedisMetrics) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	r.observe(ctx, cmd.Name(), cmd.Err())
+	return nil
+}
+
+func (r *RedisMetrics) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, redisStartKey{}, time.Now()), nil
+}
+
+func (r *RedisMetrics) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmdErr := cmd.Err(); cmdErr != nil && !errors.Is(cmdErr, redis.Nil) {
+			err = cmdErr
+			break
+		}
+	}
+	r.observe(ctx, "pipeline", err)
+	return nil
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRedis(t *testing.T) {
+	mt, err := NewMetrics("test", prometheus.NewRegistry(), configs.MetricsCfg{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	rm := mt.Redis("session")
+
+	testCases := map[string]struct {
+		err error
+	}{
+		"Ok":          {err: nil},
+		"Missing key": {err: redis.Nil},
+		"Error":       {err: errors.New("connection refused")},
+	}
+
+	for _, curr := range testCases {
+		cmd := redis.NewStringCmd(context.Background(), "get", "sid")
+		ctx, _ := rm.BeforeProcess(context.Background(), cmd)
+		cmd.SetErr(curr.err)
+		_ = rm.AfterProcess(ctx, cmd)
+	}
+
+	if count := testutil.CollectAndCount(rm.duration); count != 1 {
+		t.Errorf("wanted latency for 1 command, got %d", count)
+	}
+	if errs := testutil.ToFloat64(rm.errors.WithLabelValues("session", "get")); errs != 1 {
+		t.Errorf("wanted 1 error, got %v", errs)
+	}
+
+	rm.Ping(time.Now(), errors.New("connection refused"))
+	if up := testutil.ToFloat64(rm.up.WithLabelValues("session")); up != 0 {
+		t.Errorf("wanted store down, got %v", up)
+	}
+	rm.Ping(time.Now(), nil)
+	if up := testutil.ToFloat64(rm.up.WithLabelValues("session")); up != 1 {
+		t.Errorf("wanted store up, got %v", up)
+	}
+}
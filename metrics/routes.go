@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	otherRoute        = "other"
+	defaultRouteLimit = 64
+)
+
+// routes maps requests to the patterns registered on a ServeMux and keeps the
+// number of distinct route label values within the configured budget.
+type routes struct {
+	limit   int
+	mutex   sync.Mutex
+	seen    map[string]struct{}
+	dropped prometheus.Counter
+}
+
+func newRoutes(limit int) *routes {
+	if limit <= 0 {
+		limit = defaultRouteLimit
+	}
+
+	return &routes{
+		limit: limit,
+		seen:  make(map[string]struct{}, limit),
+		dropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_route_labels_dropped_total",
+			Help:      "Requests labeled as \"other\" because the route label budget was exhausted.",
+		}),
+	}
+}
+
+func (rt *routes) route(mx *http.ServeMux, r *http.Request) string {
+	_, pattern := mx.Handler(r)
+	if pattern == "" {
+		return otherRoute
+	}
+
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
+
+	if _, found := rt.seen[pattern]; found {
+		return pattern
+	}
+	if len(rt.seen) >= rt.limit {
+		rt.dropped.Inc()
+		return otherRoute
+	}
+	rt.seen[pattern] = struct{}{}
+
+	return pattern
+}
@@ -0,0 +1,392 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"gopkg.in/yaml.v2"
+)
+
+type sloWindow struct {
+	name     string
+	duration time.Duration
+}
+
+// Multi-window burn rate alerting: a page needs both the long and the short
+// window to burn faster than the factor, so alerts also resolve quickly.
+var (
+	sloWindows = []sloWindow{
+		{"5m", 5 * time.Minute},
+		{"30m", 30 * time.Minute},
+		{"1h", time.Hour},
+		{"6h", 6 * time.Hour},
+	}
+	sloAlerts = []struct {
+		severity string
+		long     string
+		short    string
+		factor   float64
+	}{
+		{"page", "1h", "5m", 14.4},
+		{"ticket", "6h", "30m", 6},
+	}
+)
+
+const sloSlots = 6 * 60
+
+// sloCounts are cumulative request counts of a route as exported by the
+// request metrics.
+type sloCounts struct {
+	total  uint64
+	errors uint64
+	slow   uint64
+}
+
+// sloSnapshot holds the counts of every route with an objective at the start
+// of a minute.
+type sloSnapshot struct {
+	minute int64
+	routes map[string]sloCounts
+}
+
+type sloRoute struct {
+	cfg configs.SloCfg
+	// le is the bucket bound equal to the latency threshold.
+	le float64
+}
+
+// SLO computes error budget burn rates of every route with an objective from
+// the request counter and duration histogram. It keeps a snapshot of their
+// counts per minute for the last six hours to rate them over a window.
+type SLO struct {
+	service string
+	latency prometheus.HistogramOpts
+	routes  map[string]sloRoute
+	hits    *prometheus.CounterVec
+	time    *prometheus.HistogramVec
+	now     func() time.Time
+
+	// minute is the last minute snapshotted, requests in the same minute
+	// skip the lock.
+	minute    atomic.Int64
+	mutex     sync.Mutex
+	snapshots [sloSlots]sloSnapshot
+}
+
+type ObjectiveReport struct {
+	Objective float64            `json:"objective"`
+	Threshold float64            `json:"threshold_seconds,omitempty"`
+	BurnRates map[string]float64 `json:"burn_rates"`
+}
+
+type RouteReport struct {
+	Availability *ObjectiveReport `json:"availability,omitempty"`
+	Latency      *ObjectiveReport `json:"latency,omitempty"`
+}
+
+// newSLO refuses latency thresholds that are not a bucket bound of the
+// duration histogram, the slow requests could not be counted exactly.
+func newSLO(service string, latency prometheus.HistogramOpts, objectives []configs.SloCfg, hits *prometheus.CounterVec, duration *prometheus.HistogramVec) (*SLO, error) {
+	slo := &SLO{
+		service: service,
+		latency: latency,
+		routes:  make(map[string]sloRoute, len(objectives)),
+		hits:    hits,
+		time:    duration,
+		now:     time.Now,
+	}
+	for _, cfg := range objectives {
+		route := sloRoute{cfg: cfg}
+		if cfg.Latency > 0 {
+			found := false
+			for _, bound := range latency.Buckets {
+				if math.Abs(bound-cfg.Latency) <= 1e-9*cfg.Latency {
+					route.le, found = bound, true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("slo %s: latency %v is not a bucket bound of %v", cfg.Route, cfg.Latency, latency.Buckets)
+			}
+		}
+		slo.routes[cfg.Route] = route
+	}
+	return slo, nil
+}
+
+func (m *Metrics) SLO() *SLO {
+	return m.slo
+}
+
+// counts reads the cumulative counts of the routes with an objective.
+func (s *SLO) counts() map[string]sloCounts {
+	result := make(map[string]sloCounts, len(s.routes))
+
+	for _, metric := range collect(s.hits) {
+		labels := labelMap(metric)
+		if _, found := s.routes[labels["route"]]; !found {
+			continue
+		}
+		counts := result[labels["route"]]
+		if status, err := strconv.Atoi(labels["status"]); err == nil && status >= http.StatusInternalServerError {
+			counts.errors += uint64(metric.GetCounter().GetValue())
+		}
+		result[labels["route"]] = counts
+	}
+
+	for _, metric := range collect(s.time) {
+		route, found := s.routes[labelMap(metric)["route"]]
+		if !found {
+			continue
+		}
+		histogram := metric.GetHistogram()
+		counts := result[route.cfg.Route]
+		counts.total += histogram.GetSampleCount()
+		if route.le > 0 {
+			fast := uint64(0)
+			for _, bucket := range histogram.GetBucket() {
+				if bucket.GetUpperBound() == route.le {
+					fast = bucket.GetCumulativeCount()
+				}
+			}
+			counts.slow += histogram.GetSampleCount() - fast
+		}
+		result[route.cfg.Route] = counts
+	}
+	return result
+}
+
+func collect(collector prometheus.Collector) []*dto.Metric {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		collector.Collect(ch)
+		close(ch)
+	}()
+
+	var metrics []*dto.Metric
+	for metric := range ch {
+		written := &dto.Metric{}
+		if err := metric.Write(written); err == nil {
+			metrics = append(metrics, written)
+		}
+	}
+	return metrics
+}
+
+func labelMap(metric *dto.Metric) map[string]string {
+	labels := make(map[string]string, len(metric.GetLabel()))
+	for _, pair := range metric.GetLabel() {
+		labels[pair.GetName()] = pair.GetValue()
+	}
+	return labels
+}
+
+// tick snapshots the counts once a minute. The middleware calls it before a
+// request is counted, so the first request of a minute records its start.
+func (s *SLO) tick() {
+	if len(s.routes) == 0 {
+		return
+	}
+	minute := s.now().Unix() / 60
+	if s.minute.Load() == minute {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	slot := &s.snapshots[minute%sloSlots]
+	if slot.minute != minute || slot.routes == nil {
+		*slot = sloSnapshot{minute: minute, routes: s.counts()}
+	}
+	s.minute.Store(minute)
+}
+
+// since is the oldest snapshot taken after oldest. Without one nothing was
+// counted since then, the current counts are returned.
+func (s *SLO) since(oldest int64, current map[string]sloCounts) map[string]sloCounts {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var base *sloSnapshot
+	for i := range s.snapshots {
+		slot := &s.snapshots[i]
+		if slot.routes != nil && slot.minute > oldest && (base == nil || slot.minute < base.minute) {
+			base = slot
+		}
+	}
+	if base == nil {
+		return current
+	}
+	return base.routes
+}
+
+func burnRate(bad uint64, total uint64, objective float64) float64 {
+	if total == 0 || objective >= 1 {
+		return 0
+	}
+	return float64(bad) / float64(total) / (1 - objective)
+}
+
+// Report computes the burn rates over the last minutes of every window, as
+// the rules of Rules do from the same metrics.
+func (s *SLO) Report() map[string]RouteReport {
+	current := s.counts()
+	minute := s.now().Unix() / 60
+	report := make(map[string]RouteReport, len(s.routes))
+
+	bases := make(map[string]map[string]sloCounts, len(sloWindows))
+	for _, w := range sloWindows {
+		bases[w.name] = s.since(minute-int64(w.duration/time.Minute), current)
+	}
+
+	for route, r := range s.routes {
+		var routeReport RouteReport
+		if r.cfg.Availability > 0 {
+			routeReport.Availability = &ObjectiveReport{Objective: r.cfg.Availability, BurnRates: map[string]float64{}}
+		}
+		if r.cfg.Latency > 0 && r.cfg.LatencyTarget > 0 {
+			routeReport.Latency = &ObjectiveReport{Objective: r.cfg.LatencyTarget, Threshold: r.cfg.Latency, BurnRates: map[string]float64{}}
+		}
+
+		for _, w := range sloWindows {
+			now, base := current[route], bases[w.name][route]
+			total := now.total - base.total
+			if routeReport.Availability != nil {
+				routeReport.Availability.BurnRates[w.name] = burnRate(now.errors-base.errors, total, r.cfg.Availability)
+			}
+			if routeReport.Latency != nil {
+				routeReport.Latency.BurnRates[w.name] = burnRate(now.slow-base.slow, total, r.cfg.LatencyTarget)
+			}
+		}
+		report[route] = routeReport
+	}
+	return report
+}
+
+func (s *SLO) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := json.Marshal(s.Report())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	})
+}
+
+type ruleGroups struct {
+	Groups []ruleGroup `yaml:"groups"`
+}
+
+type ruleGroup struct {
+	Name  string `yaml:"name"`
+	Rules []rule `yaml:"rules"`
+}
+
+type rule struct {
+	Record      string            `yaml:"record,omitempty"`
+	Alert       string            `yaml:"alert,omitempty"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// slowRatio is the PromQL share of requests slower than threshold, a bucket
+// bound of the duration histogram.
+func (s *SLO) slowRatio(selector string, threshold float64, window string) string {
+	duration := namespace + "_http_request_duration_seconds"
+	return fmt.Sprintf("1 - sum(rate(%s_bucket{%s,le=\"%s\"}[%s])) / sum(rate(%s_count{%s}[%s]))",
+		duration, selector, strconv.FormatFloat(threshold, 'g', -1, 64), window, duration, selector, window)
+}
+
+// Rules renders Prometheus recording and alerting rules that compute the same
+// burn rates as Report from the exported request metrics.
+func (s *SLO) Rules() ([]byte, error) {
+	return yaml.Marshal(ruleGroups{Groups: []ruleGroup{s.ruleGroup()}})
+}
+
+func (s *SLO) ruleGroup() ruleGroup {
+	routes := make([]string, 0, len(s.routes))
+	for route := range s.routes {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+
+	hits := namespace + "_http_requests_total"
+	group := ruleGroup{Name: fmt.Sprintf("%s-%s-slo", namespace, s.service)}
+
+	for _, route := range routes {
+		cfg := s.routes[route].cfg
+		selector := fmt.Sprintf("service=%q,route=%q", s.service, route)
+		labels := map[string]string{"service": s.service, "route": route}
+
+		type objective struct {
+			name   string
+			target float64
+			ratio  func(window string) string
+		}
+		var objectives []objective
+		if cfg.Availability > 0 {
+			objectives = append(objectives, objective{"availability", cfg.Availability, func(window string) string {
+				return fmt.Sprintf("sum(rate(%s{%s,status=~\"5..\"}[%s])) / sum(rate(%s{%s}[%s]))",
+					hits, selector, window, hits, selector, window)
+			}})
+		}
+		if cfg.Latency > 0 && cfg.LatencyTarget > 0 {
+			objectives = append(objectives, objective{"latency", cfg.LatencyTarget, func(window string) string {
+				return s.slowRatio(selector, s.routes[route].le, window)
+			}})
+		}
+
+		for _, obj := range objectives {
+			record := func(window string) string {
+				return fmt.Sprintf("%s:slo_%s_errors:ratio_rate%s", namespace, obj.name, window)
+			}
+			for _, w := range sloWindows {
+				group.Rules = append(group.Rules, rule{Record: record(w.name), Expr: obj.ratio(w.name), Labels: labels})
+			}
+
+			budget := strconv.FormatFloat(1-obj.target, 'g', 6, 64)
+			for _, alert := range sloAlerts {
+				factor := strconv.FormatFloat(alert.factor, 'g', -1, 64)
+				group.Rules = append(group.Rules, rule{
+					Alert: fmt.Sprintf("SLO%sBurn", strings.ToUpper(obj.name[:1])+obj.name[1:]),
+					Expr: fmt.Sprintf("%s{%s} > (%s * %s) and %s{%s} > (%s * %s)",
+						record(alert.long), selector, factor, budget, record(alert.short), selector, factor, budget),
+					Labels: map[string]string{"service": s.service, "route": route, "severity": alert.severity},
+					Annotations: map[string]string{
+						"summary": fmt.Sprintf("%s %s burns the %s error budget %sx too fast", s.service, route, obj.name, factor),
+					},
+				})
+			}
+		}
+	}
+
+	return group
+}
+
+func (s *SLO) RulesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := s.Rules()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		_, _ = w.Write(body)
+	})
+}
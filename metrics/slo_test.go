@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/yaml.v2"
+)
+
+func TestSLO(t *testing.T) {
+	mt, err := NewMetrics("films", prometheus.NewRegistry(), configs.MetricsCfg{
+		Slo: []configs.SloCfg{{Route: "/api/v1/film", Availability: 0.99, Latency: 0.32, LatencyTarget: 0.9}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	slo := mt.SLO()
+
+	now := time.Unix(1700000000, 0)
+	slo.now = func() time.Time { return now }
+
+	request := func(route string, status int, duration float64) {
+		slo.tick()
+		labels := []string{http.MethodGet, strconv.Itoa(status), route}
+		mt.Time.WithLabelValues(labels...).Observe(duration)
+		mt.Hits.WithLabelValues(labels...).Inc()
+	}
+	request("/api/v1/film", http.StatusOK, 0.01)
+	now = now.Add(time.Hour)
+	for i := 0; i < 7; i++ {
+		request("/api/v1/film", http.StatusOK, 0.01)
+	}
+	request("/api/v1/film", http.StatusInternalServerError, 0.01)
+	request("/api/v1/film", http.StatusNotFound, 1)
+	request("/api/v1/films", http.StatusInternalServerError, 0.01)
+
+	testCases := map[string]struct {
+		window       string
+		availability float64
+		latency      float64
+	}{
+		"Short window": {window: "5m", availability: 100.0 / 9, latency: 10.0 / 9},
+		"Long window":  {window: "6h", availability: 10, latency: 1},
+	}
+
+	report := slo.Report()
+	if _, found := report["/api/v1/films"]; found {
+		t.Errorf("unexpected report for a route without objectives")
+	}
+	for name, curr := range testCases {
+		got := report["/api/v1/film"]
+		if math.Abs(got.Availability.BurnRates[curr.window]-curr.availability) > 1e-9 {
+			t.Errorf("%s: wanted availability burn %v, got %v", name, curr.availability, got.Availability.BurnRates[curr.window])
+		}
+		if math.Abs(got.Latency.BurnRates[curr.window]-curr.latency) > 1e-9 {
+			t.Errorf("%s: wanted latency burn %v, got %v", name, curr.latency, got.Latency.BurnRates[curr.window])
+		}
+	}
+
+	now = now.Add(10 * time.Minute)
+	request("/api/v1/film", http.StatusOK, 0.01)
+	if burn := slo.Report()["/api/v1/film"].Availability.BurnRates["5m"]; burn != 0 {
+		t.Errorf("wanted old requests out of the short window, got %v", burn)
+	}
+
+	rules, err := slo.Rules()
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	var parsed ruleGroups
+	if err := yaml.Unmarshal(rules, &parsed); err != nil {
+		t.Fatalf("rules are not valid yaml: %s", err)
+	}
+	if count := len(parsed.Groups[0].Rules); count != 2*(len(sloWindows)+len(sloAlerts)) {
+		t.Errorf("unexpected number of rules %d", count)
+	}
+	if !strings.Contains(string(rules), `le="0.32"`) {
+		t.Errorf("wanted the latency threshold as bucket bound")
+	}
+
+	_, err = NewMetrics("films", prometheus.NewRegistry(), configs.MetricsCfg{
+		Slo: []configs.SloCfg{{Route: "/api/v1/film", Latency: 0.3, LatencyTarget: 0.9}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "latency 0.3 is not a bucket bound") {
+		t.Errorf("latency between bucket bounds: %v", err)
+	}
+}
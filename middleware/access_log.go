@@ -0,0 +1,250 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
+)
+
+const accessKey contextKey = "accessLog"
+
+type accessEntry struct {
+	userId  uint64
+	hasUser bool
+}
+
+// AccessLog writes one record per request to its own handler. Records are
+// sampled per status code ("404") or class ("4xx"); statuses without a rate
+// are always logged.
+type AccessLog struct {
+	lg       *slog.Logger
+	closer   io.Closer
+	settings atomic.Pointer[accessSettings]
+	random   func() float64
+}
+
+// accessSettings may be swapped while requests are served.
+type accessSettings struct {
+	sampling map[string]float64
+	trusted  []*net.IPNet
+}
+
+// GetAccessLog masks the records with the service log's redact settings.
+func GetAccessLog(cfg configs.AccessLogCfg, redact configs.RedactCfg) (*AccessLog, error) {
+	if !cfg.Enabled {
+		return &AccessLog{}, nil
+	}
+
+	settings, err := getAccessSettings(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := logging.Writer(cfg.Output, cfg.File, cfg.Rotation)
+	if err != nil {
+		return nil, fmt.Errorf("access log err: %w", err)
+	}
+
+	handler, err := logging.Handler(out, cfg.Format, nil)
+	if err == nil {
+		handler, err = logging.Redact(handler, redact)
+	}
+	if err != nil {
+		out.Close()
+		return nil, fmt.Errorf("access log err: %w", err)
+	}
+
+	a := &AccessLog{
+		lg:     slog.New(handler),
+		closer: out,
+		random: rand.Float64,
+	}
+	a.settings.Store(settings)
+	return a, nil
+}
+
+func getAccessSettings(cfg configs.AccessLogCfg) (*accessSettings, error) {
+	for key, rate := range cfg.Sampling {
+		if !validSamplingKey(key) {
+			return nil, fmt.Errorf("access log err: bad sampling key %q", key)
+		}
+		if rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("access log err: sampling rate of %s is out of [0, 1]", key)
+		}
+	}
+
+	trusted := make([]*net.IPNet, 0, len(cfg.TrustedProxies))
+	for _, proxy := range cfg.TrustedProxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("access log err: %w", err)
+		}
+		trusted = append(trusted, network)
+	}
+
+	return &accessSettings{sampling: cfg.Sampling, trusted: trusted}, nil
+}
+
+// Check reports the error Reconfigure would fail with, without applying cfg.
+func (a *AccessLog) Check(cfg configs.AccessLogCfg) error {
+	if a.lg == nil {
+		return nil
+	}
+	_, err := getAccessSettings(cfg)
+	return err
+}
+
+// Reconfigure swaps in the sampling rates and trusted proxies of cfg. Turning
+// the access log on or off and its output need a restart.
+func (a *AccessLog) Reconfigure(cfg configs.AccessLogCfg) error {
+	if a.lg == nil {
+		return nil
+	}
+
+	settings, err := getAccessSettings(cfg)
+	if err != nil {
+		return err
+	}
+	a.settings.Store(settings)
+	return nil
+}
+
+func validSamplingKey(key string) bool {
+	if len(key) != 3 {
+		return false
+	}
+	if strings.HasSuffix(key, "xx") {
+		return key[0] >= '1' && key[0] <= '5'
+	}
+	code, err := strconv.Atoi(key)
+	return err == nil && code >= 100 && code <= 599
+}
+
+func (a *AccessLog) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+func (s *accessSettings) sampled(status int, random func() float64) bool {
+	rate, found := s.sampling[strconv.Itoa(status)]
+	if !found {
+		rate, found = s.sampling[strconv.Itoa(status/100)+"xx"]
+	}
+	if !found || rate >= 1 {
+		return true
+	}
+	return random() < rate
+}
+
+func (s *accessSettings) isTrusted(ip net.IP) bool {
+	for _, network := range s.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP takes X-Forwarded-For into account only when the peer is a trusted
+// proxy, and then picks the rightmost address not added by one.
+func (s *accessSettings) remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	peer := net.ParseIP(host)
+	if peer == nil || !s.isTrusted(peer) {
+		return host
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		ip := net.ParseIP(hop)
+		if ip == nil {
+			break
+		}
+		host = hop
+		if !s.isTrusted(ip) {
+			break
+		}
+	}
+	return host
+}
+
+// Middleware logs the requests served by mx. AuthCheck runs deeper in the
+// chain, so it reports the user id through an entry stored in the context.
+func (a *AccessLog) Middleware(mx *http.ServeMux, next http.Handler) http.Handler {
+	if a.lg == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := metrics.CaptureResponse(w)
+		entry := &accessEntry{}
+		ctx := context.WithValue(r.Context(), accessKey, entry)
+
+		defer func() {
+			rec := recover()
+			if rec != nil {
+				rw.Panicked()
+			}
+
+			settings := a.settings.Load()
+			status := rw.Status()
+			if settings.sampled(status, a.random) {
+				_, route := mx.Handler(r)
+				if route == "" {
+					route = "other"
+				}
+
+				attrs := []slog.Attr{
+					slog.String("method", r.Method),
+					slog.String("route", route),
+					slog.String("path", r.URL.Path),
+					slog.Int("status", status),
+					slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+					slog.Int("bytes", rw.Written()),
+					slog.String("remote_ip", settings.remoteIP(r)),
+					slog.String("user_agent", r.UserAgent()),
+				}
+				if entry.hasUser {
+					attrs = append(attrs, slog.Uint64("user_id", entry.userId))
+				}
+				if id := logging.RequestID(ctx); id != "" {
+					attrs = append(attrs, slog.String("request_id", id))
+				}
+				a.lg.LogAttrs(ctx, slog.LevelInfo, "access", attrs...)
+			}
+
+			if rec != nil {
+				panic(rec)
+			}
+		}()
+
+		next.ServeHTTP(rw, r.WithContext(ctx))
+	})
+}
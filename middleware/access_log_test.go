@@ -0,0 +1,233 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
+)
+
+func TestAccessLog(t *testing.T) {
+	_, proxies, _ := net.ParseCIDR("10.0.0.0/8")
+
+	testCases := map[string]struct {
+		handler    http.HandlerFunc
+		remoteAddr string
+		forwarded  string
+		sampling   map[string]float64
+		logged     bool
+		status     float64
+		remoteIP   string
+		userId     float64
+	}{
+		"Envelope status": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				metrics.ReportStatus(w, http.StatusNotFound)
+				w.Write([]byte("{}"))
+			},
+			remoteAddr: "192.0.2.1:5000",
+			logged:     true,
+			status:     404,
+			remoteIP:   "192.0.2.1",
+		},
+		"Untrusted forwarded for": {
+			handler:    func(w http.ResponseWriter, r *http.Request) {},
+			remoteAddr: "192.0.2.1:5000",
+			forwarded:  "198.51.100.7",
+			logged:     true,
+			status:     200,
+			remoteIP:   "192.0.2.1",
+		},
+		"Trusted forwarded for": {
+			handler:    func(w http.ResponseWriter, r *http.Request) {},
+			remoteAddr: "10.0.0.2:5000",
+			forwarded:  "203.0.113.9, 198.51.100.7, 10.0.0.1",
+			logged:     true,
+			status:     200,
+			remoteIP:   "198.51.100.7",
+		},
+		"User id": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				entry := r.Context().Value(accessKey).(*accessEntry)
+				entry.userId, entry.hasUser = 42, true
+			},
+			remoteAddr: "192.0.2.1:5000",
+			logged:     true,
+			status:     200,
+			remoteIP:   "192.0.2.1",
+			userId:     42,
+		},
+		"Sampled out": {
+			handler:    func(w http.ResponseWriter, r *http.Request) {},
+			remoteAddr: "192.0.2.1:5000",
+			sampling:   map[string]float64{"2xx": 0.1},
+		},
+		"Exact code beats class": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			remoteAddr: "192.0.2.1:5000",
+			sampling:   map[string]float64{"4xx": 0, "404": 1},
+			logged:     true,
+			status:     404,
+			remoteIP:   "192.0.2.1",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			al := &AccessLog{
+				lg:     slog.New(slog.NewJSONHandler(&buf, nil)),
+				random: func() float64 { return 0.5 },
+			}
+			al.settings.Store(&accessSettings{sampling: tc.sampling, trusted: []*net.IPNet{proxies}})
+
+			mx := http.NewServeMux()
+			mx.Handle("/api/v1/films", tc.handler)
+
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/films", nil)
+			r.RemoteAddr = tc.remoteAddr
+			if tc.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tc.forwarded)
+			}
+			al.Middleware(mx, mx).ServeHTTP(httptest.NewRecorder(), r)
+
+			if !tc.logged {
+				if buf.Len() != 0 {
+					t.Errorf("unexpected record: %s", buf.String())
+				}
+				return
+			}
+
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("bad record %q: %s", buf.String(), err)
+			}
+			if record["route"] != "/api/v1/films" {
+				t.Errorf("wrong route: %v", record["route"])
+			}
+			if record["status"] != tc.status {
+				t.Errorf("wrong status: %v, expected %v", record["status"], tc.status)
+			}
+			if record["remote_ip"] != tc.remoteIP {
+				t.Errorf("wrong remote ip: %v, expected %v", record["remote_ip"], tc.remoteIP)
+			}
+			if tc.userId != 0 && record["user_id"] != tc.userId {
+				t.Errorf("wrong user id: %v, expected %v", record["user_id"], tc.userId)
+			}
+			if _, found := record["user_id"]; tc.userId == 0 && found {
+				t.Errorf("unexpected user id: %v", record["user_id"])
+			}
+		})
+	}
+}
+
+func TestGetAccessLog(t *testing.T) {
+	testCases := map[string]struct {
+		cfg configs.AccessLogCfg
+		err bool
+	}{
+		"Disabled": {cfg: configs.AccessLogCfg{Sampling: map[string]float64{"bad": 2}}},
+		"Bad sampling key": {
+			cfg: configs.AccessLogCfg{Enabled: true, Sampling: map[string]float64{"2x": 0.5}},
+			err: true,
+		},
+		"Bad sampling rate": {
+			cfg: configs.AccessLogCfg{Enabled: true, Sampling: map[string]float64{"2xx": 1.5}},
+			err: true,
+		},
+		"Bad proxy": {
+			cfg: configs.AccessLogCfg{Enabled: true, TrustedProxies: []string{"proxy"}},
+			err: true,
+		},
+		"Bad format": {
+			cfg: configs.AccessLogCfg{Enabled: true, Output: "stderr", Format: "xml"},
+			err: true,
+		},
+		"Ok": {
+			cfg: configs.AccessLogCfg{
+				Enabled:        true,
+				Output:         "stderr",
+				Sampling:       map[string]float64{"2xx": 0.1, "404": 1},
+				TrustedProxies: []string{"127.0.0.1", "10.0.0.0/8", "::1"},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := GetAccessLog(tc.cfg, configs.RedactCfg{})
+			if (err != nil) != tc.err {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestAccessLogRedact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	al, err := GetAccessLog(configs.AccessLogCfg{Enabled: true, Output: "file", File: path},
+		configs.RedactCfg{Keys: []string{"user_agent"}, Patterns: []string{`ticket-[0-9]+`}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	mx := http.NewServeMux()
+	mx.HandleFunc("/api/v1/", func(w http.ResponseWriter, r *http.Request) {})
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/ticket-123", nil)
+	r.Header.Set("User-Agent", "agent of user@example.com")
+	al.Middleware(mx, mx).ServeHTTP(httptest.NewRecorder(), r)
+	if err := al.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var record map[string]any
+	if err := json.Unmarshal(body, &record); err != nil {
+		t.Fatalf("bad record %q: %s", body, err)
+	}
+	if record["path"] != "/api/v1/"+logging.Redacted {
+		t.Errorf("path pattern not redacted: %v", record["path"])
+	}
+	if record["user_agent"] != logging.Redacted {
+		t.Errorf("user agent key not redacted: %v", record["user_agent"])
+	}
+}
+
+func TestAccessLogReconfigure(t *testing.T) {
+	al, err := GetAccessLog(configs.AccessLogCfg{Enabled: true, Output: "stderr"}, configs.RedactCfg{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer al.Close()
+
+	if err := al.Check(configs.AccessLogCfg{Sampling: map[string]float64{"2x": 0.5}}); err == nil {
+		t.Errorf("bad sampling key passed the check")
+	}
+	if err := al.Reconfigure(configs.AccessLogCfg{Sampling: map[string]float64{"2x": 0.5}}); err == nil {
+		t.Errorf("bad sampling key was accepted")
+	}
+	if !al.settings.Load().sampled(http.StatusOK, func() float64 { return 0.5 }) {
+		t.Errorf("rejected settings were applied")
+	}
+
+	if err := al.Reconfigure(configs.AccessLogCfg{Sampling: map[string]float64{"2xx": 0.1}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if al.settings.Load().sampled(http.StatusOK, func() float64 { return 0.5 }) {
+		t.Errorf("new sampling rate was not applied")
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/requests"
+)
+
+// BodyLimit caps the request bodies per route of a mux. Bodies announced as
+// too large are refused with 413 before the handler runs, the others fail to
+// be read past the limit and the handler answers 413 then.
+type BodyLimit struct {
+	lg        *slog.Logger
+	multipart []string
+	limits    atomic.Pointer[bodyLimits]
+}
+
+// bodyLimits may be swapped while requests are served.
+type bodyLimits struct {
+	limit  int64
+	routes map[string]int64
+}
+
+// GetBodyLimit limits the multipart routes to cfg.MultipartLimit and the rest
+// to cfg.BodyLimit. Routes in cfg.Routes override both.
+func GetBodyLimit(cfg configs.HttpCfg, lg *slog.Logger, multipart ...string) *BodyLimit {
+	b := &BodyLimit{
+		lg:        lg.With("module", "body_limit"),
+		multipart: multipart,
+	}
+	b.Reconfigure(cfg)
+	return b
+}
+
+func (b *BodyLimit) Reconfigure(cfg configs.HttpCfg) {
+	limits := &bodyLimits{
+		limit:  cfg.BodyLimit,
+		routes: make(map[string]int64, len(b.multipart)+len(cfg.Routes)),
+	}
+	for _, route := range b.multipart {
+		limits.routes[route] = cfg.MultipartLimit
+	}
+	for route, limit := range cfg.Routes {
+		limits.routes[route] = limit
+	}
+	b.limits.Store(limits)
+}
+
+func (b *BodyLimit) Limit(route string) int64 {
+	limits := b.limits.Load()
+	if limit, found := limits.routes[route]; found {
+		return limit
+	}
+	return limits.limit
+}
+
+func (b *BodyLimit) Middleware(mx *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, route := mx.Handler(r)
+		limit := b.Limit(route)
+
+		if r.ContentLength > limit {
+			logging.FromContext(r.Context(), b.lg).Warn("request body too large", "route", route, "length", r.ContentLength, "limit", limit)
+			w.Header().Set("Connection", "close")
+			requests.SendResponse(w, requests.Response{Status: http.StatusRequestEntityTooLarge}, b.lg)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+
+		next.ServeHTTP(w, r)
+	})
+}
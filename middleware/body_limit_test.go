@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/requests"
+)
+
+func TestBodyLimit(t *testing.T) {
+	testCases := map[string]struct {
+		route   string
+		size    int
+		chunked bool
+		served  bool
+		status  int
+	}{
+		"Within limit": {
+			route:  "/api/v1/find",
+			size:   10,
+			served: true,
+			status: http.StatusOK,
+		},
+		"Announced too large": {
+			route:  "/api/v1/find",
+			size:   11,
+			status: http.StatusRequestEntityTooLarge,
+		},
+		"Read too large": {
+			route:   "/api/v1/find",
+			size:    11,
+			chunked: true,
+			served:  true,
+			status:  http.StatusRequestEntityTooLarge,
+		},
+		"Multipart route": {
+			route:  "/api/v1/add/film",
+			size:   100,
+			served: true,
+			status: http.StatusOK,
+		},
+		"Multipart route too large": {
+			route:  "/api/v1/add/film",
+			size:   101,
+			status: http.StatusRequestEntityTooLarge,
+		},
+		"Route override": {
+			route:  "/api/v1/comment/add",
+			size:   50,
+			served: true,
+			status: http.StatusOK,
+		},
+		"Unknown route": {
+			route:  "/nowhere",
+			size:   11,
+			status: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	cfg := configs.HttpCfg{BodyLimit: 10, MultipartLimit: 100, Routes: map[string]int64{"/api/v1/comment/add": 50}}
+	limit := GetBodyLimit(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)), "/api/v1/add/film")
+
+	for name, tc := range testCases {
+		served := false
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			served = true
+			response := requests.Response{Status: http.StatusOK}
+			if _, err := io.ReadAll(r.Body); err != nil {
+				response.Status = requests.BodyStatus(err)
+			}
+			requests.SendResponse(w, response, slog.Default())
+		}
+		mx := http.NewServeMux()
+		for _, route := range []string{"/api/v1/find", "/api/v1/add/film", "/api/v1/comment/add"} {
+			mx.HandleFunc(route, handler)
+		}
+
+		r := httptest.NewRequest(http.MethodPost, tc.route, strings.NewReader(strings.Repeat("a", tc.size)))
+		if tc.chunked {
+			r.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+		limit.Middleware(mx, mx).ServeHTTP(w, r)
+
+		var response requests.Response
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+			continue
+		}
+		if served != tc.served {
+			t.Errorf("%s: served %v, expected %v", name, served, tc.served)
+		}
+		if response.Status != tc.status {
+			t.Errorf("%s: status %d, expected %d", name, response.Status, tc.status)
+		}
+		if code := w.Result().StatusCode; (code == http.StatusRequestEntityTooLarge) != (tc.status == http.StatusRequestEntityTooLarge) {
+			t.Errorf("%s: HTTP status %d, envelope status %d", name, code, tc.status)
+		}
+	}
+	limit.Reconfigure(configs.HttpCfg{BodyLimit: 20, MultipartLimit: 200})
+	if got := limit.Limit("/api/v1/find"); got != 20 {
+		t.Errorf("reconfigured limit %d, expected 20", got)
+	}
+	if got := limit.Limit("/api/v1/add/film"); got != 200 {
+		t.Errorf("reconfigured multipart limit %d, expected 200", got)
+	}
+	if got := limit.Limit("/api/v1/comment/add"); got != 20 {
+		t.Errorf("dropped route override still applies: %d", got)
+	}
+}
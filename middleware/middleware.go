@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/films/usecase"
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/logging"
 )
 
 type contextKey string
@@ -23,11 +24,14 @@ func AuthCheck(next http.Handler, core *usecase.Core, lg *slog.Logger) http.Hand
 
 		userId, err := core.GetUserId(r.Context(), session.Value)
 		if err != nil {
-			lg.Error("auth check error", "err", err.Error())
+			logging.FromContext(r.Context(), lg).Error("auth check error", "err", err.Error())
 			next.ServeHTTP(w, r)
 			return
 		}
 
+		if entry, ok := r.Context().Value(accessKey).(*accessEntry); ok {
+			entry.userId, entry.hasUser = userId, true
+		}
 		r = r.WithContext(context.WithValue(r.Context(), UserIDKey, userId))
 
 		next.ServeHTTP(w, r)
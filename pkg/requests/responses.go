@@ -2,10 +2,9 @@ package requests
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
-	"strconv"
-	"time"
 
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/metrics"
 	"github.com/go-park-mail-ru/2023_2_Vkladyshi/pkg/models"
@@ -71,26 +70,34 @@ type CalendarResponse struct {
 	Days       []models.DayItem `json:"days"`
 }
 
-func sendMetrics(mt *metrics.Metrics, path string, status int, start time.Time) {
-	end := time.Since(start)
-	mt.Time.WithLabelValues(strconv.Itoa(status), path).Observe(end.Seconds())
-	mt.Hits.WithLabelValues(strconv.Itoa(status), path).Inc()
-}
-
-func SendResponse(w http.ResponseWriter, path string, response Response, lg *slog.Logger, mt *metrics.Metrics, start time.Time) {
+func SendResponse(w http.ResponseWriter, response Response, lg *slog.Logger) {
 	jsonResponse, err := json.Marshal(response)
-	sendMetrics(mt, path, response.Status, start)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
-		sendMetrics(mt, path, http.StatusInternalServerError, start)
 		lg.Error("failed to pack json", "err", err.Error())
 		return
 	}
+	metrics.ReportStatus(w, response.Status)
 
 	w.Header().Set("Content-Type", "application/json")
+	// Unlike the other statuses a refused body is sent as the HTTP status
+	// too, clients and proxies act on it without reading the envelope.
+	if response.Status == http.StatusRequestEntityTooLarge {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+	}
 	_, err = w.Write(jsonResponse)
 	if err != nil {
 		lg.Error("failed to send response", "err", err.Error())
 		return
 	}
 }
+
+// BodyStatus is the status to answer with when the request body can not be
+// read: 413 once it runs over the limit of the route, 400 otherwise.
+func BodyStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
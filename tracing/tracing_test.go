@@ -0,0 +1,79 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-park-mail-ru/2023_2_Vkladyshi/configs"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMiddleware(t *testing.T) {
+	shutdown, err := Init("test", configs.TracingCfg{})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	defer shutdown(context.Background())
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	var traceID trace.TraceID
+	mx := http.NewServeMux()
+	mx.HandleFunc("/api/v1/film", func(w http.ResponseWriter, r *http.Request) {
+		traceID = trace.SpanContextFromContext(r.Context()).TraceID()
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/film?id=1", nil)
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	Middleware(mx, mx).ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("wanted 1 span, got %d", len(spans))
+	}
+	if name := spans[0].Name(); name != "GET /api/v1/film" {
+		t.Errorf("unexpected span name %s", name)
+	}
+	if traceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("wanted the trace continued from the request, got %s", traceID)
+	}
+
+	if _, err := Init("test", configs.TracingCfg{Exporter: "zipkin"}); err == nil {
+		t.Errorf("wanted error for an unknown exporter")
+	}
+}
+
+func TestSampleRatio(t *testing.T) {
+	testCases := map[string]struct {
+		ratio   float64
+		sampled bool
+	}{
+		"Sampling off": {ratio: 0, sampled: false},
+		"Everything":   {ratio: 1, sampled: true},
+	}
+
+	for name, tc := range testCases {
+		shutdown, err := Init("test", configs.TracingCfg{
+			Exporter:    ExporterFile,
+			File:        filepath.Join(t.TempDir(), "traces.json"),
+			SampleRatio: tc.ratio,
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error %s", name, err)
+		}
+
+		_, span := otel.Tracer("test").Start(context.Background(), "request")
+		if sampled := span.SpanContext().IsSampled(); sampled != tc.sampled {
+			t.Errorf("%s: sampled %v, wanted %v", name, sampled, tc.sampled)
+		}
+		span.End()
+		shutdown(context.Background())
+	}
+}